@@ -54,7 +54,7 @@ func registerClusterTools(s *SchedulerServer, config SchedulerServerConfig) erro
 		mcp.WithDescription(GetClusterConfigurationDescription),
 	), func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
 		log.Printf("Getting cluster configuration")
-		clusterConfig, err := s.conn.GetClusterConfiguration()
+		clusterConfig, err := s.conn.WithContext(ctx).GetClusterConfiguration()
 		if err != nil {
 			log.Printf("Failed to get cluster configuration: %v", err)
 			return &mcp.CallToolResult{
@@ -186,7 +186,7 @@ func registerClusterTools(s *SchedulerServer, config SchedulerServerConfig) erro
 
 			// Apply the configuration
 			log.Printf("Applying cluster configuration")
-			err = s.conn.ApplyClusterConfiguration(config)
+			err = s.conn.WithContext(ctx).ApplyClusterConfiguration(config)
 			if err != nil {
 				log.Printf("Failed to apply configuration: %v", err)
 				return &mcp.CallToolResult{
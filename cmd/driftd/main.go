@@ -0,0 +1,160 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+// driftd periodically compares the live cluster configuration with a
+// desired state kept in a JSON file or a clusterfile YAML tree, logs
+// drifted objects, posts them to a webhook and, when enabled,
+// reconciles the allowed object kinds:
+//
+//	driftd -config cluster.yaml -environment prod -interval 10m \
+//	    -webhook https://hooks.example.com/drift \
+//	    -reconcile -allow cluster_queue,host_group
+//
+// -once runs a single check and exits with status 2 when drift was
+// found, which suits cron jobs and CI pipelines.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/clusterfile"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/drift"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func main() {
+	os.Exit(run())
+}
+
+// run runs driftd and returns its exit status, so the deferred
+// cleanups like flushing the metrics run before the process exits.
+func run() int {
+	var (
+		configFile   = flag.String("config", "", "desired configuration: JSON file or clusterfile YAML root")
+		environment  = flag.String("environment", "", "environment overlay of a YAML configuration")
+		interval     = flag.Duration("interval", 5*time.Minute, "time between two checks")
+		once         = flag.Bool("once", false, "check once and exit with status 2 on drift")
+		reconcile    = flag.Bool("reconcile", false, "reconcile drifted objects of the allowed kinds")
+		allow        = flag.String("allow", "", "comma-separated kinds which may be reconciled (default all)")
+		deny         = flag.String("deny", "", "comma-separated kinds which are never reconciled")
+		ignore       = flag.String("ignore", "", "comma-separated kinds which are not compared")
+		webhook      = flag.String("webhook", "", "URL the JSON drift reports are posted to")
+		metricsEvery = flag.Duration("metrics-interval", 0, "print metrics to stdout at this interval (0 disables)")
+	)
+	flag.Parse()
+	if *configFile == "" {
+		return failed("-config is required")
+	}
+
+	policy := drift.Policy{Reconcile: *reconcile}
+	var err error
+	if policy.Allow, err = parseKinds(*allow); err != nil {
+		return failed("-allow: %v", err)
+	}
+	if policy.Deny, err = parseKinds(*deny); err != nil {
+		return failed("-deny: %v", err)
+	}
+	if policy.Ignore, err = parseKinds(*ignore); err != nil {
+		return failed("-ignore: %v", err)
+	}
+
+	qc, err := qconf.NewCommandLineQConf(qconf.CommandLineQConfConfig{Executable: "qconf"})
+	if err != nil {
+		return failed("failed to construct qconf client: %v", err)
+	}
+
+	config := drift.Config{
+		QConf:     qc,
+		Desired:   drift.FileSource(*configFile, clusterfile.LoadOptions{Environment: *environment}),
+		Policy:    policy,
+		Interval:  *interval,
+		Notifiers: []drift.Notifier{drift.LogNotifier{}},
+	}
+	if *webhook != "" {
+		config.Notifiers = append(config.Notifiers, drift.WebhookNotifier{URL: *webhook})
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if *metricsEvery > 0 {
+		exporter, err := stdoutmetric.New()
+		if err != nil {
+			return failed("failed to create metrics exporter: %v", err)
+		}
+		provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(
+			sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(*metricsEvery))))
+		defer provider.Shutdown(context.Background())
+		config.Meter = provider.Meter("driftd")
+	}
+
+	detector, err := drift.NewDetector(config)
+	if err != nil {
+		return failed("%v", err)
+	}
+
+	if *once {
+		report := detector.Check(ctx)
+		switch {
+		case report.Error != "":
+			return 1
+		case report.Drifted():
+			return 2
+		}
+		return 0
+	}
+	slog.Info("watching cluster configuration", "config", *configFile, "interval", *interval)
+	if err := detector.Run(ctx); err != nil && ctx.Err() == nil {
+		return failed("%v", err)
+	}
+	return 0
+}
+
+// parseKinds parses a comma-separated list of object kinds.
+func parseKinds(s string) ([]qconf.ObjectKind, error) {
+	var kinds []qconf.ObjectKind
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		kind := qconf.ObjectKind(name)
+		if !slices.Contains(qconf.ObjectKinds(), kind) {
+			return nil, fmt.Errorf("unknown object kind %q", name)
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// failed prints an error message and returns the exit status 1.
+func failed(format string, args ...any) int {
+	fmt.Fprintf(os.Stderr, "driftd: "+format+"\n", args...)
+	return 1
+}
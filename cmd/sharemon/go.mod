@@ -1,6 +1,6 @@
 module github.com/hpc-gridware/go-clusterscheduler/cmd/sharemon
 
-go 1.24.0
+go 1.25.0
 
 replace github.com/hpc-gridware/go-clusterscheduler => ../..
 
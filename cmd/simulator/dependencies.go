@@ -21,110 +21,65 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
 )
 
 // cleanupInvalidPEReferences removes references to parallel environments
-// from queues when those PEs are not defined in the configuration.
-// This prevents errors when comparing configurations.
+// from queues when those PEs are not defined in the configuration,
+// including the ones in host overrides. This prevents errors when
+// applying the configuration. All other reference problems found by
+// ValidateClusterConfig are printed as warnings.
 func cleanupInvalidPEReferences(config *qconf.ClusterConfig) {
-	// Build a set of valid PE names
-	validPEs := make(map[string]bool)
-	for peName := range config.ParallelEnvironments {
-		validPEs[peName] = true
-	}
-
-	// Check each queue and remove invalid PE references
-	for queueName, queue := range config.ClusterQueues {
-		if len(queue.PeList) == 0 {
+	for _, issue := range qconf.ValidateClusterConfig(*config) {
+		if issue.Code != qconf.ConfigCodeUnknownPE || issue.Field != "pe_list" {
+			fmt.Printf("Warning: %s\n", issue.Error())
 			continue
 		}
-
-		// Filter out invalid PE names
-		validPEList := make([]string, 0)
-		for _, peRef := range queue.PeList {
-			// PE references can be in format "pename" or "[host=pename]"
-			// Extract the PE name
-			peName := peRef
-			if len(peRef) > 0 && peRef[0] == '[' {
-				// Skip host-specific PE references as they're harder to parse
-				// and we're being conservative here
-				continue
-			}
-
-			// Only keep if PE is defined
-			if validPEs[peName] {
-				validPEList = append(validPEList, peRef)
-			} else {
-				fmt.Printf("Warning: Removing reference to undefined PE '%s' from queue '%s'\n",
-					peName, queueName)
-			}
-		}
-
-		// Update the queue's PE list
-		if len(validPEList) == 0 {
-			queue.PeList = nil
+		queue := config.ClusterQueues[issue.Name]
+		queue.PeList = removePEReference(queue.PeList, issue.Host, issue.Ref)
+		config.ClusterQueues[issue.Name] = queue
+		if issue.Host != "" {
+			fmt.Printf("Warning: Removing reference to undefined PE '%s' for '%s' from queue '%s'\n",
+				issue.Ref, issue.Host, issue.Name)
 		} else {
-			queue.PeList = validPEList
+			fmt.Printf("Warning: Removing reference to undefined PE '%s' from queue '%s'\n",
+				issue.Ref, issue.Name)
 		}
-		config.ClusterQueues[queueName] = queue
 	}
 }
 
-// removeQueuePEReferences modifies queues in the cluster to remove references
-// to parallel environments that are about to be deleted.
-func removeQueuePEReferences(cs *qconf.CommandLineQConf, currentConfig qconf.ClusterConfig,
-	pesToDelete map[string]qconf.ParallelEnvironmentConfig) error {
-
-	// Build a set of PE names to delete
-	peNamesToDelete := make(map[string]bool)
-	for peName := range pesToDelete {
-		peNamesToDelete[peName] = true
-	}
-
-	// Check each queue in the current configuration
-	for queueName, queue := range currentConfig.ClusterQueues {
-		if len(queue.PeList) == 0 {
+// removePEReference removes peName from a queue's pe_list, either from
+// the default value or, when host is set, from the override for host. An
+// override which is left without PEs is kept as "[host=NONE]".
+func removePEReference(peList []string, host, peName string) []string {
+	result := make([]string, 0, len(peList))
+	for _, entry := range peList {
+		if host == "" {
+			if entry != peName {
+				result = append(result, entry)
+			}
 			continue
 		}
-
-		modified := false
-		newPEList := make([]string, 0)
-
-		for _, peRef := range queue.PeList {
-			// PE references can be in format "pename" or "[host=pename]"
-			peName := peRef
-			if len(peRef) > 0 && peRef[0] == '[' {
-				// For host-specific references, skip for simplicity
-				// Most configs won't use this
-				newPEList = append(newPEList, peRef)
-				continue
-			}
-
-			// Keep PE reference only if it's not being deleted
-			if !peNamesToDelete[peName] {
-				newPEList = append(newPEList, peRef)
-			} else {
-				fmt.Printf("Removing PE reference '%s' from queue '%s' before deletion\n",
-					peName, queueName)
-				modified = true
-			}
+		prefix := "[" + host + "="
+		if !strings.HasPrefix(entry, prefix) || !strings.HasSuffix(entry, "]") {
+			result = append(result, entry)
+			continue
 		}
-
-		// If we modified the queue, update it in the cluster
-		if modified {
-			queue.PeList = newPEList
-			if len(newPEList) == 0 {
-				queue.PeList = nil
-			}
-			err := cs.ModifyClusterQueue(queueName, queue)
-			if err != nil {
-				return fmt.Errorf("failed to modify queue %s to remove PE references: %w",
-					queueName, err)
+		var pes []string
+		for _, pe := range strings.Fields(entry[len(prefix) : len(entry)-1]) {
+			if pe != peName {
+				pes = append(pes, pe)
 			}
 		}
+		if len(pes) == 0 {
+			pes = []string{"NONE"}
+		}
+		result = append(result, prefix+strings.Join(pes, " ")+"]")
 	}
-
-	return nil
+	if len(result) == 0 {
+		return nil
+	}
+	return result
 }
@@ -27,10 +27,17 @@ import (
 )
 
 func dump(cmd *cobra.Command, args []string) {
+	concurrency, err := cmd.Flags().GetInt("concurrency")
+	FatalOnError(err)
+	rate, err := cmd.Flags().GetFloat64("rate")
+	FatalOnError(err)
+
 	cs, err := qconf.NewCommandLineQConf(qconf.CommandLineQConfConfig{
 		Executable: "qconf",
 		// be friendly and prevent too many requests on the qmaster
-		DelayAfter: time.Millisecond * 50,
+		DelayAfter:  time.Millisecond * 50,
+		Concurrency: concurrency,
+		RateLimit:   rate,
 	})
 	FatalOnError(err)
 
@@ -23,16 +23,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/clusterfile"
 	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
 	"github.com/spf13/cobra"
 )
 
 func run(cmd *cobra.Command, args []string) {
 	configFile := args[0]
+	environment, err := cmd.Flags().GetString("environment")
+	FatalOnError(err)
 
-	// Read cluster configuration from JSON file
-	config, err := readClusterConfig(configFile)
+	// Read cluster configuration from JSON or YAML file
+	config, err := readClusterConfig(configFile, environment)
 	FatalOnError(err)
 
 	// Initialize qconf client
@@ -74,9 +78,16 @@ func run(cmd *cobra.Command, args []string) {
 }
 
 // readClusterConfig reads and parses a cluster configuration from a JSON file
-func readClusterConfig(configFile string) (qconf.ClusterConfig, error) {
+// or, for .yaml and .yml files, from a clusterfile configuration tree with
+// the overlay of the given environment.
+func readClusterConfig(configFile, environment string) (qconf.ClusterConfig, error) {
 	var config qconf.ClusterConfig
 
+	switch filepath.Ext(configFile) {
+	case ".yaml", ".yml":
+		return clusterfile.Load(configFile, clusterfile.LoadOptions{Environment: environment})
+	}
+
 	file, err := os.Open(configFile)
 	if err != nil {
 		return config, err
@@ -113,28 +124,17 @@ func applyConfigurationChanges(cs *qconf.CommandLineQConf,
 
 	reconcileExecHosts(cs, comparison)
 
-	// Add new entries
-	if comparison.DiffAdded != nil {
-		_, err := qconf.AddAllEntries(cs, *comparison.DiffAdded)
-		FatalOnError(err)
-	}
-
-	// Modify existing entries
-	if comparison.DiffModified != nil {
-		_, err := qconf.ModifyAllEntries(cs, *comparison.DiffModified)
-		FatalOnError(err)
-	}
+	// Adds and modifications are ordered by the references between the
+	// objects, e.g. host groups are added before the queues using them
+	// and queues are detached from parallel environments which are
+	// deleted afterwards.
+	changes := *comparison
+	changes.DiffRemoved = nil
+	_, err := qconf.ApplyComparison(cs, currentConfig, &changes)
+	FatalOnError(err)
 
-	// Handle deletions with dependency cleanup
+	// Delete removed entries, continuing on errors
 	if comparison.DiffRemoved != nil {
-		// Before deleting PEs, remove references from queues
-		if len(comparison.DiffRemoved.ParallelEnvironments) > 0 {
-			err := removeQueuePEReferences(cs, currentConfig,
-				comparison.DiffRemoved.ParallelEnvironments)
-			PrintOnError(err)
-		}
-
-		// Delete removed entries
 		_, err := qconf.DeleteAllEnries(cs, *comparison.DiffRemoved, true)
 		PrintOnError(err)
 	}
@@ -35,7 +35,7 @@ var rootCmd = &cobra.Command{
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run the cluster simulation",
-	Long:  "Run the cluster simulation using the provided JSON file.",
+	Long:  "Run the cluster simulation using the provided JSON or YAML file.",
 	Args:  cobra.MinimumNArgs(1),
 	Run:   run,
 }
@@ -49,7 +49,10 @@ var dumpCmd = &cobra.Command{
 }
 
 func main() {
+	runCmd.Flags().String("environment", "", "environment overlay of a YAML configuration")
 	rootCmd.AddCommand(runCmd)
+	dumpCmd.Flags().Int("concurrency", 1, "number of qconf calls running in parallel")
+	dumpCmd.Flags().Float64("rate", 0, "maximum number of qconf calls per second (0 is unlimited)")
 	rootCmd.AddCommand(dumpCmd)
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
 	"go.opentelemetry.io/contrib/bridges/otelslog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
@@ -175,6 +176,18 @@ var DestructiveQConfMethods = []string{
 // run an arbitrary qconf command line, bypassing every per-method guard.
 var deniedMethods = map[string]bool{
 	"RunCommand": true,
+	// WithContext and Context take or return a context.Context; they are
+	// used by the adapter itself (see boundInstance) and have no JSON form.
+	"WithContext": true,
+	"Context":     true,
+}
+
+// boundInstance returns the instance bound to ctx with
+// core.WithContext, like qconf.CommandLineQConf. A client that
+// disconnects then also stops the qconf processes started on its behalf.
+// Instances without a WithContext method are returned unchanged.
+func (a *adapter) boundInstance(ctx context.Context) interface{} {
+	return core.WithContext(a.instance, ctx)
 }
 
 type adapter struct {
@@ -238,7 +251,10 @@ func (a *adapter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	method := reflect.ValueOf(a.instance).MethodByName(req.MethodName)
+	// The method runs under the request context, not under the 5 second
+	// decode timeout above; GetClusterConfiguration on a large cluster
+	// legitimately takes longer.
+	method := reflect.ValueOf(a.boundInstance(r.Context())).MethodByName(req.MethodName)
 	if !method.IsValid() {
 		logErr := fmt.Errorf("method not found: %s", req.MethodName)
 		a.fail(ctx, w, r, http.StatusNotFound, logErr.Error(), nil)
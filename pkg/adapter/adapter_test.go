@@ -2,6 +2,7 @@ package adapter_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -32,6 +33,19 @@ func (s *TestService) RunCommand(args ...string) (string, error) {
 	return "ran", nil
 }
 
+// ContextService records the context it was bound to through WithContext.
+type ContextService struct {
+	ctx context.Context
+}
+
+func (s *ContextService) WithContext(ctx context.Context) *ContextService {
+	return &ContextService{ctx: ctx}
+}
+
+func (s *ContextService) HasContext() (bool, error) {
+	return s.ctx != nil, nil
+}
+
 func postMethod(url, method string, args []interface{}) *http.Response {
 	body, _ := json.Marshal(map[string]interface{}{"method": method, "args": args})
 	req, _ := http.NewRequest("POST", url, bytes.NewBuffer(body))
@@ -161,6 +175,29 @@ var _ = Describe("Adapter", func() {
 		})
 	})
 
+	Context("context binding", func() {
+		It("binds an instance with WithContext to the request context", func() {
+			ctxServer := httptest.NewServer(adapter.NewAdapter(&ContextService{}))
+			defer ctxServer.Close()
+
+			resp := postMethod(ctxServer.URL, "HasContext", []interface{}{})
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			var bound bool
+			Expect(json.NewDecoder(resp.Body).Decode(&bound)).To(Succeed())
+			Expect(bound).To(BeTrue())
+		})
+
+		It("does not expose WithContext itself", func() {
+			ctxServer := httptest.NewServer(adapter.NewAdapter(&ContextService{}))
+			defer ctxServer.Close()
+
+			resp := postMethod(ctxServer.URL, "WithContext", []interface{}{nil})
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
 	Context("explicit deny-list", func() {
 		var denyServer *httptest.Server
 
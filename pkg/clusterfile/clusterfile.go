@@ -0,0 +1,207 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+// Package clusterfile reads and writes a declarative, multi-file YAML
+// format for cluster configurations, meant to be kept under version
+// control and reviewed like code.
+//
+// A file holds one or more YAML documents (JSON works as well). A
+// document either lists objects by kind, using the keys of the JSON
+// encoding of ClusterConfig:
+//
+//	include:
+//	  - queues/            # all .yaml, .yml and .json files
+//	  - pe/*.yaml
+//	variables:
+//	  gpu_slots: 4
+//	host_groups:
+//	  "@gpu":
+//	    hostlist: [gpu1, gpu2]
+//	cluster_queues:
+//	  gpu.q:
+//	    hostlist: ["@gpu"]
+//	    slots: ["1", "[@gpu=${gpu_slots}]"]
+//	environments:
+//	  prod:
+//	    include: [overlays/prod.yaml]
+//	    variables:
+//	      gpu_slots: 8
+//
+// or describes a single object, named by its name attribute:
+//
+//	kind: cluster_queue
+//	qname: gpu.q
+//	hostlist: ["@gpu"]
+//
+// Objects are maps of their attributes by JSON name; lists may be
+// written as a single scalar. ${name} is replaced by the variable name
+// in all values, $${ is a literal ${. An object must be defined only
+// once, except in the files of the selected environment which form an
+// overlay: their attributes replace the attributes of the objects
+// already defined, and their admin host, submit host, manager and
+// operator lists replace the base lists.
+//
+// All errors carry the file and line of the offending value.
+package clusterfile
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a problem at a position in a configuration file.
+type Error struct {
+	File string
+	// Line and Column are 0 when the problem concerns the whole file.
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	if e.Line == 0 {
+		return fmt.Sprintf("%s: %v", e.File, e.Err)
+	}
+	return fmt.Sprintf("%s:%d:%d: %v", e.File, e.Line, e.Column, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// errorAt returns an *Error at the position of node n in file.
+func errorAt(file string, n *yaml.Node, format string, args ...any) error {
+	e := &Error{File: file, Err: fmt.Errorf(format, args...)}
+	if n != nil {
+		e.Line, e.Column = n.Line, n.Column
+	}
+	return e
+}
+
+// yamlError converts an error of the YAML parser, which only carries
+// the line in its text, into an *Error.
+func yamlError(file string, err error) error {
+	var te *yaml.TypeError
+	if errors.As(err, &te) {
+		err = errors.New(strings.Join(te.Errors, "; "))
+	}
+	var line int
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if n, _ := fmt.Sscanf(msg, "line %d:", &line); n == 1 {
+		_, msg, _ = strings.Cut(msg, ": ")
+		return &Error{File: file, Line: line, Column: 1, Err: errors.New(msg)}
+	}
+	return &Error{File: file, Err: err}
+}
+
+// sectionType tells how a ClusterConfig field is written in a file.
+type sectionType int
+
+const (
+	// objectSection is a map of objects by name, like cluster_queues.
+	objectSection sectionType = iota
+	// singleSection is a single object, like global_config.
+	singleSection
+	// listSection is a list of names, like admin_hosts.
+	listSection
+)
+
+// section is a field of ClusterConfig.
+type section struct {
+	name  string
+	index int
+	typ   sectionType
+	// object is the struct type of the objects of the section.
+	object reflect.Type
+}
+
+// sections are the sections of ClusterConfig in field order, by name.
+var sections, sectionOrder = clusterConfigSections()
+
+// kindSections maps the kind of a single object document to its section.
+var kindSections = map[string]string{
+	"cluster_environment":  "cluster_environment",
+	"global_config":        "global_config",
+	"scheduler_config":     "scheduler_config",
+	"calendar":             "calendars",
+	"complex_entry":        "complex_entries",
+	"ckpt_interface":       "ckpt_interfaces",
+	"host_configuration":   "host_configurations",
+	"exec_host":            "exec_hosts",
+	"host_group":           "host_groups",
+	"resource_quota_set":   "resource_quota_sets",
+	"parallel_environment": "parallel_environments",
+	"project":              "projects",
+	"user":                 "users",
+	"cluster_queue":        "cluster_queues",
+	"user_set_list":        "user_set_lists",
+}
+
+func clusterConfigSections() (map[string]section, []string) {
+	byName := make(map[string]section)
+	var order []string
+	t := reflect.TypeOf(qconf.ClusterConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		s := section{name: jsonName(f), index: i}
+		switch f.Type.Kind() {
+		case reflect.Map:
+			s.typ, s.object = objectSection, f.Type.Elem()
+		case reflect.Pointer:
+			s.typ, s.object = singleSection, f.Type.Elem()
+		case reflect.Slice:
+			s.typ = listSection
+		default:
+			continue
+		}
+		byName[s.name] = s
+		order = append(order, s.name)
+	}
+	return byName, order
+}
+
+// kindOf returns the kind of single object documents of section s.
+func kindOf(s string) string {
+	for kind, name := range kindSections {
+		if name == s {
+			return kind
+		}
+	}
+	return ""
+}
+
+// jsonName returns the JSON name of a struct field.
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
+
+// nameField returns the JSON name of the name attribute of objects of
+// type t, which is their first field.
+func nameField(t reflect.Type) string {
+	return jsonName(t.Field(0))
+}
@@ -0,0 +1,226 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package clusterfile_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/clusterfile"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+)
+
+var _ = Describe("Clusterfile", func() {
+
+	var dir string
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+	})
+
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+		return path
+	}
+
+	Context("Load", func() {
+
+		BeforeEach(func() {
+			write("cluster.yaml", `include:
+  - queues/
+variables:
+  gpu_slots: 4
+admin_hosts: [master]
+host_groups:
+  "@gpu":
+    hostlist: [gpu1, gpu2]
+global_config:
+  max_unheard: "00:05:00"
+environments:
+  prod:
+    include: [prod.yaml]
+    variables:
+      gpu_slots: 8
+`)
+			write("queues/gpu.q.yaml", `kind: cluster_queue
+qname: gpu.q
+hostlist: "@gpu"
+slots: ["1", "[@gpu=${gpu_slots}]"]
+---
+kind: parallel_environment
+pe_name: smp
+slots: 999
+control_slaves: true
+`)
+			write("prod.yaml", `admin_hosts: [master, backup]
+host_groups:
+  "@gpu":
+    hostlist: [gpu1, gpu2, gpu3]
+`)
+		})
+
+		It("loads included files and substitutes variables", func() {
+			cc, err := clusterfile.Load(filepath.Join(dir, "cluster.yaml"), clusterfile.LoadOptions{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cc.AdminHosts).To(Equal([]string{"master"}))
+			Expect(cc.HostGroups["@gpu"]).To(Equal(qconf.HostGroupConfig{
+				Name: "@gpu", Hosts: []string{"gpu1", "gpu2"}}))
+			Expect(cc.ClusterQueues["gpu.q"].HostList).To(Equal([]string{"@gpu"}))
+			Expect(cc.ClusterQueues["gpu.q"].Slots).To(Equal([]string{"1", "[@gpu=4]"}))
+			Expect(cc.ParallelEnvironments["smp"].Slots).To(Equal(999))
+			Expect(cc.ParallelEnvironments["smp"].ControlSlaves).To(Equal("true"))
+			Expect(cc.GlobalConfig.MaxUnheard).To(Equal("00:05:00"))
+		})
+
+		It("applies the overlay of an environment", func() {
+			cc, err := clusterfile.Load(filepath.Join(dir, "cluster.yaml"),
+				clusterfile.LoadOptions{Environment: "prod"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cc.AdminHosts).To(Equal([]string{"master", "backup"}))
+			Expect(cc.HostGroups["@gpu"].Hosts).To(Equal([]string{"gpu1", "gpu2", "gpu3"}))
+			Expect(cc.ClusterQueues["gpu.q"].Slots).To(Equal([]string{"1", "[@gpu=8]"}))
+		})
+
+		It("lets options override variables", func() {
+			cc, err := clusterfile.Load(filepath.Join(dir, "cluster.yaml"), clusterfile.LoadOptions{
+				Variables: map[string]string{"gpu_slots": "2"},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cc.ClusterQueues["gpu.q"].Slots).To(Equal([]string{"1", "[@gpu=2]"}))
+		})
+
+		It("rejects unknown environments", func() {
+			_, err := clusterfile.Load(filepath.Join(dir, "cluster.yaml"),
+				clusterfile.LoadOptions{Environment: "test"})
+			Expect(err).To(MatchError(ContainSubstring("unknown environment")))
+		})
+	})
+
+	Context("errors", func() {
+
+		load := func(content string) error {
+			_, err := clusterfile.Load(write("cluster.yaml", content), clusterfile.LoadOptions{})
+			return err
+		}
+
+		It("reports the position of unknown attributes", func() {
+			err := load("cluster_queues:\n  all.q:\n    slots: 1\n    slotz: 2\n")
+			var fe *clusterfile.Error
+			Expect(errors.As(err, &fe)).To(BeTrue())
+			Expect(fe.File).To(HaveSuffix("cluster.yaml"))
+			Expect(fe.Line).To(Equal(4))
+			Expect(err.Error()).To(ContainSubstring(`unknown attribute "slotz"`))
+		})
+
+		It("reports the position of invalid values", func() {
+			err := load("parallel_environments:\n  smp:\n    slots: many\n")
+			Expect(err).To(MatchError(HaveSuffix(`cluster.yaml:3:12: invalid integer "many"`)))
+		})
+
+		It("reports undefined variables", func() {
+			err := load("host_groups:\n  \"@all\":\n    hostlist: [\"${hosts}\"]\n")
+			Expect(err).To(MatchError(ContainSubstring(`cluster.yaml:3:16: undefined variable "hosts"`)))
+		})
+
+		It("reports duplicate objects in the base files", func() {
+			write("more.yaml", "host_groups:\n  \"@all\":\n    hostlist: [a]\n")
+			err := load("include: more.yaml\nhost_groups:\n  \"@all\":\n    hostlist: [b]\n")
+			Expect(err).To(MatchError(ContainSubstring("cluster.yaml:3:3: host_group @all already defined at")))
+			Expect(err).To(MatchError(ContainSubstring("more.yaml:2")))
+		})
+
+		It("reports syntax errors with their line", func() {
+			err := load("admin_hosts: [a]\nsubmit_hosts: [b]\nmanagers: - c\n")
+			Expect(err).To(MatchError(HaveSuffix(
+				"cluster.yaml:3:1: block sequence entries are not allowed in this context")))
+		})
+
+		It("reports include cycles", func() {
+			write("a.yaml", "include: cluster.yaml\n")
+			err := load("include: a.yaml\n")
+			Expect(err).To(MatchError(ContainSubstring("include cycle")))
+		})
+
+		It("reports missing includes at the include", func() {
+			err := load("include:\n  - missing.yaml\n")
+			Expect(err).To(MatchError(ContainSubstring("cluster.yaml:2:5:")))
+		})
+	})
+
+	Context("WriteDir", func() {
+
+		cc := qconf.ClusterConfig{
+			AdminHosts: []string{"master"},
+			HostGroups: map[string]qconf.HostGroupConfig{
+				"@gpu": {Name: "@gpu", Hosts: []string{"gpu1"}},
+			},
+			ClusterQueues: map[string]qconf.ClusterQueueConfig{
+				"all.q": {Name: "all.q", HostList: []string{"@gpu"}, Slots: []string{"1", "[@gpu=4]"}},
+			},
+			ExecHosts: map[string]qconf.HostExecConfig{
+				"gpu1": {Name: "gpu1", LoadScaling: map[string]float64{"np_load_avg": 1.5},
+					ComplexValues: map[string]string{"gpu": "2"}},
+			},
+			SchedulerConfig: &qconf.SchedulerConfig{MaxReservation: 10},
+		}
+
+		for _, layout := range []clusterfile.Layout{clusterfile.PerKind, clusterfile.PerObject} {
+			It("round-trips a configuration", func() {
+				Expect(clusterfile.WriteDir(dir, cc, layout)).To(Succeed())
+				loaded, err := clusterfile.Load(filepath.Join(dir, clusterfile.RootFile),
+					clusterfile.LoadOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loaded).To(Equal(cc))
+			})
+		}
+
+		It("round-trips values which look like variables", func() {
+			literal := qconf.ClusterConfig{
+				Managers: []string{"${admin}"},
+				ClusterQueues: map[string]qconf.ClusterQueueConfig{
+					"all.q": {Name: "all.q", Prolog: []string{"/bin/sh -c 'echo ${JOB_ID} $${HOME}'"}},
+				},
+				ExecHosts: map[string]qconf.HostExecConfig{
+					"gpu1": {Name: "gpu1", ComplexValues: map[string]string{"path": "${PATH}"}},
+				},
+			}
+			for _, layout := range []clusterfile.Layout{clusterfile.PerKind, clusterfile.PerObject} {
+				Expect(clusterfile.WriteDir(dir, literal, layout)).To(Succeed())
+				loaded, err := clusterfile.Load(filepath.Join(dir, clusterfile.RootFile),
+					clusterfile.LoadOptions{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(loaded).To(Equal(literal))
+			}
+		})
+
+		It("writes one file per object", func() {
+			Expect(clusterfile.WriteDir(dir, cc, clusterfile.PerObject)).To(Succeed())
+			data, err := os.ReadFile(filepath.Join(dir, "cluster_queues", "all.q.yaml"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(string(data)).To(HavePrefix("kind: cluster_queue\nqname: all.q\n"))
+		})
+	})
+})
@@ -0,0 +1,694 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package clusterfile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	"gopkg.in/yaml.v3"
+)
+
+// LoadOptions controls Load.
+type LoadOptions struct {
+	// Environment selects the overlay defined under environments.
+	// Empty loads the base configuration only.
+	Environment string
+	// Variables override the variables defined in the files.
+	Variables map[string]string
+}
+
+// Load reads the configuration file path, the files it includes and
+// the overlay of the selected environment into a ClusterConfig.
+func Load(path string, opts LoadOptions) (qconf.ClusterConfig, error) {
+	l := &loader{
+		loaded:       make(map[string]bool),
+		variables:    make(map[string]variable),
+		environments: make(map[string]environment),
+		objects:      make(map[string]map[string]*object),
+		singles:      make(map[string]*object),
+		lists:        make(map[string][]listEntry),
+	}
+	if err := l.loadFile(path); err != nil {
+		return qconf.ClusterConfig{}, err
+	}
+	if opts.Environment != "" {
+		env, ok := l.environments[opts.Environment]
+		if !ok {
+			return qconf.ClusterConfig{}, fmt.Errorf("unknown environment %q", opts.Environment)
+		}
+		l.overlay = true
+		if err := l.loadEnvironment(env); err != nil {
+			return qconf.ClusterConfig{}, err
+		}
+	}
+	for name, value := range opts.Variables {
+		l.variables[name] = variable{value: value}
+	}
+	return l.build()
+}
+
+// loader collects the definitions of all loaded files.
+type loader struct {
+	// loaded are the absolute paths of the files already read.
+	loaded map[string]bool
+	// stack are the files currently being read, for include cycles.
+	stack        []string
+	variables    map[string]variable
+	environments map[string]environment
+	// overlay is set while the files of the environment are read.
+	overlay bool
+	// listsReset are the list sections the overlay replaced already.
+	listsReset map[string]bool
+	objects    map[string]map[string]*object
+	singles    map[string]*object
+	lists      map[string][]listEntry
+}
+
+type variable struct {
+	value string
+	file  string
+	node  *yaml.Node
+	// overlay is set for variables of the environment.
+	overlay bool
+}
+
+type environment struct {
+	file string
+	node *yaml.Node
+}
+
+// object is an object of a section with its attributes.
+type object struct {
+	file   string
+	node   *yaml.Node
+	fields map[string]field
+	// order are the attribute names in the order of first definition.
+	order []string
+}
+
+// field is an attribute value and where it was defined.
+type field struct {
+	file  string
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+type listEntry struct {
+	file string
+	node *yaml.Node
+}
+
+func (l *loader) loadFile(path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(l.stack, abs) {
+		return fmt.Errorf("include cycle: %s", strings.Join(append(l.stack, abs), " -> "))
+	}
+	if l.loaded[abs] {
+		return nil
+	}
+	l.loaded[abs] = true
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	l.stack = append(l.stack, abs)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	dec := yaml.NewDecoder(f)
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return yamlError(path, err)
+		}
+		if len(doc.Content) == 0 {
+			continue
+		}
+		if err := l.loadDocument(path, doc.Content[0]); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *loader) loadDocument(file string, root *yaml.Node) error {
+	if root.Kind == yaml.ScalarNode && root.Tag == "!!null" {
+		return nil
+	}
+	if root.Kind != yaml.MappingNode {
+		return errorAt(file, root, "document must be a mapping")
+	}
+	if kind := mappingValue(root, "kind"); kind != nil {
+		return l.loadObjectDocument(file, root, kind)
+	}
+	for i := 0; i < len(root.Content); i += 2 {
+		key, value := root.Content[i], root.Content[i+1]
+		var err error
+		switch key.Value {
+		case "include":
+			err = l.loadIncludes(file, value)
+		case "variables":
+			err = l.addVariables(file, value)
+		case "environments":
+			err = l.addEnvironments(file, value)
+		default:
+			s, ok := sections[key.Value]
+			if !ok {
+				return errorAt(file, key, "unknown section %q", key.Value)
+			}
+			err = l.addSection(file, s, value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadObjectDocument adds a document describing a single object.
+func (l *loader) loadObjectDocument(file string, root, kind *yaml.Node) error {
+	name, ok := kindSections[kind.Value]
+	if !ok {
+		return errorAt(file, kind, "unknown kind %q", kind.Value)
+	}
+	s := sections[name]
+	fields := &yaml.Node{Kind: yaml.MappingNode, Line: root.Line, Column: root.Column}
+	for i := 0; i < len(root.Content); i += 2 {
+		if root.Content[i].Value != "kind" {
+			fields.Content = append(fields.Content, root.Content[i], root.Content[i+1])
+		}
+	}
+	if s.typ == singleSection {
+		return l.addSingle(file, s, fields)
+	}
+	nameNode := mappingValue(fields, nameField(s.object))
+	if nameNode == nil || nameNode.Kind != yaml.ScalarNode || nameNode.Value == "" {
+		return errorAt(file, root, "%s has no %s", kind.Value, nameField(s.object))
+	}
+	return l.addObject(file, s, nameNode, fields)
+}
+
+func (l *loader) loadIncludes(file string, n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode {
+		n = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{n}}
+	}
+	if n.Kind != yaml.SequenceNode {
+		return errorAt(file, n, "include must be a list of files")
+	}
+	for _, entry := range n.Content {
+		if entry.Kind != yaml.ScalarNode {
+			return errorAt(file, entry, "include must be a list of files")
+		}
+		files, err := includedFiles(filepath.Dir(file), entry.Value)
+		if err != nil {
+			return errorAt(file, entry, "%v", err)
+		}
+		for _, f := range files {
+			if err := l.loadFile(f); err != nil {
+				var fe *Error
+				if errors.As(err, &fe) {
+					return err
+				}
+				return errorAt(file, entry, "%v", err)
+			}
+		}
+	}
+	return nil
+}
+
+// includedFiles returns the files an include entry refers to, relative
+// to dir: a file, a glob pattern or a directory, which stands for all
+// .yaml, .yml and .json files in it.
+func includedFiles(dir, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	if info, err := os.Stat(pattern); err == nil && info.IsDir() {
+		var files []string
+		for _, ext := range []string{"*.yaml", "*.yml", "*.json"} {
+			matches, _ := filepath.Glob(filepath.Join(pattern, ext))
+			files = append(files, matches...)
+		}
+		sort.Strings(files)
+		return files, nil
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no files match %s", pattern)
+	}
+	return files, nil
+}
+
+func (l *loader) addVariables(file string, n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return errorAt(file, n, "variables must be a mapping")
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if value.Kind != yaml.ScalarNode {
+			return errorAt(file, value, "variable %s must be a scalar", key.Value)
+		}
+		if prev, ok := l.variables[key.Value]; ok && prev.overlay == l.overlay {
+			return errorAt(file, key, "variable %s already defined at %s:%d",
+				key.Value, prev.file, prev.node.Line)
+		}
+		l.variables[key.Value] = variable{value: value.Value, file: file, node: key,
+			overlay: l.overlay}
+	}
+	return nil
+}
+
+func (l *loader) addEnvironments(file string, n *yaml.Node) error {
+	if l.overlay {
+		return errorAt(file, n, "environments cannot be defined in an overlay")
+	}
+	if n.Kind != yaml.MappingNode {
+		return errorAt(file, n, "environments must be a mapping")
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if prev, ok := l.environments[key.Value]; ok {
+			return errorAt(file, key, "environment %s already defined at %s:%d",
+				key.Value, prev.file, prev.node.Line)
+		}
+		if value.Kind != yaml.MappingNode {
+			return errorAt(file, value, "environment %s must be a mapping", key.Value)
+		}
+		for j := 0; j < len(value.Content); j += 2 {
+			if k := value.Content[j]; k.Value != "include" && k.Value != "variables" {
+				return errorAt(file, k, "unknown environment setting %q", k.Value)
+			}
+		}
+		l.environments[key.Value] = environment{file: file, node: value}
+	}
+	return nil
+}
+
+func (l *loader) loadEnvironment(env environment) error {
+	if vars := mappingValue(env.node, "variables"); vars != nil {
+		if err := l.addVariables(env.file, vars); err != nil {
+			return err
+		}
+	}
+	if include := mappingValue(env.node, "include"); include != nil {
+		return l.loadIncludes(env.file, include)
+	}
+	return nil
+}
+
+func (l *loader) addSection(file string, s section, n *yaml.Node) error {
+	if n.Kind == yaml.ScalarNode && n.Tag == "!!null" {
+		return nil
+	}
+	switch s.typ {
+	case singleSection:
+		return l.addSingle(file, s, n)
+	case listSection:
+		if n.Kind == yaml.ScalarNode {
+			n = &yaml.Node{Kind: yaml.SequenceNode, Content: []*yaml.Node{n}}
+		}
+		if n.Kind != yaml.SequenceNode {
+			return errorAt(file, n, "%s must be a list", s.name)
+		}
+		if l.overlay && !l.listsReset[s.name] {
+			if l.listsReset == nil {
+				l.listsReset = make(map[string]bool)
+			}
+			l.listsReset[s.name] = true
+			l.lists[s.name] = nil
+		}
+		for _, e := range n.Content {
+			if e.Kind != yaml.ScalarNode {
+				return errorAt(file, e, "%s entries must be scalars", s.name)
+			}
+			l.lists[s.name] = append(l.lists[s.name], listEntry{file: file, node: e})
+		}
+		return nil
+	}
+	if n.Kind != yaml.MappingNode {
+		return errorAt(file, n, "%s must be a mapping of objects by name", s.name)
+	}
+	for i := 0; i < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		if value.Kind == yaml.ScalarNode && value.Tag == "!!null" {
+			value = &yaml.Node{Kind: yaml.MappingNode, Line: value.Line, Column: value.Column}
+		}
+		if value.Kind != yaml.MappingNode {
+			return errorAt(file, value, "%s %s must be a mapping", kindOf(s.name), key.Value)
+		}
+		if nameNode := mappingValue(value, nameField(s.object)); nameNode != nil &&
+			nameNode.Value != key.Value {
+			return errorAt(file, nameNode, "%s %s has %s %q", kindOf(s.name), key.Value,
+				nameField(s.object), nameNode.Value)
+		}
+		if err := l.addObject(file, s, key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addObject adds the object named by nameNode to section s. In the
+// overlay the attributes of an existing object are replaced.
+func (l *loader) addObject(file string, s section, nameNode, fields *yaml.Node) error {
+	objects := l.objects[s.name]
+	if objects == nil {
+		objects = make(map[string]*object)
+		l.objects[s.name] = objects
+	}
+	obj, exists := objects[nameNode.Value]
+	if exists && !l.overlay {
+		return errorAt(file, nameNode, "%s %s already defined at %s:%d", kindOf(s.name),
+			nameNode.Value, obj.file, obj.node.Line)
+	}
+	if !exists {
+		obj = &object{file: file, node: nameNode, fields: make(map[string]field)}
+		objects[nameNode.Value] = obj
+	}
+	return l.addFields(file, obj, fields, true)
+}
+
+// addSingle adds the attributes of a single object section. In the
+// base files every attribute may be set only once.
+func (l *loader) addSingle(file string, s section, fields *yaml.Node) error {
+	if fields.Kind != yaml.MappingNode {
+		return errorAt(file, fields, "%s must be a mapping", s.name)
+	}
+	obj := l.singles[s.name]
+	if obj == nil {
+		obj = &object{file: file, node: fields, fields: make(map[string]field)}
+		l.singles[s.name] = obj
+	}
+	return l.addFields(file, obj, fields, l.overlay)
+}
+
+func (l *loader) addFields(file string, obj *object, fields *yaml.Node, replace bool) error {
+	for i := 0; i < len(fields.Content); i += 2 {
+		key, value := fields.Content[i], fields.Content[i+1]
+		if prev, ok := obj.fields[key.Value]; ok && !replace {
+			return errorAt(file, key, "%s already set at %s:%d", key.Value, prev.file,
+				prev.key.Line)
+		}
+		if _, ok := obj.fields[key.Value]; !ok {
+			obj.order = append(obj.order, key.Value)
+		}
+		obj.fields[key.Value] = field{file: file, key: key, value: value}
+	}
+	return nil
+}
+
+// build decodes the collected definitions into a ClusterConfig.
+func (l *loader) build() (qconf.ClusterConfig, error) {
+	var cc qconf.ClusterConfig
+	v := reflect.ValueOf(&cc).Elem()
+	for _, name := range sectionOrder {
+		s := sections[name]
+		dst := v.Field(s.index)
+		switch s.typ {
+		case objectSection:
+			objects := l.objects[name]
+			if objects == nil {
+				continue
+			}
+			m := reflect.MakeMapWithSize(dst.Type(), len(objects))
+			for _, objName := range sortedKeys(objects) {
+				obj := objects[objName]
+				elem := reflect.New(s.object).Elem()
+				if err := l.decodeObject(obj, elem); err != nil {
+					return cc, err
+				}
+				if elem.Field(0).String() == "" {
+					elem.Field(0).SetString(objName)
+				}
+				m.SetMapIndex(reflect.ValueOf(objName), elem)
+			}
+			dst.Set(m)
+		case singleSection:
+			obj := l.singles[name]
+			if obj == nil {
+				continue
+			}
+			p := reflect.New(s.object)
+			if err := l.decodeObject(obj, p.Elem()); err != nil {
+				return cc, err
+			}
+			dst.Set(p)
+		case listSection:
+			var list []string
+			for _, e := range l.lists[name] {
+				value, err := l.substitute(e.file, e.node)
+				if err != nil {
+					return cc, err
+				}
+				if !slices.Contains(list, value.Value) {
+					list = append(list, value.Value)
+				}
+			}
+			if list != nil {
+				dst.Set(reflect.ValueOf(list))
+			}
+		}
+	}
+	return cc, nil
+}
+
+// decodeObject sets the struct dst from the attributes of obj.
+func (l *loader) decodeObject(obj *object, dst reflect.Value) error {
+	indexes := make(map[string]int, dst.NumField())
+	for i := 0; i < dst.NumField(); i++ {
+		indexes[jsonName(dst.Type().Field(i))] = i
+	}
+	for _, name := range obj.order {
+		f := obj.fields[name]
+		i, ok := indexes[f.key.Value]
+		if !ok {
+			return errorAt(f.file, f.key, "unknown attribute %q", f.key.Value)
+		}
+		value, err := l.substitute(f.file, f.value)
+		if err != nil {
+			return err
+		}
+		if err := decodeValue(f.file, value, dst.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeValue sets dst from the node n. Strings and string lists take
+// scalar values verbatim, so "slots: 4" and "slots: [4]" work for the
+// []string queue attributes.
+func decodeValue(file string, n *yaml.Node, dst reflect.Value) error {
+	isNull := n.Kind == yaml.ScalarNode && n.Tag == "!!null"
+	if isNull {
+		return nil
+	}
+	switch dst.Kind() {
+	case reflect.Pointer:
+		p := reflect.New(dst.Type().Elem())
+		if err := decodeValue(file, n, p.Elem()); err != nil {
+			return err
+		}
+		dst.Set(p)
+		return nil
+	case reflect.String:
+		if n.Kind != yaml.ScalarNode {
+			return errorAt(file, n, "expected a scalar value")
+		}
+		dst.SetString(n.Value)
+		return nil
+	case reflect.Int, reflect.Int64:
+		if n.Kind == yaml.ScalarNode {
+			i, err := strconv.ParseInt(n.Value, 10, 64)
+			if err != nil {
+				return errorAt(file, n, "invalid integer %q", n.Value)
+			}
+			dst.SetInt(i)
+			return nil
+		}
+	case reflect.Float64:
+		if n.Kind == yaml.ScalarNode {
+			f, err := strconv.ParseFloat(n.Value, 64)
+			if err != nil {
+				return errorAt(file, n, "invalid number %q", n.Value)
+			}
+			dst.SetFloat(f)
+			return nil
+		}
+	case reflect.Bool:
+		if n.Kind == yaml.ScalarNode {
+			b, err := strconv.ParseBool(n.Value)
+			if err != nil {
+				return errorAt(file, n, "invalid boolean %q", n.Value)
+			}
+			dst.SetBool(b)
+			return nil
+		}
+	case reflect.Map:
+		if dst.Type().Key().Kind() != reflect.String {
+			break
+		}
+		if n.Kind != yaml.MappingNode {
+			return errorAt(file, n, "expected a mapping")
+		}
+		m := reflect.MakeMapWithSize(dst.Type(), len(n.Content)/2)
+		for i := 0; i < len(n.Content); i += 2 {
+			elem := reflect.New(dst.Type().Elem()).Elem()
+			if err := decodeValue(file, n.Content[i+1], elem); err != nil {
+				return err
+			}
+			m.SetMapIndex(reflect.ValueOf(n.Content[i].Value), elem)
+		}
+		dst.Set(m)
+		return nil
+	case reflect.Slice:
+		if dst.Type().Elem().Kind() != reflect.String {
+			break
+		}
+		items := []*yaml.Node{n}
+		if n.Kind == yaml.SequenceNode {
+			items = n.Content
+		}
+		list := make([]string, 0, len(items))
+		for _, item := range items {
+			if item.Kind != yaml.ScalarNode {
+				return errorAt(file, item, "expected a scalar value")
+			}
+			list = append(list, item.Value)
+		}
+		dst.Set(reflect.ValueOf(list))
+		return nil
+	}
+	var generic any
+	if err := n.Decode(&generic); err != nil {
+		return yamlError(file, err)
+	}
+	data, err := json.Marshal(generic)
+	if err != nil {
+		return errorAt(file, n, "%v", err)
+	}
+	p := reflect.New(dst.Type())
+	if err := json.Unmarshal(data, p.Interface()); err != nil {
+		return errorAt(file, n, "invalid value: %v", err)
+	}
+	dst.Set(p.Elem())
+	return nil
+}
+
+// substitute returns a copy of n with the variables replaced in all
+// scalars.
+func (l *loader) substitute(file string, n *yaml.Node) (*yaml.Node, error) {
+	c := *n
+	if n.Kind == yaml.ScalarNode {
+		value, err := l.expand(file, n)
+		if err != nil {
+			return nil, err
+		}
+		if value != n.Value {
+			c.Value, c.Tag, c.Style = value, "!!str", 0
+		}
+		return &c, nil
+	}
+	c.Content = make([]*yaml.Node, len(n.Content))
+	for i, child := range n.Content {
+		var err error
+		if c.Content[i], err = l.substitute(file, child); err != nil {
+			return nil, err
+		}
+	}
+	return &c, nil
+}
+
+// expand replaces ${name} in the scalar n by the value of the variable
+// name and $${ by ${.
+func (l *loader) expand(file string, n *yaml.Node) (string, error) {
+	s := n.Value
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '$' {
+			b.WriteString(s[:i-1] + "${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", errorAt(file, n, "unterminated variable reference in %q", n.Value)
+		}
+		name := s[i+2 : i+end]
+		v, ok := l.variables[name]
+		if !ok {
+			return "", errorAt(file, n, "undefined variable %q", name)
+		}
+		b.WriteString(s[:i] + v.value)
+		s = s[i+end+1:]
+	}
+}
+
+// mappingValue returns the value of key in the mapping node n.
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		if n.Content[i].Value == key {
+			return n.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
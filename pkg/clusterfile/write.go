@@ -0,0 +1,229 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package clusterfile
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	"gopkg.in/yaml.v3"
+)
+
+// Layout selects how WriteDir splits a configuration into files.
+type Layout int
+
+const (
+	// PerKind writes one file per section, like cluster_queues.yaml.
+	PerKind Layout = iota
+	// PerObject writes one file per object, like
+	// cluster_queues/all.q.yaml.
+	PerObject
+)
+
+// RootFile is the name of the file WriteDir writes the includes to.
+const RootFile = "cluster.yaml"
+
+// WriteDir writes cc into the directory dir in the given layout. Load
+// on dir/RootFile returns cc again. Existing files are overwritten,
+// other files in dir are left alone.
+func WriteDir(dir string, cc qconf.ClusterConfig, layout Layout) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	root := &yaml.Node{Kind: yaml.MappingNode}
+	var includes []string
+	v := reflect.ValueOf(cc)
+	for _, name := range sectionOrder {
+		s := sections[name]
+		value := v.Field(s.index)
+		if value.IsNil() || s.typ != singleSection && value.Len() == 0 {
+			continue
+		}
+		switch {
+		case s.typ == listSection:
+			node := &yaml.Node{}
+			if err := node.Encode(value.Interface()); err != nil {
+				return err
+			}
+			escapeVariables(node)
+			appendKeyValue(root, name, node)
+		case s.typ == singleSection && layout == PerObject:
+			node, err := objectNode(value.Elem(), kindOf(name))
+			if err != nil {
+				return err
+			}
+			if err := writeYAML(filepath.Join(dir, name+".yaml"), node); err != nil {
+				return err
+			}
+			includes = append(includes, name+".yaml")
+		case s.typ == objectSection && layout == PerObject:
+			if err := os.MkdirAll(filepath.Join(dir, name), 0755); err != nil {
+				return err
+			}
+			for _, key := range value.MapKeys() {
+				node, err := objectNode(value.MapIndex(key), kindOf(name))
+				if err != nil {
+					return err
+				}
+				file := filepath.Join(dir, name, key.String()+".yaml")
+				if err := writeYAML(file, node); err != nil {
+					return err
+				}
+			}
+			includes = append(includes, name+"/")
+		default:
+			node := &yaml.Node{Kind: yaml.MappingNode}
+			if s.typ == singleSection {
+				objNode, err := objectNode(value.Elem(), "")
+				if err != nil {
+					return err
+				}
+				appendKeyValue(node, name, objNode)
+			} else {
+				objects := &yaml.Node{Kind: yaml.MappingNode}
+				for _, key := range sortedValueKeys(value) {
+					objNode, err := objectNode(value.MapIndex(key), "")
+					if err != nil {
+						return err
+					}
+					appendKeyValue(objects, key.String(), objNode)
+				}
+				appendKeyValue(node, name, objects)
+			}
+			if err := writeYAML(filepath.Join(dir, name+".yaml"), node); err != nil {
+				return err
+			}
+			includes = append(includes, name+".yaml")
+		}
+	}
+	if len(includes) > 0 {
+		node := &yaml.Node{}
+		if err := node.Encode(includes); err != nil {
+			return err
+		}
+		root.Content = append([]*yaml.Node{{Kind: yaml.ScalarNode, Value: "include"}, node},
+			root.Content...)
+	}
+	return writeYAML(filepath.Join(dir, RootFile), root)
+}
+
+// objectNode returns the mapping node of the struct v with its
+// attributes in field order, preceded by kind when not empty.
+func objectNode(v reflect.Value, kind string) (*yaml.Node, error) {
+	data, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil, err
+	}
+	// keep integers exact, float64 would write 1e+06
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var attrs map[string]any
+	if err := dec.Decode(&attrs); err != nil {
+		return nil, err
+	}
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	if kind != "" {
+		appendKeyValue(node, "kind", &yaml.Node{Kind: yaml.ScalarNode, Value: kind})
+	}
+	for i := 0; i < v.NumField(); i++ {
+		name := jsonName(v.Type().Field(i))
+		value, ok := attrs[name]
+		if !ok {
+			continue
+		}
+		valueNode := &yaml.Node{}
+		if err := valueNode.Encode(plainNumbers(value)); err != nil {
+			return nil, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+		escapeVariables(valueNode)
+		appendKeyValue(node, name, valueNode)
+	}
+	return node, nil
+}
+
+// escapeVariables writes ${ as $${ in all scalars of n, so Load reads
+// values like "echo ${JOB_ID}" verbatim instead of expanding them.
+func escapeVariables(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode {
+		n.Value = strings.ReplaceAll(n.Value, "${", "$${")
+		return
+	}
+	for _, child := range n.Content {
+		escapeVariables(child)
+	}
+}
+
+// plainNumbers replaces the json.Numbers in v by int64 or float64 values.
+func plainNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any:
+		for k, e := range v {
+			v[k] = plainNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = plainNumbers(e)
+		}
+	}
+	return v
+}
+
+func appendKeyValue(mapping *yaml.Node, key string, value *yaml.Node) {
+	mapping.Content = append(mapping.Content,
+		&yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+}
+
+func sortedValueKeys(m reflect.Value) []reflect.Value {
+	keys := m.MapKeys()
+	names := make(map[string]reflect.Value, len(keys))
+	for _, k := range keys {
+		names[k.String()] = k
+	}
+	sorted := make([]reflect.Value, 0, len(keys))
+	for _, name := range sortedKeys(names) {
+		sorted = append(sorted, names[name])
+	}
+	return sorted
+}
+
+func writeYAML(path string, node *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(node); err != nil {
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := enc.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0644)
+}
@@ -0,0 +1,364 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+// Package confstore keeps a local, content-addressed history of cluster
+// configurations. Each revision records a snapshot of the ClusterConfig
+// and the share tree together with a timestamp, an author and a message,
+// much like a git commit:
+//
+//	store, err := confstore.Open("/var/lib/ocs/history")
+//	rev, err := store.Record(qc, "admin", "before upgrade")
+//	...
+//	err = store.Checkout(qc, "HEAD~1", false)
+//
+// Snapshots and revisions are stored as JSON files named by the SHA-256
+// of their content below the snapshots and the revisions directory;
+// HEAD holds the id of the latest revision.
+package confstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+)
+
+// ErrNoRevision is returned when a revision cannot be found, including
+// HEAD of an empty store.
+var ErrNoRevision = errors.New("no such revision")
+
+// ErrNoChanges is returned by Commit when the snapshot equals the one of
+// HEAD.
+var ErrNoChanges = errors.New("no changes since last revision")
+
+// Snapshot is the recorded state of a cluster.
+type Snapshot struct {
+	ClusterConfig qconf.ClusterConfig `json:"cluster_config"`
+	// ShareTree is nil when no share tree is configured.
+	ShareTree *qconf.StructuredShareTree `json:"share_tree,omitempty"`
+}
+
+// Revision is an entry of the history.
+type Revision struct {
+	// ID is the content hash of the revision.
+	ID string `json:"-"`
+	// Parent is the ID of the previous revision, empty for the first.
+	Parent   string    `json:"parent,omitempty"`
+	Time     time.Time `json:"time"`
+	Author   string    `json:"author"`
+	Message  string    `json:"message"`
+	Snapshot string    `json:"snapshot"`
+}
+
+// The directories of the two object types, so a revision id prefix
+// never matches a snapshot.
+const (
+	revisionsDir = "revisions"
+	snapshotsDir = "snapshots"
+)
+
+// Store is a configuration history in a local directory.
+type Store struct {
+	dir string
+	// now returns the time of new revisions.
+	now func() time.Time
+}
+
+// Open opens the history in dir, creating the directory when it does not
+// exist.
+func Open(dir string) (*Store, error) {
+	for _, sub := range []string{revisionsDir, snapshotsDir} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0755); err != nil {
+			return nil, fmt.Errorf("failed to create store directory: %w", err)
+		}
+	}
+	return &Store{dir: dir, now: time.Now}, nil
+}
+
+// Record reads the current configuration and share tree from qc and
+// commits them.
+func (s *Store) Record(qc qconf.QConf, author, message string) (Revision, error) {
+	cc, err := qc.GetClusterConfiguration()
+	if err != nil {
+		return Revision{}, fmt.Errorf("failed to get cluster configuration: %w", err)
+	}
+	tree, err := qc.ShowShareTreeStructured()
+	if err != nil && !errors.Is(err, qconf.ErrNoShareTree) {
+		return Revision{}, fmt.Errorf("failed to get share tree: %w", err)
+	}
+	return s.Commit(Snapshot{ClusterConfig: cc, ShareTree: tree}, author, message)
+}
+
+// Commit stores snap as a new revision on top of HEAD and makes it the
+// new HEAD. It returns ErrNoChanges together with HEAD when snap equals
+// the snapshot of HEAD.
+func (s *Store) Commit(snap Snapshot, author, message string) (Revision, error) {
+	snapshotID, err := s.writeObject(snapshotsDir, snap)
+	if err != nil {
+		return Revision{}, err
+	}
+	head, err := s.Head()
+	if err != nil && !errors.Is(err, ErrNoRevision) {
+		return Revision{}, err
+	}
+	if err == nil && head.Snapshot == snapshotID {
+		return head, ErrNoChanges
+	}
+	rev := Revision{
+		Parent:   head.ID,
+		Time:     s.now().UTC(),
+		Author:   author,
+		Message:  message,
+		Snapshot: snapshotID,
+	}
+	if rev.ID, err = s.writeObject(revisionsDir, rev); err != nil {
+		return Revision{}, err
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, "HEAD"), []byte(rev.ID+"\n")); err != nil {
+		return Revision{}, fmt.Errorf("failed to update HEAD: %w", err)
+	}
+	return rev, nil
+}
+
+// Head returns the latest revision.
+func (s *Store) Head() (Revision, error) {
+	return s.Resolve("HEAD")
+}
+
+// Log returns all revisions reachable from HEAD, newest first. The log
+// of an empty store is empty.
+func (s *Store) Log() ([]Revision, error) {
+	rev, err := s.Head()
+	if errors.Is(err, ErrNoRevision) {
+		return nil, nil
+	}
+	var log []Revision
+	for err == nil {
+		log = append(log, rev)
+		if rev.Parent == "" {
+			return log, nil
+		}
+		rev, err = s.revision(rev.Parent)
+	}
+	return log, err
+}
+
+// Resolve returns the revision rev refers to: "HEAD", "HEAD~n" for the
+// n-th ancestor of HEAD, a revision id or a unique prefix of at least
+// four characters of it.
+func (s *Store) Resolve(rev string) (Revision, error) {
+	if rev == "HEAD" || strings.HasPrefix(rev, "HEAD~") {
+		steps := 0
+		if n, ok := strings.CutPrefix(rev, "HEAD~"); ok {
+			var err error
+			if steps, err = strconv.Atoi(n); err != nil || steps < 0 {
+				return Revision{}, fmt.Errorf("invalid revision %q", rev)
+			}
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, "HEAD"))
+		if errors.Is(err, os.ErrNotExist) {
+			return Revision{}, fmt.Errorf("%s: %w", rev, ErrNoRevision)
+		}
+		if err != nil {
+			return Revision{}, fmt.Errorf("failed to read HEAD: %w", err)
+		}
+		r, err := s.revision(strings.TrimSpace(string(data)))
+		for ; err == nil && steps > 0; steps-- {
+			if r.Parent == "" {
+				return Revision{}, fmt.Errorf("%s: %w", rev, ErrNoRevision)
+			}
+			r, err = s.revision(r.Parent)
+		}
+		return r, err
+	}
+	id, err := s.expand(rev)
+	if err != nil {
+		return Revision{}, err
+	}
+	return s.revision(id)
+}
+
+// Show returns the revision rev and its snapshot.
+func (s *Store) Show(rev string) (Revision, Snapshot, error) {
+	r, err := s.Resolve(rev)
+	if err != nil {
+		return Revision{}, Snapshot{}, err
+	}
+	var snap Snapshot
+	if err := s.readObject(snapshotsDir, r.Snapshot, &snap); err != nil {
+		return Revision{}, Snapshot{}, err
+	}
+	return r, snap, nil
+}
+
+// Diff compares the cluster configuration of revA with the one of revB.
+// shareTreeChanged reports whether the share trees differ.
+func (s *Store) Diff(revA, revB string) (comparison *qconf.ClusterConfigComparison,
+	shareTreeChanged bool, err error) {
+	_, a, err := s.Show(revA)
+	if err != nil {
+		return nil, false, err
+	}
+	_, b, err := s.Show(revB)
+	if err != nil {
+		return nil, false, err
+	}
+	comparison, err = a.ClusterConfig.CompareTo(b.ClusterConfig)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to compare configurations: %w", err)
+	}
+	treeA, _ := json.Marshal(a.ShareTree)
+	treeB, _ := json.Marshal(b.ShareTree)
+	return comparison, string(treeA) != string(treeB), nil
+}
+
+// Checkout applies the snapshot of rev to the cluster with Apply and
+// replaces the share tree with the recorded one. With dryRun only the
+// changes are printed. HEAD is not moved; record the cluster afterwards
+// to add the restored state to the history.
+func (s *Store) Checkout(qc qconf.QConf, rev string, dryRun bool) error {
+	_, snap, err := s.Show(rev)
+	if err != nil {
+		return err
+	}
+	if err := qconf.Apply(qc, snap.ClusterConfig, dryRun); err != nil {
+		return fmt.Errorf("failed to apply revision %s: %w", rev, err)
+	}
+	if dryRun {
+		return nil
+	}
+	if snap.ShareTree == nil {
+		err = qc.DeleteShareTree()
+		if errors.Is(err, qconf.ErrNoShareTree) {
+			err = nil
+		}
+	} else {
+		err = qc.ModifyShareTreeStructured(snap.ShareTree)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to restore share tree of revision %s: %w", rev, err)
+	}
+	return nil
+}
+
+func (s *Store) revision(id string) (Revision, error) {
+	var rev Revision
+	if err := s.readObject(revisionsDir, id, &rev); err != nil {
+		return Revision{}, err
+	}
+	rev.ID = id
+	return rev, nil
+}
+
+// expand returns the full revision id of the unique id prefix.
+func (s *Store) expand(prefix string) (string, error) {
+	if len(prefix) < 4 || strings.Trim(prefix, "0123456789abcdef") != "" {
+		return "", fmt.Errorf("invalid revision %q", prefix)
+	}
+	entries, err := os.ReadDir(filepath.Join(s.dir, revisionsDir, prefix[:2]))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("%s: %w", prefix, ErrNoRevision)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read objects: %w", err)
+	}
+	var found string
+	for _, e := range entries {
+		id := prefix[:2] + e.Name()
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		if found != "" {
+			return "", fmt.Errorf("revision %q is ambiguous", prefix)
+		}
+		found = id
+	}
+	if found == "" {
+		return "", fmt.Errorf("%s: %w", prefix, ErrNoRevision)
+	}
+	return found, nil
+}
+
+func (s *Store) objectPath(sub, id string) string {
+	return filepath.Join(s.dir, sub, id[:2], id[2:])
+}
+
+// writeObject stores v as JSON in the directory sub and returns its id.
+func (s *Store) writeObject(sub string, v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode object: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	id := hex.EncodeToString(sum[:])
+	path := s.objectPath(sub, id)
+	if _, err := os.Stat(path); err == nil {
+		return id, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("failed to create object directory: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return "", fmt.Errorf("failed to write object %s: %w", id, err)
+	}
+	return id, nil
+}
+
+func (s *Store) readObject(sub, id string, v any) error {
+	if len(id) < 3 {
+		return fmt.Errorf("%s: %w", id, ErrNoRevision)
+	}
+	data, err := os.ReadFile(s.objectPath(sub, id))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%s: %w", id, ErrNoRevision)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read object %s: %w", id, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode object %s: %w", id, err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a temporary file next to path and
+// renames it, so readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
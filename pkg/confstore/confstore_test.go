@@ -0,0 +1,131 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package confstore_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/confstore"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+)
+
+var _ = Describe("Store", func() {
+
+	var (
+		store *confstore.Store
+		qc    *qconf.InMemoryQConf
+	)
+
+	BeforeEach(func() {
+		var err error
+		store, err = confstore.Open(GinkgoT().TempDir())
+		Expect(err).NotTo(HaveOccurred())
+		qc, err = qconf.NewInMemoryQConf(qconf.InMemoryQConfConfig{
+			ClusterConfig: qconf.ClusterConfig{
+				HostGroups: map[string]qconf.HostGroupConfig{
+					"@allhosts": {Name: "@allhosts", Hosts: []string{"node1"}},
+				},
+			},
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("has an empty log and no HEAD when empty", func() {
+		log, err := store.Log()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).To(BeEmpty())
+		_, err = store.Head()
+		Expect(errors.Is(err, confstore.ErrNoRevision)).To(BeTrue())
+	})
+
+	It("records, logs, shows and diffs revisions", func() {
+		first, err := store.Record(qc, "alice", "initial")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(first.Parent).To(BeEmpty())
+
+		Expect(qc.AddAttribute("hostgroup", "hostlist", "node2", "@allhosts")).To(Succeed())
+		second, err := store.Record(qc, "bob", "add node2")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second.Parent).To(Equal(first.ID))
+
+		log, err := store.Log()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).To(HaveLen(2))
+		Expect(log[0].Message).To(Equal("add node2"))
+		Expect(log[1].Author).To(Equal("alice"))
+
+		rev, snap, err := store.Show("HEAD~1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.ID).To(Equal(first.ID))
+		Expect(snap.ClusterConfig.HostGroups["@allhosts"].Hosts).To(Equal([]string{"node1"}))
+
+		rev, err = store.Resolve(second.ID[:8])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rev.ID).To(Equal(second.ID))
+
+		comparison, treeChanged, err := store.Diff(first.ID, "HEAD")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(treeChanged).To(BeFalse())
+		Expect(comparison.IsSame).To(BeFalse())
+		Expect(comparison.DiffModified.HostGroups).To(HaveKey("@allhosts"))
+	})
+
+	It("does not record unchanged configurations", func() {
+		first, err := store.Record(qc, "alice", "initial")
+		Expect(err).NotTo(HaveOccurred())
+		rev, err := store.Record(qc, "alice", "again")
+		Expect(errors.Is(err, confstore.ErrNoChanges)).To(BeTrue())
+		Expect(rev.ID).To(Equal(first.ID))
+	})
+
+	It("rejects unknown revisions", func() {
+		_, err := store.Record(qc, "alice", "initial")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = store.Resolve("HEAD~1")
+		Expect(errors.Is(err, confstore.ErrNoRevision)).To(BeTrue())
+		_, err = store.Resolve("ffff")
+		Expect(err).To(HaveOccurred())
+		_, err = store.Resolve("xyz")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("does not resolve snapshot ids as revisions", func() {
+		rev, err := store.Record(qc, "alice", "initial")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = store.Resolve(rev.Snapshot)
+		Expect(errors.Is(err, confstore.ErrNoRevision)).To(BeTrue())
+		_, _, err = store.Show(rev.Snapshot[:8])
+		Expect(errors.Is(err, confstore.ErrNoRevision)).To(BeTrue())
+	})
+
+	It("checks out an earlier revision", func() {
+		first, err := store.Record(qc, "alice", "initial")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qc.AddAttribute("hostgroup", "hostlist", "node2", "@allhosts")).To(Succeed())
+
+		Expect(store.Checkout(qc, first.ID, false)).To(Succeed())
+		hg, err := qc.ShowHostGroup("@allhosts")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(Equal([]string{"node1"}))
+	})
+})
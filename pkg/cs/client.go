@@ -0,0 +1,221 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2024 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package cs
+
+import (
+	"fmt"
+	"path/filepath"
+
+	qacct "github.com/hpc-gridware/go-clusterscheduler/pkg/qacct/v9.0"
+	qacct91 "github.com/hpc-gridware/go-clusterscheduler/pkg/qacct/v9.1"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	qconf91 "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.1"
+	qhost "github.com/hpc-gridware/go-clusterscheduler/pkg/qhost/v9.0"
+	qhost91 "github.com/hpc-gridware/go-clusterscheduler/pkg/qhost/v9.1"
+	qstat "github.com/hpc-gridware/go-clusterscheduler/pkg/qstat/v9.0"
+	qstat91 "github.com/hpc-gridware/go-clusterscheduler/pkg/qstat/v9.1"
+)
+
+// ClientConfig configures a Client.
+type ClientConfig struct {
+	// ExecutablePath is the directory containing qconf, qstat, qhost
+	// and qacct. Empty means they are looked up in the PATH.
+	ExecutablePath string
+	// Version skips the version detection when set, for instance when
+	// the version is known from the installation.
+	Version *qconf.ClusterSchedulerVersion
+	// DryRun prints the commands instead of running them. As nothing
+	// runs, it requires Version.
+	DryRun bool
+}
+
+// Client is a version-neutral connection to a cluster scheduler. It
+// detects the version of the installation with qconf's GetVersion and
+// uses the matching implementation of qconf, qstat, qhost and qacct.
+// The global and cluster configuration are exchanged in the superset
+// model GlobalConfig and ClusterConfig; fields the connected version
+// does not support are rejected with an UnsupportedFieldError.
+type Client struct {
+	config  ClientConfig
+	version qconf.ClusterSchedulerVersion
+	// release is the version of the implementation used, which is
+	// the newest supported release not newer than version.
+	release Version
+	qconf   *qconf.CommandLineQConf
+	qconf91 *qconf91.CommandLineQConf
+}
+
+// Supported releases, oldest first.
+var (
+	Release90 = Version{Major: 9, Minor: 0}
+	Release91 = Version{Major: 9, Minor: 1}
+)
+
+// NewClient detects the version of the cluster scheduler and returns a
+// client using the matching implementation. Versions newer than the
+// newest supported release of the same major version use that release;
+// other major versions fail with ErrUnsupportedVersion.
+func NewClient(config ClientConfig) (*Client, error) {
+	if config.DryRun && config.Version == nil {
+		return nil, fmt.Errorf("a dry run client requires the version")
+	}
+	c := &Client{config: config}
+	qc, err := qconf.NewCommandLineQConf(qconf.CommandLineQConfConfig{
+		Executable: c.executable("qconf"),
+		DryRun:     config.DryRun,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if config.Version != nil {
+		c.version = *config.Version
+	} else {
+		c.version, err = qc.GetVersion()
+		if err != nil {
+			return nil, fmt.Errorf("failed to detect cluster scheduler version: %w", err)
+		}
+	}
+
+	release := VersionOf(c.version)
+	switch {
+	case release.Major != 9:
+		return nil, fmt.Errorf("%w: %s %s", ErrUnsupportedVersion,
+			c.version.Product, c.version.Version)
+	case release.AtLeast(Release91):
+		c.release = Release91
+		c.qconf91 = &qconf91.CommandLineQConf{CommandLineQConf: qc}
+	default:
+		c.release = Release90
+	}
+	c.qconf = qc
+	return c, nil
+}
+
+// Version returns the version of the connected cluster scheduler.
+func (c *Client) Version() qconf.ClusterSchedulerVersion {
+	return c.version
+}
+
+// Release returns the release of the implementation in use.
+func (c *Client) Release() Version {
+	return c.release
+}
+
+// QConf returns the qconf implementation for all configuration objects
+// whose format does not differ between versions. Use the Client's
+// GlobalConfig and ClusterConfig methods for the others.
+func (c *Client) QConf() qconf.QConf {
+	return c.qconf
+}
+
+// QStat returns the qstat implementation of the connected version.
+func (c *Client) QStat() (qstat.QStat, error) {
+	config := qstat.CommandLineQStatConfig{
+		Executable: c.executable("qstat"),
+		DryRun:     c.config.DryRun,
+	}
+	if c.release == Release91 {
+		return qstat91.NewCommandLineQstat(config)
+	}
+	return qstat.NewCommandLineQstat(config)
+}
+
+// QHost returns the qhost implementation of the connected version.
+func (c *Client) QHost() (qhost.QHost, error) {
+	config := qhost.CommandLineQHostConfig{
+		Executable: c.executable("qhost"),
+		DryRun:     c.config.DryRun,
+	}
+	if c.release == Release91 {
+		return qhost91.NewCommandLineQhost(config)
+	}
+	return qhost.NewCommandLineQhost(config)
+}
+
+// QAcct returns the qacct implementation of the connected version.
+func (c *Client) QAcct() (qacct.QAcct, error) {
+	config := qacct.CommandLineQAcctConfig{
+		Executable: c.executable("qacct"),
+		DryRun:     c.config.DryRun,
+	}
+	if c.release == Release91 {
+		return qacct91.NewCommandLineQAcct(config)
+	}
+	return qacct.NewCommandLineQAcct(config)
+}
+
+// ShowGlobalConfiguration returns the global configuration. Fields the
+// connected version does not support are left empty.
+func (c *Client) ShowGlobalConfiguration() (*GlobalConfig, error) {
+	if c.qconf91 != nil {
+		return c.qconf91.ShowGlobalConfiguration()
+	}
+	global, err := c.qconf.ShowGlobalConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	return &GlobalConfig{GlobalConfig: *global}, nil
+}
+
+// ModifyGlobalConfig replaces the global configuration. It fails
+// without changing anything if a field is set which the connected
+// version does not support.
+func (c *Client) ModifyGlobalConfig(cfg GlobalConfig) error {
+	if err := CheckGlobalConfig(cfg, c.version); err != nil {
+		return err
+	}
+	if c.qconf91 != nil {
+		return c.qconf91.ModifyGlobalConfig(cfg)
+	}
+	return c.qconf.ModifyGlobalConfig(cfg.GlobalConfig)
+}
+
+// GetClusterConfiguration returns the cluster configuration.
+func (c *Client) GetClusterConfiguration() (ClusterConfig, error) {
+	if c.qconf91 != nil {
+		return c.qconf91.GetClusterConfiguration()
+	}
+	cc, err := c.qconf.GetClusterConfiguration()
+	if err != nil {
+		return ClusterConfig{}, err
+	}
+	return fromCoreClusterConfig(cc), nil
+}
+
+// ApplyClusterConfiguration applies the cluster configuration. Like
+// ModifyGlobalConfig it fails up front for unsupported fields.
+func (c *Client) ApplyClusterConfiguration(cc ClusterConfig) error {
+	if cc.GlobalConfig != nil {
+		if err := CheckGlobalConfig(*cc.GlobalConfig, c.version); err != nil {
+			return err
+		}
+	}
+	if c.qconf91 != nil {
+		return c.qconf91.ApplyClusterConfiguration(cc)
+	}
+	return c.qconf.ApplyClusterConfiguration(toCoreClusterConfig(cc))
+}
+
+func (c *Client) executable(name string) string {
+	if c.config.ExecutablePath == "" {
+		return name
+	}
+	return filepath.Join(c.config.ExecutablePath, name)
+}
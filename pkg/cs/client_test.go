@@ -0,0 +1,142 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2024 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package cs_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/cs"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+)
+
+var _ = Describe("Client", func() {
+
+	// fakeQconf installs a qconf script in a new directory which prints
+	// the version banner for -help and a global configuration with a
+	// v9.1 field for -sconf; other calls are logged to calls.
+	fakeQconf := func(banner string) string {
+		dir := GinkgoT().TempDir()
+		script := `#!/bin/sh
+case "$1" in
+-help) echo "` + banner + `" ;;
+-sconf) printf 'execd_spool_dir /opt/cs/default/spool\njsv_params NONE\nmail_tag TEST\n' ;;
+*) echo "$@" >> "` + dir + `/calls" ;;
+esac
+`
+		Expect(os.WriteFile(filepath.Join(dir, "qconf"), []byte(script), 0755)).To(Succeed())
+		return dir
+	}
+
+	It("uses the v9.1 implementation for a 9.1 cluster", func() {
+		client, err := cs.NewClient(cs.ClientConfig{ExecutablePath: fakeQconf("GCS 9.1.2 (130126-1240)")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Version().Version).To(Equal("9.1.2"))
+		Expect(client.Release()).To(Equal(cs.Release91))
+
+		global, err := client.ShowGlobalConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(global.ExecdSpoolDir).To(Equal("/opt/cs/default/spool"))
+		Expect(global.MailTag).To(Equal("TEST"))
+	})
+
+	It("uses the v9.0 implementation for a 9.0 cluster", func() {
+		client, err := cs.NewClient(cs.ClientConfig{ExecutablePath: fakeQconf("OCS 9.0.10 (131225-1739)")})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Release()).To(Equal(cs.Release90))
+
+		global, err := client.ShowGlobalConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(global.MailTag).To(BeEmpty())
+		Expect(global.ExtraFields).To(HaveKeyWithValue("mail_tag", "TEST"))
+	})
+
+	It("rejects fields the connected version does not support", func() {
+		dir := fakeQconf("OCS 9.0.10 (131225-1739)")
+		client, err := cs.NewClient(cs.ClientConfig{ExecutablePath: dir})
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg := cs.GlobalConfig{TopologyFile: "/etc/topology"}
+		cfg.Mailer = "/bin/mail"
+		err = client.ModifyGlobalConfig(cfg)
+		Expect(errors.Is(err, cs.ErrUnsupportedField)).To(BeTrue())
+		var fieldErr *cs.UnsupportedFieldError
+		Expect(errors.As(err, &fieldErr)).To(BeTrue())
+		Expect(fieldErr.Field).To(Equal("global_config.topology_file"))
+		Expect(fieldErr.Since).To(Equal(cs.Release91))
+		Expect(err.Error()).To(ContainSubstring("OCS 9.0.10"))
+		Expect(filepath.Join(dir, "calls")).NotTo(BeAnExistingFile())
+	})
+
+	It("fails for unsupported major versions", func() {
+		_, err := cs.NewClient(cs.ClientConfig{ExecutablePath: fakeQconf("SGE 8.1.9 (123)")})
+		Expect(errors.Is(err, cs.ErrUnsupportedVersion)).To(BeTrue())
+	})
+
+	It("uses the newest release for newer minor versions", func() {
+		client, err := cs.NewClient(cs.ClientConfig{
+			DryRun:  true,
+			Version: &qconf.ClusterSchedulerVersion{Product: "GCS", Version: "9.3.0", Major: 9, Minor: 3},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(client.Release()).To(Equal(cs.Release91))
+		_, err = client.QStat()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("requires the version for a dry run", func() {
+		_, err := cs.NewClient(cs.ClientConfig{DryRun: true})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("CheckGlobalConfig", func() {
+
+	v90 := qconf.ClusterSchedulerVersion{Product: "OCS", Version: "9.0.1", Major: 9, Minor: 0, Patch: 1}
+	v91 := qconf.ClusterSchedulerVersion{Product: "OCS", Version: "9.1.0", Major: 9, Minor: 1}
+
+	It("accepts all fields on the version introducing them", func() {
+		cfg := cs.GlobalConfig{JsvParams: "x", BindingParams: map[string]string{"a": "b"}}
+		Expect(cs.CheckGlobalConfig(cfg, v91)).To(Succeed())
+	})
+
+	It("reports every unsupported field", func() {
+		cfg := cs.GlobalConfig{JsvParams: "x", BindingParams: map[string]string{"a": "b"}}
+		err := cs.CheckGlobalConfig(cfg, v90)
+		Expect(err).To(MatchError(ContainSubstring("global_config.jsv_params")))
+		Expect(err).To(MatchError(ContainSubstring("global_config.binding_params")))
+	})
+
+	It("ignores unset fields", func() {
+		Expect(cs.CheckGlobalConfig(cs.GlobalConfig{}, v90)).To(Succeed())
+	})
+
+	It("knows when fields became available", func() {
+		since, ok := cs.AvailableSince("global_config.gdi_request_limits")
+		Expect(ok).To(BeTrue())
+		Expect(since).To(Equal(cs.Version{Major: 9, Minor: 1}))
+		_, ok = cs.AvailableSince("global_config.mailer")
+		Expect(ok).To(BeFalse())
+	})
+})
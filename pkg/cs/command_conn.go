@@ -42,4 +42,10 @@ func (c *CommandLineInterface) QConf() (qconf.QConf, error) {
 	)
 }
 
+// Client returns a version-neutral client which detects the version of
+// the cluster scheduler, unlike QConf which always uses v9.0.
+func (c *CommandLineInterface) Client() (*Client, error) {
+	return NewClient(ClientConfig{ExecutablePath: c.executablePath})
+}
+
 // Additional methods for other operations can be added here.
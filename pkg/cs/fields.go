@@ -0,0 +1,188 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2024 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package cs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	qconf91 "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.1"
+)
+
+// GlobalConfig is the version-neutral global configuration: the
+// superset of the attributes of all supported versions. Attributes
+// listed in FieldsSince are only available from the given version on.
+type GlobalConfig = qconf91.GlobalConfig
+
+// ClusterConfig is the version-neutral cluster configuration built on
+// GlobalConfig.
+type ClusterConfig = qconf91.ClusterConfig
+
+// ErrUnsupportedVersion is returned for cluster scheduler versions no
+// implementation exists for.
+var ErrUnsupportedVersion = errors.New("unsupported cluster scheduler version")
+
+// ErrUnsupportedField is wrapped by UnsupportedFieldError.
+var ErrUnsupportedField = errors.New("field not supported by the cluster scheduler version")
+
+// Version is a major.minor cluster scheduler release.
+type Version struct {
+	Major int `json:"major"`
+	Minor int `json:"minor"`
+}
+
+// VersionOf returns the release of v.
+func VersionOf(v qconf.ClusterSchedulerVersion) Version {
+	return Version{Major: v.Major, Minor: v.Minor}
+}
+
+// AtLeast returns true if v is the release o or a later one.
+func (v Version) AtLeast(o Version) bool {
+	return v.Major > o.Major || (v.Major == o.Major && v.Minor >= o.Minor)
+}
+
+func (v Version) String() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+// FieldsSince maps the attributes of the version-neutral configuration
+// model which are not available in every supported version to the
+// version introducing them. Keys are "<object>.<attribute>" with the
+// json names, e.g. "global_config.jsv_params". Attributes not listed
+// are available in all versions.
+var FieldsSince = map[string]Version{
+	"global_config.jsv_params":         {9, 1},
+	"global_config.topology_file":      {9, 1},
+	"global_config.mail_tag":           {9, 1},
+	"global_config.gdi_request_limits": {9, 1},
+	"global_config.binding_params":     {9, 1},
+}
+
+// AvailableSince returns the version introducing the attribute field,
+// given as "<object>.<attribute>". It returns false for attributes
+// available in all supported versions.
+func AvailableSince(field string) (Version, bool) {
+	v, ok := FieldsSince[field]
+	return v, ok
+}
+
+// UnsupportedFieldError reports a field which is set but not supported
+// by the version of the connected cluster.
+type UnsupportedFieldError struct {
+	// Field is the attribute as "<object>.<attribute>".
+	Field string
+	// Since is the version introducing the attribute.
+	Since Version
+	// Version is the version of the connected cluster.
+	Version qconf.ClusterSchedulerVersion
+}
+
+func (e *UnsupportedFieldError) Error() string {
+	return fmt.Sprintf("%s requires cluster scheduler %s or later, connected cluster runs %s %s",
+		e.Field, e.Since, e.Version.Product, e.Version.Version)
+}
+
+func (e *UnsupportedFieldError) Unwrap() error {
+	return ErrUnsupportedField
+}
+
+// CheckGlobalConfig returns an UnsupportedFieldError for every field of
+// cfg which is set but not available in version v. Fields left at their
+// zero value are ignored, so a configuration read from an older cluster
+// always passes.
+func CheckGlobalConfig(cfg GlobalConfig, v qconf.ClusterSchedulerVersion) error {
+	var errs []error
+	value := reflect.ValueOf(cfg)
+	for i := 0; i < value.NumField(); i++ {
+		field := value.Type().Field(i)
+		if field.Anonymous {
+			continue
+		}
+		name := "global_config." + strings.Split(field.Tag.Get("json"), ",")[0]
+		since, ok := FieldsSince[name]
+		if !ok || VersionOf(v).AtLeast(since) || value.Field(i).IsZero() {
+			continue
+		}
+		errs = append(errs, &UnsupportedFieldError{Field: name, Since: since, Version: v})
+	}
+	return errors.Join(errs...)
+}
+
+// toCoreClusterConfig returns the version-neutral configuration as the
+// configuration of the core implementation, which has no fields of
+// later versions.
+func toCoreClusterConfig(cc ClusterConfig) qconf.ClusterConfig {
+	core := qconf.ClusterConfig{
+		ClusterEnvironment:   cc.ClusterEnvironment,
+		SchedulerConfig:      cc.SchedulerConfig,
+		Calendars:            cc.Calendars,
+		ComplexEntries:       cc.ComplexEntries,
+		CkptInterfaces:       cc.CkptInterfaces,
+		HostConfigurations:   cc.HostConfigurations,
+		ExecHosts:            cc.ExecHosts,
+		AdminHosts:           cc.AdminHosts,
+		SubmitHosts:          cc.SubmitHosts,
+		HostGroups:           cc.HostGroups,
+		ResourceQuotaSets:    cc.ResourceQuotaSets,
+		Managers:             cc.Managers,
+		Operators:            cc.Operators,
+		ParallelEnvironments: cc.ParallelEnvironments,
+		Projects:             cc.Projects,
+		Users:                cc.Users,
+		ClusterQueues:        cc.ClusterQueues,
+		UserSetLists:         cc.UserSetLists,
+	}
+	if cc.GlobalConfig != nil {
+		core.GlobalConfig = &cc.GlobalConfig.GlobalConfig
+	}
+	return core
+}
+
+// fromCoreClusterConfig returns the configuration of the core
+// implementation as version-neutral configuration.
+func fromCoreClusterConfig(core qconf.ClusterConfig) ClusterConfig {
+	cc := ClusterConfig{
+		ClusterEnvironment:   core.ClusterEnvironment,
+		SchedulerConfig:      core.SchedulerConfig,
+		Calendars:            core.Calendars,
+		ComplexEntries:       core.ComplexEntries,
+		CkptInterfaces:       core.CkptInterfaces,
+		HostConfigurations:   core.HostConfigurations,
+		ExecHosts:            core.ExecHosts,
+		AdminHosts:           core.AdminHosts,
+		SubmitHosts:          core.SubmitHosts,
+		HostGroups:           core.HostGroups,
+		ResourceQuotaSets:    core.ResourceQuotaSets,
+		Managers:             core.Managers,
+		Operators:            core.Operators,
+		ParallelEnvironments: core.ParallelEnvironments,
+		Projects:             core.Projects,
+		Users:                core.Users,
+		ClusterQueues:        core.ClusterQueues,
+		UserSetLists:         core.UserSetLists,
+	}
+	if core.GlobalConfig != nil {
+		cc.GlobalConfig = &GlobalConfig{GlobalConfig: *core.GlobalConfig}
+	}
+	return cc
+}
@@ -0,0 +1,293 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+// Package drift detects when the live configuration of a cluster
+// diverges from a desired state, for instance after an emergency
+// qconf -mq that never made it into the repository, reports the drift
+// and optionally reconciles it.
+package drift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/clusterfile"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Source returns the desired cluster configuration.
+type Source func() (qconf.ClusterConfig, error)
+
+// FileSource reads the desired configuration from path on every call:
+// a clusterfile tree for .yaml and .yml files, otherwise a JSON file
+// as written by the simulator's dump command.
+func FileSource(path string, opts clusterfile.LoadOptions) Source {
+	return func() (qconf.ClusterConfig, error) {
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml":
+			return clusterfile.Load(path, opts)
+		}
+		var cc qconf.ClusterConfig
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cc, err
+		}
+		if err := json.Unmarshal(data, &cc); err != nil {
+			return cc, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return cc, nil
+	}
+}
+
+// Event is an object whose live configuration differs from the desired
+// one.
+type Event struct {
+	Kind qconf.ObjectKind `json:"kind"`
+	Name string           `json:"name"`
+	// Action is the change which turns the live object into the
+	// desired one.
+	Action qconf.ChangeAction `json:"action"`
+	// Fields are the differing fields of modified objects.
+	Fields []qconf.FieldDiff `json:"fields,omitempty"`
+	// Reconcilable is set when the policy allows to reconcile the kind.
+	Reconcilable bool `json:"reconcilable"`
+	// Reconciled is set when the object was reconciled successfully.
+	Reconciled bool `json:"reconciled"`
+}
+
+func (e Event) String() string {
+	return fmt.Sprintf("%s %s %s", e.Action, e.Kind, e.Name)
+}
+
+// Report is the result of a check.
+type Report struct {
+	Time   time.Time `json:"time"`
+	Events []Event   `json:"events"`
+	// Error is set when the check or the reconciliation failed.
+	Error string `json:"error,omitempty"`
+}
+
+// Drifted reports whether the check found any drift.
+func (r *Report) Drifted() bool {
+	return len(r.Events) > 0
+}
+
+// Notifier is told about the report of every check which found drift
+// or failed.
+type Notifier interface {
+	Notify(ctx context.Context, report *Report) error
+}
+
+// Policy selects the object kinds which are watched and reconciled.
+type Policy struct {
+	// Ignore are kinds which are not compared at all, like exec hosts
+	// which register themselves.
+	Ignore []qconf.ObjectKind
+	// Reconcile enables automatic reconciliation.
+	Reconcile bool
+	// Allow are the kinds which may be reconciled; empty allows all.
+	Allow []qconf.ObjectKind
+	// Deny are kinds which are never reconciled, even when allowed.
+	Deny []qconf.ObjectKind
+}
+
+// reconcilable reports whether objects of kind may be reconciled.
+func (p Policy) reconcilable(kind qconf.ObjectKind) bool {
+	if !p.Reconcile || slices.Contains(p.Deny, kind) {
+		return false
+	}
+	return len(p.Allow) == 0 || slices.Contains(p.Allow, kind)
+}
+
+// Config is the configuration of a Detector.
+type Config struct {
+	// QConf is bound to the context of each check when it has a
+	// WithContext method like qconf.CommandLineQConf.
+	QConf   qconf.QConf
+	Desired Source
+	Policy  Policy
+	// Interval is the time between two checks of Run.
+	Interval  time.Duration
+	Notifiers []Notifier
+	// Meter records the metrics. When nil the global meter provider is
+	// used.
+	Meter metric.Meter
+}
+
+// Detector compares the live configuration with the desired one.
+type Detector struct {
+	config     Config
+	checks     metric.Int64Counter
+	drifted    metric.Int64Gauge
+	reconciled metric.Int64Counter
+	failures   metric.Int64Counter
+}
+
+// NewDetector returns a Detector for config.
+func NewDetector(config Config) (*Detector, error) {
+	if config.QConf == nil || config.Desired == nil {
+		return nil, errors.New("qconf and desired state source are required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = 5 * time.Minute
+	}
+	if config.Meter == nil {
+		config.Meter = otel.Meter("github.com/hpc-gridware/go-clusterscheduler/pkg/drift")
+	}
+	d := &Detector{config: config}
+	var err error
+	if d.checks, err = config.Meter.Int64Counter("drift.checks",
+		metric.WithDescription("Number of drift checks")); err != nil {
+		return nil, err
+	}
+	if d.drifted, err = config.Meter.Int64Gauge("drift.objects",
+		metric.WithDescription("Number of drifted objects found by the last check")); err != nil {
+		return nil, err
+	}
+	if d.reconciled, err = config.Meter.Int64Counter("drift.reconciled",
+		metric.WithDescription("Number of reconciled objects")); err != nil {
+		return nil, err
+	}
+	if d.failures, err = config.Meter.Int64Counter("drift.failures",
+		metric.WithDescription("Number of failed checks and reconciliations")); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Run checks every Interval until ctx is done, starting immediately.
+// Failed checks are reported to the notifiers and do not stop Run.
+func (d *Detector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(d.config.Interval)
+	defer ticker.Stop()
+	for {
+		d.Check(ctx)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check compares the live configuration with the desired one once,
+// reconciles the allowed kinds when the policy says so and notifies
+// about drift and failures.
+func (d *Detector) Check(ctx context.Context) *Report {
+	report := &Report{Time: time.Now()}
+	if err := d.check(ctx, report); err != nil {
+		report.Error = err.Error()
+		d.failures.Add(ctx, 1)
+	}
+	d.checks.Add(ctx, 1)
+	d.drifted.Record(ctx, int64(len(report.Events)))
+	if report.Drifted() || report.Error != "" {
+		for _, n := range d.config.Notifiers {
+			if err := n.Notify(ctx, report); err != nil {
+				d.failures.Add(ctx, 1, metric.WithAttributes(
+					attribute.String("notifier", fmt.Sprintf("%T", n))))
+			}
+		}
+	}
+	return report
+}
+
+func (d *Detector) check(ctx context.Context, report *Report) error {
+	desired, err := d.config.Desired()
+	if err != nil {
+		return fmt.Errorf("failed to read desired configuration: %w", err)
+	}
+	qc := qconf.WithContext(d.config.QConf, ctx)
+	current, err := qc.GetClusterConfiguration()
+	if err != nil {
+		return fmt.Errorf("failed to get cluster configuration: %w", err)
+	}
+
+	var watched, reconcile []qconf.ObjectKind
+	for _, kind := range qconf.ObjectKinds() {
+		if slices.Contains(d.config.Policy.Ignore, kind) {
+			continue
+		}
+		watched = append(watched, kind)
+		if d.config.Policy.reconcilable(kind) {
+			reconcile = append(reconcile, kind)
+		}
+	}
+
+	comparison, err := current.CompareTo(qconf.ReplaceKinds(current, desired, watched))
+	if err != nil {
+		return fmt.Errorf("failed to compare configurations: %w", err)
+	}
+	report.Events = events(comparison.FieldDiffs, reconcile)
+	if len(reconcile) == 0 || !slices.ContainsFunc(report.Events,
+		func(e Event) bool { return e.Reconcilable }) {
+		return nil
+	}
+
+	comparison, err = current.CompareTo(qconf.ReplaceKinds(current, desired, reconcile))
+	if err != nil {
+		return fmt.Errorf("failed to compare configurations: %w", err)
+	}
+	applied, err := qconf.ApplyComparison(qc, current, comparison)
+	if err != nil {
+		return fmt.Errorf("failed to reconcile: %w", err)
+	}
+	for _, change := range applied.Applied {
+		for i, e := range report.Events {
+			if e.Kind == change.Kind && e.Name == change.Name {
+				report.Events[i].Reconciled = true
+			}
+		}
+	}
+	d.reconciled.Add(ctx, int64(len(applied.Applied)))
+	return nil
+}
+
+// events groups the field differences by object.
+func events(diffs []qconf.FieldDiff, reconcile []qconf.ObjectKind) []Event {
+	var events []Event
+	index := make(map[string]int)
+	for _, diff := range diffs {
+		key := string(diff.Kind) + "/" + diff.Name
+		i, ok := index[key]
+		if !ok {
+			i = len(events)
+			index[key] = i
+			events = append(events, Event{
+				Kind:         diff.Kind,
+				Name:         diff.Name,
+				Action:       diff.Action,
+				Reconcilable: slices.Contains(reconcile, diff.Kind),
+			})
+		}
+		if diff.Field != "" {
+			events[i].Fields = append(events[i].Fields, diff)
+		}
+	}
+	return events
+}
@@ -0,0 +1,178 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package drift_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/drift"
+	qconf "github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/v9.0"
+)
+
+// contextQConf records the contexts it is bound to with WithContext.
+type contextQConf struct {
+	*qconf.InMemoryQConf
+	bound *[]context.Context
+}
+
+func (c contextQConf) WithContext(ctx context.Context) contextQConf {
+	*c.bound = append(*c.bound, ctx)
+	return c
+}
+
+var _ = Describe("Detector", func() {
+
+	var (
+		desired qconf.ClusterConfig
+		qc      *qconf.InMemoryQConf
+	)
+
+	newDetector := func(policy drift.Policy, notifiers ...drift.Notifier) *drift.Detector {
+		d, err := drift.NewDetector(drift.Config{
+			QConf:     qc,
+			Desired:   func() (qconf.ClusterConfig, error) { return desired, nil },
+			Policy:    policy,
+			Notifiers: notifiers,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		return d
+	}
+
+	BeforeEach(func() {
+		desired = qconf.ClusterConfig{
+			HostGroups: map[string]qconf.HostGroupConfig{
+				"@all": {Name: "@all", Hosts: []string{"node1"}},
+			},
+			ClusterQueues: map[string]qconf.ClusterQueueConfig{
+				"all.q": {Name: "all.q", HostList: []string{"@all"}, Slots: []string{"1"}},
+			},
+		}
+		var err error
+		qc, err = qconf.NewInMemoryQConf(qconf.InMemoryQConfConfig{ClusterConfig: desired})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("finds no drift when the cluster matches", func() {
+		report := newDetector(drift.Policy{}).Check(context.Background())
+		Expect(report.Error).To(BeEmpty())
+		Expect(report.Drifted()).To(BeFalse())
+	})
+
+	It("reports drifted objects without changing them", func() {
+		Expect(qc.ModifyAttribute("queue", "slots", "8", "all.q")).To(Succeed())
+		Expect(qc.AddAttribute("hostgroup", "hostlist", "node2", "@all")).To(Succeed())
+
+		report := newDetector(drift.Policy{}).Check(context.Background())
+		Expect(report.Error).To(BeEmpty())
+		Expect(report.Events).To(HaveLen(2))
+		for _, e := range report.Events {
+			Expect(e.Action).To(Equal(qconf.ChangeAction("modify")))
+			Expect(e.Reconcilable).To(BeFalse())
+			Expect(e.Fields).NotTo(BeEmpty())
+		}
+
+		q, err := qc.ShowClusterQueue("all.q")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.Slots).To(Equal([]string{"8"}))
+	})
+
+	It("reconciles only the allowed kinds", func() {
+		Expect(qc.ModifyAttribute("queue", "slots", "8", "all.q")).To(Succeed())
+		Expect(qc.AddAttribute("hostgroup", "hostlist", "node2", "@all")).To(Succeed())
+
+		report := newDetector(drift.Policy{
+			Reconcile: true,
+			Allow:     []qconf.ObjectKind{"cluster_queue", "host_group"},
+			Deny:      []qconf.ObjectKind{"host_group"},
+		}).Check(context.Background())
+		Expect(report.Error).To(BeEmpty())
+		Expect(report.Events).To(ConsistOf(
+			And(HaveField("Kind", qconf.ObjectKind("cluster_queue")), HaveField("Reconciled", true)),
+			And(HaveField("Kind", qconf.ObjectKind("host_group")), HaveField("Reconciled", false)),
+		))
+
+		q, err := qc.ShowClusterQueue("all.q")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.Slots).To(Equal([]string{"1"}))
+		hg, err := qc.ShowHostGroup("@all")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(ContainElement("node2"))
+	})
+
+	It("ignores kinds", func() {
+		Expect(qc.AddAttribute("hostgroup", "hostlist", "node2", "@all")).To(Succeed())
+		report := newDetector(drift.Policy{
+			Ignore: []qconf.ObjectKind{"host_group"},
+		}).Check(context.Background())
+		Expect(report.Drifted()).To(BeFalse())
+	})
+
+	It("binds the qconf to the context of the check", func() {
+		var bound []context.Context
+		d, err := drift.NewDetector(drift.Config{
+			QConf:   contextQConf{InMemoryQConf: qc, bound: &bound},
+			Desired: func() (qconf.ClusterConfig, error) { return desired, nil },
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		type key struct{}
+		ctx := context.WithValue(context.Background(), key{}, "check")
+		Expect(d.Check(ctx).Error).To(BeEmpty())
+		Expect(bound).To(HaveLen(1))
+		Expect(bound[0].Value(key{})).To(Equal("check"))
+	})
+
+	It("posts drift reports to a webhook", func() {
+		received := make(chan drift.Report, 1)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var report drift.Report
+			Expect(json.NewDecoder(r.Body).Decode(&report)).To(Succeed())
+			received <- report
+		}))
+		defer server.Close()
+
+		Expect(qc.ModifyAttribute("queue", "slots", "8", "all.q")).To(Succeed())
+		newDetector(drift.Policy{}, drift.WebhookNotifier{URL: server.URL}).Check(context.Background())
+
+		var report drift.Report
+		Eventually(received).Should(Receive(&report))
+		Expect(report.Events).To(HaveLen(1))
+		Expect(report.Events[0].Name).To(Equal("all.q"))
+	})
+
+	It("runs until the context is done", func() {
+		d, err := drift.NewDetector(drift.Config{
+			QConf:    qc,
+			Desired:  func() (qconf.ClusterConfig, error) { return desired, nil },
+			Interval: 10 * time.Millisecond,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(d.Run(ctx)).To(MatchError(context.DeadlineExceeded))
+	})
+})
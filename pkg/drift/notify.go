@@ -0,0 +1,93 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package drift
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+)
+
+// LogNotifier logs every drifted object and failure.
+type LogNotifier struct {
+	// Logger defaults to slog.Default().
+	Logger *slog.Logger
+}
+
+// Notify implements Notifier.
+func (n LogNotifier) Notify(ctx context.Context, report *Report) error {
+	logger := n.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if report.Error != "" {
+		logger.ErrorContext(ctx, "drift check failed", "error", report.Error)
+	}
+	for _, e := range report.Events {
+		fields := make([]string, 0, len(e.Fields))
+		for _, f := range e.Fields {
+			fields = append(fields, f.String())
+		}
+		logger.WarnContext(ctx, "configuration drift", "kind", e.Kind, "name", e.Name,
+			"action", e.Action, "fields", fields, "reconciled", e.Reconciled)
+	}
+	return nil
+}
+
+// WebhookNotifier posts the report as JSON to URL.
+type WebhookNotifier struct {
+	URL string
+	// Header is added to the request, for instance for authorization.
+	Header http.Header
+	// Client defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Notify implements Notifier.
+func (n WebhookNotifier) Notify(ctx context.Context, report *Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for key, values := range n.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post drift report: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to post drift report: %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+// Package qerror classifies the diagnostics the cluster-scheduler client
+// binaries (qconf, qsub, qalter, qdel, qmod, qstat, qhost, qacct) print
+// when a command fails.
+//
+// The wrappers return a *QconfError for a failing command instead of a
+// plain formatted error, so callers can tell the standard failures apart
+// without string-matching the output:
+//
+//	if _, err := qc.ShowClusterQueue("all.q"); errors.Is(err, qerror.ErrNotExist) {
+//		// create the queue
+//	}
+//
+// errors.As gives access to the object, its name, the raw output and the
+// exit code. The messages are the ones qmaster answers with, as defined in
+// msg_common.h and the msg_*.h files of the OCS source.
+package qerror
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+// Kind is the class of a failure reported by a client binary.
+type Kind string
+
+const (
+	// KindUnknown is a failure none of the other kinds matches.
+	KindUnknown Kind = "unknown"
+	// KindNotExist is an object which does not exist, like
+	// `cluster queue "foo.q" does not exist`.
+	KindNotExist Kind = "not_exist"
+	// KindAlreadyExists is an object which cannot be added because one of
+	// the same name exists, like `host group "@rack1" already exists`.
+	KindAlreadyExists Kind = "already_exists"
+	// KindPermissionDenied is an operation the user or host is not allowed
+	// to perform, like `denied: "bob" must be manager for this operation`.
+	KindPermissionDenied Kind = "permission_denied"
+	// KindStillReferenced is an object which cannot be deleted because
+	// other objects refer to it.
+	KindStillReferenced Kind = "still_referenced"
+	// KindQmasterUnreachable is a command which could not talk to qmaster.
+	KindQmasterUnreachable Kind = "qmaster_unreachable"
+)
+
+// Sentinel errors matching a *QconfError of the corresponding kind with
+// errors.Is.
+var (
+	ErrNotExist           = errors.New("object does not exist")
+	ErrAlreadyExists      = errors.New("object already exists")
+	ErrPermissionDenied   = errors.New("permission denied")
+	ErrStillReferenced    = errors.New("object is still referenced")
+	ErrQmasterUnreachable = errors.New("unable to contact qmaster")
+	sentinels             = map[Kind]error{
+		KindNotExist:           ErrNotExist,
+		KindAlreadyExists:      ErrAlreadyExists,
+		KindPermissionDenied:   ErrPermissionDenied,
+		KindStillReferenced:    ErrStillReferenced,
+		KindQmasterUnreachable: ErrQmasterUnreachable,
+	}
+)
+
+// QconfError is the error of a client binary which failed.
+type QconfError struct {
+	// Kind is the class of the failure.
+	Kind Kind
+	// Object is the object type named in the diagnostic, like
+	// "cluster queue" or "job", and empty when it names none.
+	Object string
+	// Name is the quoted object name in the diagnostic, if any.
+	Name string
+	// Output is the output of the command.
+	Output string
+	// ExitCode is the exit status of the command, or -1 when it did not
+	// exit, e.g. because it was killed or could not be started.
+	ExitCode int
+	// Err is the error of running the command. An *exec.ExitError is
+	// kept as its message only, so that errors of the same failure
+	// compare equal; the exit status is in ExitCode.
+	Err error
+}
+
+// Error returns the output and the error of the command.
+func (e *QconfError) Error() string {
+	return fmt.Sprintf("failed to run command (%s): %v", e.Output, e.Err)
+}
+
+// Unwrap returns the error of running the command, so errors.Is finds
+// context.Canceled or context.DeadlineExceeded for a cancelled command.
+func (e *QconfError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the kind of e.
+func (e *QconfError) Is(target error) bool {
+	sentinel, ok := sentinels[e.Kind]
+	return ok && target == sentinel
+}
+
+// New returns the error of a command which printed output and failed
+// with err. The exit code is taken from an *exec.ExitError in err.
+func New(output string, err error) *QconfError {
+	e := &QconfError{Output: output, ExitCode: -1, Err: err}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		e.ExitCode = exitErr.ExitCode()
+		e.Err = errors.New(err.Error())
+	}
+	e.Kind, e.Object, e.Name = Classify(output)
+	return e
+}
+
+// FromOutput returns the error of a command run with exec.Cmd.Output,
+// which keeps the standard error of a failed command in the
+// *exec.ExitError instead of returning it.
+func FromOutput(stdout []byte, err error) *QconfError {
+	output := string(stdout)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		output += string(exitErr.Stderr)
+	}
+	return New(output, err)
+}
+
+// classes are checked in order: a qmaster which cannot be reached says
+// nothing about the object, and deleting a referenced object is often
+// reported with a "denied:" prefix.
+var classes = []struct {
+	kind    Kind
+	pattern *regexp.Regexp
+}{
+	{KindQmasterUnreachable, regexp.MustCompile(
+		`(?i)unable to (contact|send message to) qmaster|commlib error|failed receiving gdi request`)},
+	{KindStillReferenced, regexp.MustCompile(
+		`(?i)still referenced|still reference|is referenced (in|as|by)`)},
+	{KindNotExist, regexp.MustCompile(
+		`(?i)does not exist|do not exist|no sharetree|is not known as`)},
+	{KindAlreadyExists, regexp.MustCompile(
+		`(?i)already exists`)},
+	{KindPermissionDenied, regexp.MustCompile(
+		`(?i)must be (manager|operator)|is no (admin|submit) host|access denied|permission denied`)},
+}
+
+// quoted is the first quoted name of a diagnostic line.
+var quoted = regexp.MustCompile(`"([^"]*)"`)
+
+// Classify returns the kind of the failure reported in output and the
+// object type and name the diagnostic refers to. Output matching no known
+// diagnostic is KindUnknown.
+func Classify(output string) (kind Kind, object, name string) {
+	lines := strings.Split(output, "\n")
+	for _, class := range classes {
+		for _, line := range lines {
+			if class.pattern.MatchString(line) {
+				object, name = objectOf(line)
+				return class.kind, object, name
+			}
+		}
+	}
+	return KindUnknown, "", ""
+}
+
+// objectOf splits a line like `denied: complex attribute "mem" is still
+// referenced` into the object type and name before the first quote.
+func objectOf(line string) (object, name string) {
+	loc := quoted.FindStringSubmatchIndex(line)
+	if loc == nil {
+		return "", ""
+	}
+	object = line[:loc[0]]
+	if i := strings.LastIndex(object, ": "); i >= 0 {
+		object = object[i+2:]
+	}
+	return strings.TrimSpace(object), line[loc[2]:loc[3]]
+}
@@ -0,0 +1,119 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package qerror_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
+)
+
+var _ = Describe("Qerror", func() {
+
+	DescribeTable("Classify",
+		func(output string, kind qerror.Kind, object, name string) {
+			k, o, n := qerror.Classify(output)
+			Expect(k).To(Equal(kind))
+			Expect(o).To(Equal(object))
+			Expect(n).To(Equal(name))
+		},
+		Entry("missing object",
+			"cluster queue \"foo.q\" does not exist\n",
+			qerror.KindNotExist, "cluster queue", "foo.q"),
+		Entry("missing job",
+			"denied: job \"42\" does not exist\n",
+			qerror.KindNotExist, "job", "42"),
+		Entry("missing jobs of qstat",
+			"Following jobs do not exist: \n42\n",
+			qerror.KindNotExist, "", ""),
+		Entry("missing share tree",
+			"no sharetree element\n",
+			qerror.KindNotExist, "", ""),
+		Entry("existing object",
+			"host group \"@rack1\" already exists\n",
+			qerror.KindAlreadyExists, "host group", "@rack1"),
+		Entry("not a manager",
+			"denied: \"bob\" must be manager for this operation\n",
+			qerror.KindPermissionDenied, "", "bob"),
+		Entry("no admin host",
+			"denied: host \"node1\" is no admin host\n",
+			qerror.KindPermissionDenied, "host", "node1"),
+		Entry("referenced object",
+			"denied: complex attribute \"mem\" is still referenced in queue \"all.q\"\n",
+			qerror.KindStillReferenced, "complex attribute", "mem"),
+		Entry("unreachable qmaster before other messages",
+			"error: commlib error: got select error (Connection refused)\n"+
+				"unable to send message to qmaster using port 6444 on host \"master\": got send error\n",
+			qerror.KindQmasterUnreachable, "", ""),
+		Entry("unknown failure",
+			"error: invalid option argument \"-x\"\n",
+			qerror.KindUnknown, "", ""),
+	)
+
+	It("matches the sentinel error of its kind", func() {
+		err := error(qerror.New("project \"p1\" does not exist\n", errors.New("exit status 1")))
+		Expect(errors.Is(err, qerror.ErrNotExist)).To(BeTrue())
+		Expect(errors.Is(err, qerror.ErrAlreadyExists)).To(BeFalse())
+		Expect(err.Error()).To(Equal(
+			"failed to run command (project \"p1\" does not exist\n): exit status 1"))
+
+		err = qerror.New("something went wrong\n", errors.New("exit status 1"))
+		for _, sentinel := range []error{qerror.ErrNotExist, qerror.ErrAlreadyExists,
+			qerror.ErrPermissionDenied, qerror.ErrStillReferenced, qerror.ErrQmasterUnreachable} {
+			Expect(errors.Is(err, sentinel)).To(BeFalse())
+		}
+	})
+
+	It("takes the exit code from the command", func() {
+		out, err := exec.Command("sh", "-c",
+			"echo 'calendar \"night\" does not exist'; exit 2").CombinedOutput()
+		Expect(err).To(HaveOccurred())
+
+		qerr := qerror.New(string(out), err)
+		Expect(qerr.ExitCode).To(Equal(2))
+		Expect(qerr.Kind).To(Equal(qerror.KindNotExist))
+		Expect(qerr.Name).To(Equal("night"))
+		Expect(qerr.Error()).To(ContainSubstring("exit status 2"))
+	})
+
+	It("includes standard error of commands run with Output", func() {
+		out, err := exec.Command("sh", "-c",
+			"echo 'denied: \"bob\" must be manager for this operation' >&2; exit 1").Output()
+		Expect(err).To(HaveOccurred())
+
+		qerr := qerror.FromOutput(out, err)
+		Expect(qerr.ExitCode).To(Equal(1))
+		Expect(errors.Is(qerr, qerror.ErrPermissionDenied)).To(BeTrue())
+		Expect(qerr.Output).To(ContainSubstring("must be manager"))
+	})
+
+	It("unwraps to a context error", func() {
+		qerr := qerror.New("", context.Canceled)
+		Expect(errors.Is(qerr, context.Canceled)).To(BeTrue())
+		Expect(qerr.ExitCode).To(Equal(-1))
+		Expect(qerr.Kind).To(Equal(qerror.KindUnknown))
+	})
+
+})
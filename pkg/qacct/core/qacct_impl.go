@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os/exec"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
 )
 
@@ -79,7 +80,8 @@ func (q *QAcctImpl) NativeSpecification(args []string) (string, error) {
 	command := exec.Command(q.config.Executable, args...)
 	out, err := command.Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to get output of qacct: %w", err)
+		return "", fmt.Errorf("failed to get output of qacct: %w",
+			qerror.FromOutput(out, err))
 	}
 	return string(out), nil
 }
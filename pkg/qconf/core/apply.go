@@ -25,8 +25,9 @@ import (
 )
 
 // Apply compares the current cluster configuration with the new configuration
-// and applies the changes. If dryRun is true, it only prints the plan of the actions,
-// otherwise it applies the changes.
+// and applies the changes. If dryRun is true, it only prints the plan of the actions
+// as shell script (see Plan), otherwise it applies the changes. When applying
+// fails, the changes made so far are rolled back; see ApplyWithReport.
 func Apply(qc QConf, newConfig ClusterConfig, dryRun bool) error {
 	currentConfig, err := qc.GetClusterConfiguration()
 	if err != nil {
@@ -43,35 +44,21 @@ func Apply(qc QConf, newConfig ClusterConfig, dryRun bool) error {
 		return nil
 	}
 
-	if dryRun {
-		fmt.Println("Dry run - planned changes:")
-		qc, err = NewCommandLineQConf(CommandLineQConfConfig{
-			Executable: "qconf",
-			DryRun:     true,
-		})
-		if err != nil {
-			return fmt.Errorf("failed to create qconf: %w", err)
-		}
-	}
-
-	if comparison.DiffAdded != nil {
-		if _, err := AddAllEntries(qc, *comparison.DiffAdded); err != nil {
-			return fmt.Errorf("failed to add elements: %w", err)
-		}
+	if !dryRun {
+		_, err := ApplyComparison(qc, currentConfig, comparison)
+		return err
 	}
 
-	if comparison.DiffModified != nil {
-		if _, err := ModifyAllEntries(qc, *comparison.DiffModified); err != nil {
-			return fmt.Errorf("failed to modify elements: %w", err)
-		}
+	plan, err := newApplyPlan(currentConfig, newConfig, comparison)
+	if err != nil {
+		return fmt.Errorf("failed to plan changes: %w", err)
 	}
-
-	if comparison.DiffRemoved != nil {
-		if _, err := DeleteAllEnries(qc, *comparison.DiffRemoved, false); err != nil {
-			return fmt.Errorf("failed to delete elements: %w", err)
-		}
+	script, err := plan.ShellScript(executableOf(qc))
+	if err != nil {
+		return fmt.Errorf("failed to render plan: %w", err)
 	}
-
+	fmt.Println("Dry run - planned changes:")
+	fmt.Print(script)
 	return nil
 }
 
@@ -288,8 +275,38 @@ func AddAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 // and the error. The modified cluster configuration can be used
 // for rollback.
 func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
+	return ModifyAllEntriesWithOptions(qc, q, ModifyOptions{})
+}
+
+// ModifyOptions controls how ModifyAllEntriesWithOptions changes the
+// objects.
+type ModifyOptions struct {
+	// MinimalChanges changes cluster queues, exec hosts, host groups,
+	// parallel environments and ckpt interfaces attribute by attribute
+	// with ModifyFields instead of replacing them as a whole.
+	MinimalChanges bool
+	// Current is the configuration the changes are computed against.
+	// When nil it is read with GetClusterConfiguration.
+	Current *ClusterConfig
+}
+
+// ModifyAllEntriesWithOptions is ModifyAllEntries with options.
+func ModifyAllEntriesWithOptions(qc QConf, q ClusterConfig, opts ModifyOptions) (ClusterConfig, error) {
 	var modifiedConfig ClusterConfig
 
+	var current ClusterConfig
+	if opts.MinimalChanges {
+		if opts.Current != nil {
+			current = *opts.Current
+		} else {
+			cc, err := qc.GetClusterConfiguration()
+			if err != nil {
+				return modifiedConfig, fmt.Errorf("failed to get current cluster configuration: %w", err)
+			}
+			current = cc
+		}
+	}
+
 	// Modify all user set lists
 	for _, elem := range q.UserSetLists {
 		if err := qc.ModifyUserset(elem.Name, elem); err != nil {
@@ -358,7 +375,8 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 
 	// Modify all host groups
 	for _, elem := range q.HostGroups {
-		if err := qc.ModifyHostGroup(elem.Name, elem); err != nil {
+		if err := modifyObject(qc, opts.MinimalChanges, KindHostGroup, current.HostGroups,
+			elem.Name, elem, qc.ModifyHostGroup); err != nil {
 			return modifiedConfig, fmt.Errorf("failed to modify host group %s: %w",
 				elem.Name, err)
 		}
@@ -370,7 +388,8 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 
 	// Modify all exec hosts
 	for _, elem := range q.ExecHosts {
-		if err := qc.ModifyExecHost(elem.Name, elem); err != nil {
+		if err := modifyObject(qc, opts.MinimalChanges, KindExecHost, current.ExecHosts,
+			elem.Name, elem, qc.ModifyExecHost); err != nil {
 			return modifiedConfig, fmt.Errorf("failed to modify exec host %s: %w",
 				elem.Name, err)
 		}
@@ -380,9 +399,10 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 		modifiedConfig.ExecHosts[elem.Name] = elem
 	}
 
-	// Modify all complex entries
+	// Modify all complex entries one by one; ModifyAllComplexes (-Mc)
+	// would replace the whole complex list and drop all other entries.
 	for _, elem := range q.ComplexEntries {
-		if err := qc.ModifyAllComplexes([]ComplexEntryConfig{elem}); err != nil {
+		if err := qc.ModifyComplexEntry(elem.Name, elem); err != nil {
 			return modifiedConfig, fmt.Errorf("failed to modify complex entry %s: %w",
 				elem.Name, err)
 		}
@@ -406,7 +426,8 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 
 	// Modify all ckpt interfaces
 	for _, elem := range q.CkptInterfaces {
-		if err := qc.ModifyCkptInterface(elem.Name, elem); err != nil {
+		if err := modifyObject(qc, opts.MinimalChanges, KindCkptInterface, current.CkptInterfaces,
+			elem.Name, elem, qc.ModifyCkptInterface); err != nil {
 			return modifiedConfig, fmt.Errorf("failed to modify ckpt interface %s: %w",
 				elem.Name, err)
 		}
@@ -436,7 +457,8 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 
 	// Modify all parallel environments
 	for _, elem := range q.ParallelEnvironments {
-		if err := qc.ModifyParallelEnvironment(elem.Name, elem); err != nil {
+		if err := modifyObject(qc, opts.MinimalChanges, KindParallelEnvironment, current.ParallelEnvironments,
+			elem.Name, elem, qc.ModifyParallelEnvironment); err != nil {
 			return modifiedConfig, fmt.Errorf("failed to modify parallel environment %s: %w",
 				elem.Name, err)
 		}
@@ -448,7 +470,8 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 
 	// Modify all cluster queues
 	for _, elem := range q.ClusterQueues {
-		if err := qc.ModifyClusterQueue(elem.Name, elem); err != nil {
+		if err := modifyObject(qc, opts.MinimalChanges, KindClusterQueue, current.ClusterQueues,
+			elem.Name, elem, qc.ModifyClusterQueue); err != nil {
 			return modifiedConfig, fmt.Errorf("failed to modify cluster queue %s: %w",
 				elem.Name, err)
 		}
@@ -485,6 +508,16 @@ func ModifyAllEntries(qc QConf, q ClusterConfig) (ClusterConfig, error) {
 	return modifiedConfig, nil
 }
 
+// modifyObject changes the object name with ModifyFields when minimal is
+// set and the object exists in current, otherwise with modify.
+func modifyObject[T any](qc QConf, minimal bool, kind ObjectKind, current map[string]T,
+	name string, desired T, modify func(string, T) error) error {
+	if cur, ok := current[name]; ok && minimal {
+		return ModifyFields(qc, kind, name, cur, desired)
+	}
+	return modify(name, desired)
+}
+
 // DeleteAllEnries deletes all elements from the cluster configuration
 // and returns the delete objects of the cluster configuration. The
 // elements must exist before; otherwise, an error is returned.
@@ -675,7 +708,7 @@ func DeleteAllEnries(qc QConf, q ClusterConfig, continueOnError bool) (ClusterCo
 			return deletedConfig,
 				fmt.Errorf("error deleting operator %s: %w", elem, err)
 		}
-		deletedConfig.Operators = q.Operators
+		deletedConfig.Operators = append(deletedConfig.Operators, elem)
 	}
 
 	// Delete all managers
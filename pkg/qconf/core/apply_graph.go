@@ -0,0 +1,719 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"container/heap"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// objectRef names a configuration object of a ClusterConfig.
+type objectRef struct {
+	kind ObjectKind
+	name string
+}
+
+// fieldRef is a reference to another object made in field of an object.
+// host is set for references made in a host or host group override.
+type fieldRef struct {
+	objectRef
+	field string
+	host  string
+}
+
+// queueRefFields maps the JSON names of the list fields of a cluster
+// queue to the kind of object their values name. All other list fields
+// hold plain values; their host and host group overrides still refer to
+// hosts.
+var queueRefFields = map[string]ObjectKind{
+	"hostlist":         KindExecHost,
+	"pe_list":          KindParallelEnvironment,
+	"ckpt_list":        KindCkptInterface,
+	"calendar":         KindCalendar,
+	"user_lists":       KindUserSetList,
+	"xuser_lists":      KindUserSetList,
+	"projects":         KindProject,
+	"xprojects":        KindProject,
+	"subordinate_list": KindClusterQueue,
+	"complex_values":   KindComplexEntry,
+}
+
+// hostRef returns the reference for a host name or, with a leading "@",
+// a host group name.
+func hostRef(name string) objectRef {
+	if strings.HasPrefix(name, "@") {
+		return objectRef{KindHostGroup, name}
+	}
+	return objectRef{KindExecHost, name}
+}
+
+// valueRef returns the object the list value v of the given kind refers
+// to. "NONE" and wildcards do not refer to an object.
+func valueRef(kind ObjectKind, v string) (objectRef, bool) {
+	switch kind {
+	case KindComplexEntry, KindClusterQueue:
+		// complex_values are "name=value", subordinates "name=threshold"
+		v, _, _ = strings.Cut(v, "=")
+	case KindExecHost:
+		ref := hostRef(v)
+		return ref, v != "" && v != "NONE"
+	}
+	if v == "" || v == "NONE" || strings.ContainsAny(v, "*!") {
+		return objectRef{}, false
+	}
+	return objectRef{kind, v}, true
+}
+
+// valueRefs returns the objects the list value v of the given kind
+// refers to. A slotwise subordination refers to all queues it names.
+func valueRefs(kind ObjectKind, v string) []objectRef {
+	if kind == KindClusterQueue {
+		if _, entries, ok := slotwiseSubordinates(v); ok {
+			var refs []objectRef
+			for _, entry := range entries {
+				if ref, ok := valueRef(kind, slotwiseQueue(entry)); ok {
+					refs = append(refs, ref)
+				}
+			}
+			return refs
+		}
+	}
+	if ref, ok := valueRef(kind, v); ok {
+		return []objectRef{ref}
+	}
+	return nil
+}
+
+// slotwiseSubordinates splits a slotwise subordination like
+// "slots=2(low.q:1:sr,other.q:2:lr)" into its threshold "slots=2" and
+// its queue entries "low.q:1:sr" and "other.q:2:lr".
+func slotwiseSubordinates(v string) (string, []string, bool) {
+	threshold, rest, ok := strings.Cut(v, "(")
+	if !ok || !strings.HasPrefix(threshold, "slots=") || !strings.HasSuffix(rest, ")") {
+		return "", nil, false
+	}
+	var entries []string
+	for _, entry := range strings.Split(rest[:len(rest)-1], ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return threshold, entries, true
+}
+
+// slotwiseQueue returns the queue name of a slotwise subordination
+// entry "queue:seq_no:action", where seq_no and action are optional.
+func slotwiseQueue(entry string) string {
+	name, _, _ := strings.Cut(entry, ":")
+	return name
+}
+
+// listValues splits a plain entry or the values of an override of a
+// list field into single values. Separators within parentheses, as in
+// the slotwise subordination "slots=2(low.q:1:sr,other.q:2:lr)", do not
+// split values.
+func listValues(kind ObjectKind, s string) []string {
+	if kind == KindComplexEntry {
+		return strings.Split(s, ",")
+	}
+	var values []string
+	depth, start := 0, -1
+	for i, r := range s {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case (r == ' ' || r == ',') && depth == 0:
+			if start >= 0 {
+				values = append(values, s[start:i])
+				start = -1
+			}
+			continue
+		}
+		if start < 0 {
+			start = i
+		}
+	}
+	if start >= 0 {
+		values = append(values, s[start:])
+	}
+	return values
+}
+
+// joinParenthesized rejoins the list entries which the qconf parsers
+// split at the commas within parentheses, like the slotwise
+// subordination "slots=2(low.q:1:sr,other.q:2:lr)".
+func joinParenthesized(entries []string) []string {
+	var joined []string
+	open := 0
+	for _, entry := range entries {
+		if open > 0 {
+			joined[len(joined)-1] += "," + entry
+		} else {
+			joined = append(joined, entry)
+		}
+		open = max(open+strings.Count(entry, "(")-strings.Count(entry, ")"), 0)
+	}
+	return joined
+}
+
+// parseOverride splits an override like "[host=v1 v2]" into its host
+// and its values.
+func parseOverride(entry string) (string, string, bool) {
+	if !strings.HasPrefix(entry, "[") || !strings.HasSuffix(entry, "]") {
+		return "", "", false
+	}
+	return strings.Cut(entry[1:len(entry)-1], "=")
+}
+
+// listRefs returns the objects the entries of the list field refer to,
+// including the hosts and host groups of its overrides.
+func listRefs(field string, kind ObjectKind, entries []string) []fieldRef {
+	var refs []fieldRef
+	addValues := func(s, host string) {
+		if kind == "" {
+			return
+		}
+		for _, v := range listValues(kind, s) {
+			for _, ref := range valueRefs(kind, v) {
+				refs = append(refs, fieldRef{ref, field, host})
+			}
+		}
+	}
+	for _, entry := range joinParenthesized(entries) {
+		host, values, ok := parseOverride(entry)
+		if !ok {
+			addValues(entry, "")
+			continue
+		}
+		refs = append(refs, fieldRef{hostRef(host), field, host})
+		addValues(values, host)
+	}
+	return refs
+}
+
+// withoutListRefs returns entries without the values and overrides
+// which refer to a dropped object. An override which loses all values
+// is kept as "[host=NONE]", a plain part which loses all values is
+// replaced by "NONE" when overrides remain.
+func withoutListRefs(kind ObjectKind, entries []string, drop func(objectRef) bool) []string {
+	keep := func(s string) []string {
+		if kind == "" {
+			return []string{s}
+		}
+		var kept []string
+		for _, v := range listValues(kind, s) {
+			if threshold, entries, ok := slotwiseSubordinates(v); ok && kind == KindClusterQueue {
+				entries = slices.DeleteFunc(entries, func(entry string) bool {
+					ref, ok := valueRef(kind, slotwiseQueue(entry))
+					return ok && drop(ref)
+				})
+				if len(entries) > 0 {
+					kept = append(kept, threshold+"("+strings.Join(entries, ",")+")")
+				}
+				continue
+			}
+			if ref, ok := valueRef(kind, v); ok && drop(ref) {
+				continue
+			}
+			kept = append(kept, v)
+		}
+		return kept
+	}
+	sep := " "
+	if kind == KindComplexEntry {
+		sep = ","
+	}
+
+	var plain, overrides []string
+	plainCount := 0
+	for _, entry := range joinParenthesized(entries) {
+		host, values, ok := parseOverride(entry)
+		if !ok {
+			plainCount++
+			plain = append(plain, keep(entry)...)
+			continue
+		}
+		if drop(hostRef(host)) {
+			continue
+		}
+		kept := keep(values)
+		if len(kept) == 0 {
+			kept = []string{"NONE"}
+		}
+		overrides = append(overrides, fmt.Sprintf("[%s=%s]", host, strings.Join(kept, sep)))
+	}
+	if len(plain) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	if len(plain) == 0 && plainCount > 0 {
+		plain = []string{"NONE"}
+	}
+	return append(plain, overrides...)
+}
+
+// rqsRefs returns the objects the rules of a resource quota set refer
+// to, like "users {@staff} queues all.q hosts @rack1 to slots=10".
+func rqsRefs(limits []string) []fieldRef {
+	var refs []fieldRef
+	add := func(kind ObjectKind, v string) {
+		if ref, ok := valueRef(kind, v); ok {
+			refs = append(refs, fieldRef{ref, "limits", ""})
+		}
+	}
+	for _, limit := range limits {
+		r, err := ParseRQSRule(limit)
+		if err != nil {
+			continue
+		}
+		for _, f := range []struct {
+			filter *RQSFilter
+			kind   ObjectKind
+		}{
+			{r.Users, KindUserSetList}, {r.Projects, KindProject},
+			{r.PEs, KindParallelEnvironment}, {r.Queues, KindClusterQueue},
+			{r.Hosts, KindExecHost},
+		} {
+			if f.filter == nil {
+				continue
+			}
+			for _, e := range f.filter.Entries {
+				v := e.Name
+				switch f.kind {
+				case KindUserSetList:
+					// plain names are users, not user sets
+					if !strings.HasPrefix(v, "@") {
+						continue
+					}
+					v = v[1:]
+				case KindClusterQueue:
+					v, _, _ = strings.Cut(v, "@")
+				}
+				add(f.kind, v)
+			}
+		}
+		for _, l := range r.Limits {
+			add(KindComplexEntry, l.Resource)
+		}
+	}
+	return refs
+}
+
+// objectRefs returns the objects obj refers to.
+func objectRefs(obj any) []objectRef {
+	var refs []objectRef
+	for _, ref := range objectFieldRefs(obj) {
+		refs = append(refs, ref.objectRef)
+	}
+	return refs
+}
+
+// objectFieldRefs returns the references obj makes to other objects,
+// in the order of its fields.
+func objectFieldRefs(obj any) []fieldRef {
+	var refs []fieldRef
+	switch o := obj.(type) {
+	case ClusterQueueConfig:
+		forEachQueueList(&o, func(field string, entries *[]string) {
+			refs = append(refs, listRefs(field, queueRefFields[field], *entries)...)
+		})
+	case HostGroupConfig:
+		refs = listRefs("hostlist", KindExecHost, o.Hosts)
+	case HostExecConfig:
+		for _, name := range sortedKeys(o.ComplexValues) {
+			refs = append(refs, fieldRef{objectRef{KindComplexEntry, name}, "complex_values", ""})
+		}
+		refs = append(refs, listRefs("user_lists", KindUserSetList, o.UserLists)...)
+		refs = append(refs, listRefs("xuser_lists", KindUserSetList, o.XUserLists)...)
+		refs = append(refs, listRefs("projects", KindProject, o.Projects)...)
+		refs = append(refs, listRefs("xprojects", KindProject, o.XProjects)...)
+	case ParallelEnvironmentConfig:
+		refs = append(listRefs("user_lists", KindUserSetList, o.UserLists),
+			listRefs("xuser_lists", KindUserSetList, o.XUserLists)...)
+	case ProjectConfig:
+		refs = append(listRefs("acl", KindUserSetList, o.ACL),
+			listRefs("xacl", KindUserSetList, o.XACL)...)
+	case UserConfig:
+		if ref, ok := valueRef(KindProject, o.DefaultProject); ok {
+			refs = append(refs, fieldRef{ref, "default_project", ""})
+		}
+	case ResourceQuotaSetConfig:
+		refs = rqsRefs(o.Limits)
+	}
+	return refs
+}
+
+// withoutRefs returns a copy of obj which no longer refers to the
+// dropped objects.
+func withoutRefs(obj any, drop func(objectRef) bool) any {
+	switch o := obj.(type) {
+	case ClusterQueueConfig:
+		forEachQueueList(&o, func(field string, entries *[]string) {
+			*entries = withoutListRefs(queueRefFields[field], *entries, drop)
+		})
+		return o
+	case HostGroupConfig:
+		o.Hosts = withoutListRefs(KindExecHost, o.Hosts, drop)
+		return o
+	case HostExecConfig:
+		complexes := make(map[string]string, len(o.ComplexValues))
+		for name, value := range o.ComplexValues {
+			if !drop(objectRef{KindComplexEntry, name}) {
+				complexes[name] = value
+			}
+		}
+		o.ComplexValues = complexes
+		o.UserLists = withoutListRefs(KindUserSetList, o.UserLists, drop)
+		o.XUserLists = withoutListRefs(KindUserSetList, o.XUserLists, drop)
+		o.Projects = withoutListRefs(KindProject, o.Projects, drop)
+		o.XProjects = withoutListRefs(KindProject, o.XProjects, drop)
+		return o
+	case ParallelEnvironmentConfig:
+		o.UserLists = withoutListRefs(KindUserSetList, o.UserLists, drop)
+		o.XUserLists = withoutListRefs(KindUserSetList, o.XUserLists, drop)
+		return o
+	case ProjectConfig:
+		o.ACL = withoutListRefs(KindUserSetList, o.ACL, drop)
+		o.XACL = withoutListRefs(KindUserSetList, o.XACL, drop)
+		return o
+	case UserConfig:
+		if drop(objectRef{KindProject, o.DefaultProject}) {
+			o.DefaultProject = "NONE"
+		}
+		return o
+	case ResourceQuotaSetConfig:
+		o.Limits = slices.DeleteFunc(slices.Clone(o.Limits), func(limit string) bool {
+			return slices.ContainsFunc(rqsRefs([]string{limit}), func(ref fieldRef) bool {
+				return drop(ref.objectRef)
+			})
+		})
+		return o
+	}
+	return obj
+}
+
+// forEachQueueList calls fn with the JSON name and a pointer to every
+// list field of q.
+func forEachQueueList(q *ClusterQueueConfig, fn func(field string, entries *[]string)) {
+	v := reflect.ValueOf(q).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		entries, ok := v.Field(i).Addr().Interface().(*[]string)
+		if !ok {
+			continue
+		}
+		field, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+		fn(field, entries)
+	}
+}
+
+// applyStep is a single change of one object made by Apply. obj is the
+// object to add or to modify to; deletions have no object.
+type applyStep struct {
+	change AppliedChange
+	obj    any
+}
+
+func (s applyStep) run(qc QConf) error {
+	ops, ok := kindOpsOf(s.change.Kind)
+	if !ok {
+		return fmt.Errorf("unknown object kind %q", s.change.Kind)
+	}
+	switch s.change.Action {
+	case ActionAdd:
+		return ops.add(qc, s.change.Name, s.obj)
+	case ActionModify:
+		return ops.modify(qc, s.change.Name, s.obj)
+	case ActionDelete:
+		return ops.del(qc, s.change.Name)
+	}
+	return fmt.Errorf("unknown action %q", s.change.Action)
+}
+
+// stepNode is a step in the dependency graph built by orderedSteps. An
+// edge from a node to one in next means the node has to run first.
+type stepNode struct {
+	step applyStep
+	// rank orders nodes which do not depend on each other: adds before
+	// modifications before deletions, by kind like AddAllEntries and
+	// DeleteAllEnries, then by name.
+	phase, kind int
+	next, prev  map[*stepNode]bool
+	done        bool
+}
+
+func (n *stepNode) before(o *stepNode) bool {
+	if n.phase != o.phase {
+		return n.phase < o.phase
+	}
+	if n.kind != o.kind {
+		return n.kind < o.kind
+	}
+	return n.step.change.Name < o.step.change.Name
+}
+
+func (n *stepNode) link(to *stepNode) {
+	if n == to {
+		return
+	}
+	n.next[to] = true
+	to.prev[n] = true
+}
+
+func (n *stepNode) unlink(to *stepNode) {
+	delete(n.next, to)
+	delete(to.prev, n)
+}
+
+// pendingPrev returns the predecessors of n which did not run yet.
+func (n *stepNode) pendingPrev() []*stepNode {
+	var pending []*stepNode
+	for p := range n.prev {
+		if !p.done {
+			pending = append(pending, p)
+		}
+	}
+	slices.SortFunc(pending, func(a, b *stepNode) int {
+		if a.before(b) {
+			return -1
+		}
+		return 1
+	})
+	return pending
+}
+
+// stepQueue is a heap of nodes ordered by rank.
+type stepQueue []*stepNode
+
+func (q stepQueue) Len() int           { return len(q) }
+func (q stepQueue) Less(i, j int) bool { return q[i].before(q[j]) }
+func (q stepQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *stepQueue) Push(x any)        { *q = append(*q, x.(*stepNode)) }
+func (q *stepQueue) Pop() any {
+	old := *q
+	n := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return n
+}
+
+// orderedSteps returns the changes of comparison as single steps, in an
+// order in which every object exists before it is referred to and is no
+// longer referred to when it is deleted:
+//
+//   - an object is added before the objects which will refer to it are
+//     added or modified,
+//   - an object which refers to another one, like a queue to a parallel
+//     environment, is modified or deleted before the other one is deleted,
+//   - an object is modified before another object is modified to refer
+//     to it while it still refers to that object, so host groups never
+//     contain each other.
+//
+// Without dependencies the order is the one of AddAllEntries,
+// ModifyAllEntries and DeleteAllEnries. References which form a cycle
+// are broken with staged steps: an object is added without its
+// references to objects which are not added yet and completed by a
+// modification later, or an object is detached from the objects it
+// refers to by a modification before they are deleted.
+func orderedSteps(current ClusterConfig, comparison *ClusterConfigComparison) ([]applyStep, error) {
+	nodes := map[objectRef]*stepNode{}
+	var all []*stepNode
+	newNode := func(step applyStep, phase int) *stepNode {
+		n := &stepNode{
+			step:  step,
+			phase: phase,
+			kind:  kindIndex(step.change.Kind),
+			next:  map[*stepNode]bool{},
+			prev:  map[*stepNode]bool{},
+		}
+		if step.change.Action == ActionDelete {
+			n.kind = len(applyKinds) - n.kind
+		}
+		all = append(all, n)
+		return n
+	}
+
+	phases := []struct {
+		action  ChangeAction
+		changed *ClusterConfig
+	}{
+		{ActionAdd, comparison.DiffAdded},
+		{ActionModify, comparison.DiffModified},
+		{ActionDelete, comparison.DiffRemoved},
+	}
+	for phase, p := range phases {
+		if p.changed == nil {
+			continue
+		}
+		for _, ops := range applyKinds {
+			for _, name := range ops.names(*p.changed) {
+				ref := objectRef{ops.kind, name}
+				if _, ok := nodes[ref]; ok {
+					return nil, fmt.Errorf("%s %s is changed more than once", ops.kind, name)
+				}
+				step := applyStep{change: AppliedChange{Kind: ops.kind, Name: name, Action: p.action}}
+				if p.action != ActionDelete {
+					step.obj, _ = ops.lookup(*p.changed, name)
+				}
+				nodes[ref] = newNode(step, phase)
+			}
+		}
+	}
+
+	// what the objects refer to before and after the change
+	currentRefs := func(ref objectRef) []objectRef {
+		ops, _ := kindOpsOf(ref.kind)
+		obj, ok := ops.lookup(current, ref.name)
+		if !ok {
+			return nil
+		}
+		return objectRefs(obj)
+	}
+	for _, n := range slices.Clone(all) {
+		change := n.step.change
+		self := objectRef{change.Kind, change.Name}
+		if change.Action != ActionDelete {
+			for _, ref := range objectRefs(n.step.obj) {
+				r, ok := nodes[ref]
+				if !ok {
+					continue
+				}
+				switch r.step.change.Action {
+				case ActionAdd:
+					r.link(n)
+				case ActionModify:
+					if change.Action == ActionModify && slices.Contains(currentRefs(ref), self) {
+						r.link(n)
+					}
+				}
+			}
+		}
+		if change.Action != ActionAdd {
+			for _, ref := range currentRefs(self) {
+				if r, ok := nodes[ref]; ok && r.step.change.Action == ActionDelete {
+					n.link(r)
+				}
+			}
+		}
+	}
+
+	var steps []applyStep
+	queue := &stepQueue{}
+	pushReady := func() {
+		for _, n := range all {
+			if !n.done && len(n.pendingPrev()) == 0 {
+				heap.Push(queue, n)
+			}
+		}
+	}
+	pushReady()
+	for done := 0; done < len(all); {
+		if queue.Len() == 0 {
+			staged, err := breakCycle(all, newNode, current)
+			if err != nil {
+				return nil, err
+			}
+			if staged != nil {
+				steps = append(steps, *staged)
+			}
+			pushReady()
+			continue
+		}
+		n := heap.Pop(queue).(*stepNode)
+		n.done = true
+		done++
+		steps = append(steps, n.step)
+		for next := range n.next {
+			if len(next.pendingPrev()) == 0 {
+				heap.Push(queue, next)
+			}
+		}
+	}
+	return steps, nil
+}
+
+// breakCycle breaks a reference cycle among the nodes which did not run
+// yet. A deletion is preceded by a staged modification which detaches
+// the object from the objects it refers to; the returned step has to run
+// right away. An add is split into an add without the missing references
+// and a modification which completes the object. A modification runs
+// regardless of the cycle.
+func breakCycle(all []*stepNode, newNode func(applyStep, int) *stepNode,
+	current ClusterConfig) (*applyStep, error) {
+
+	// every node which waits only waits for nodes of a cycle; walking
+	// back from one reaches a node of the cycle
+	var start *stepNode
+	for _, n := range all {
+		if !n.done && (start == nil || n.before(start)) {
+			start = n
+		}
+	}
+	seen := map[*stepNode]bool{}
+	n := start
+	for !seen[n] {
+		seen[n] = true
+		n = n.pendingPrev()[0]
+	}
+
+	change := n.step.change
+	switch change.Action {
+	case ActionDelete:
+		drop := map[objectRef]bool{}
+		for next := range n.next {
+			if !next.done {
+				drop[objectRef{next.step.change.Kind, next.step.change.Name}] = true
+				n.unlink(next)
+			}
+		}
+		ops, _ := kindOpsOf(change.Kind)
+		obj, ok := ops.lookup(current, change.Name)
+		if !ok {
+			return nil, fmt.Errorf("%s %s does not exist", change.Kind, change.Name)
+		}
+		return &applyStep{
+			change: AppliedChange{Kind: change.Kind, Name: change.Name,
+				Action: ActionModify, Staged: true},
+			obj: withoutRefs(obj, func(ref objectRef) bool { return drop[ref] }),
+		}, nil
+	case ActionAdd:
+		pending := n.pendingPrev()
+		missing := map[objectRef]bool{}
+		complete := newNode(applyStep{
+			change: AppliedChange{Kind: change.Kind, Name: change.Name,
+				Action: ActionModify, Staged: true},
+			obj: n.step.obj,
+		}, 1)
+		for _, p := range pending {
+			missing[objectRef{p.step.change.Kind, p.step.change.Name}] = true
+			p.unlink(n)
+			p.link(complete)
+		}
+		n.step.change.Staged = true
+		n.step.obj = withoutRefs(n.step.obj, func(ref objectRef) bool { return missing[ref] })
+	default:
+		for _, p := range n.pendingPrev() {
+			p.unlink(n)
+		}
+	}
+	return nil, nil
+}
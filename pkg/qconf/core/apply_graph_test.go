@@ -0,0 +1,303 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+// referenceCheckingQConf is an InMemoryQConf which, like qmaster,
+// refuses to delete objects which are still referenced.
+type referenceCheckingQConf struct {
+	*core.InMemoryQConf
+}
+
+func (r *referenceCheckingQConf) referenced(name string, lists func(cc core.ClusterConfig) [][]string) error {
+	cc, err := r.GetClusterConfiguration()
+	if err != nil {
+		return err
+	}
+	for _, list := range lists(cc) {
+		for _, entry := range list {
+			if slices.Contains(strings.FieldsFunc(entry, func(r rune) bool {
+				return strings.ContainsRune(" ,[]=", r)
+			}), name) {
+				return fmt.Errorf("%s is still referenced", name)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *referenceCheckingQConf) DeleteParallelEnvironment(name string) error {
+	err := r.referenced(name, func(cc core.ClusterConfig) [][]string {
+		var lists [][]string
+		for _, q := range cc.ClusterQueues {
+			lists = append(lists, q.PeList)
+		}
+		return lists
+	})
+	if err != nil {
+		return err
+	}
+	return r.InMemoryQConf.DeleteParallelEnvironment(name)
+}
+
+func (r *referenceCheckingQConf) DeleteHostGroup(name string) error {
+	err := r.referenced(name, func(cc core.ClusterConfig) [][]string {
+		var lists [][]string
+		for _, hg := range cc.HostGroups {
+			lists = append(lists, hg.Hosts)
+		}
+		for _, q := range cc.ClusterQueues {
+			lists = append(lists, q.HostList)
+		}
+		return lists
+	})
+	if err != nil {
+		return err
+	}
+	return r.InMemoryQConf.DeleteHostGroup(name)
+}
+
+func (r *referenceCheckingQConf) DeleteComplexEntry(name string) error {
+	err := r.referenced(name, func(cc core.ClusterConfig) [][]string {
+		var lists [][]string
+		for _, h := range cc.ExecHosts {
+			for complex := range h.ComplexValues {
+				lists = append(lists, []string{complex})
+			}
+		}
+		return lists
+	})
+	if err != nil {
+		return err
+	}
+	return r.InMemoryQConf.DeleteComplexEntry(name)
+}
+
+func (r *referenceCheckingQConf) DeleteClusterQueue(name string) error {
+	err := r.referenced(name, func(cc core.ClusterConfig) [][]string {
+		var lists [][]string
+		for qname, q := range cc.ClusterQueues {
+			if qname != name {
+				lists = append(lists, q.SubordinateList)
+			}
+		}
+		return lists
+	})
+	if err != nil {
+		return err
+	}
+	return r.InMemoryQConf.DeleteClusterQueue(name)
+}
+
+var _ = Describe("Apply ordering by references", func() {
+
+	var qc *referenceCheckingQConf
+
+	// apply applies the current configuration changed by change and
+	// returns the applied changes as strings.
+	apply := func(change func(cc *core.ClusterConfig)) []string {
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		change(&cc)
+		report, err := core.ApplyWithReport(qc, cc)
+		Expect(err).NotTo(HaveOccurred())
+		var applied []string
+		for _, c := range report.Applied {
+			applied = append(applied, c.String())
+		}
+		return applied
+	}
+
+	BeforeEach(func() {
+		qc = &referenceCheckingQConf{newInMemoryQConf(core.ClusterConfig{
+			ParallelEnvironments: map[string]core.ParallelEnvironmentConfig{
+				"make": {Name: "make", Slots: 8},
+				"mpi":  {Name: "mpi", Slots: 16},
+			},
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q", HostList: []string{"@allhosts"},
+					PeList: []string{"make", "[node1=mpi make]"}},
+			},
+			HostGroups: map[string]core.HostGroupConfig{
+				"@allhosts": {Name: "@allhosts", Hosts: []string{"@rack1"}},
+				"@rack1":    {Name: "@rack1", Hosts: []string{"node1"}},
+			},
+			ExecHosts: map[string]core.HostExecConfig{
+				"node1": {Name: "node1", ComplexValues: map[string]string{"gpu": "2"}},
+			},
+			ComplexEntries: map[string]core.ComplexEntryConfig{
+				"gpu": {Name: "gpu", Shortcut: "gpu", Type: "INT", Relop: "<=",
+					Requestable: "YES", Consumable: "YES", Default: "0"},
+			},
+		})}
+	})
+
+	It("modifies a queue with a host override before deleting its parallel environment", func() {
+		applied := apply(func(cc *core.ClusterConfig) {
+			delete(cc.ParallelEnvironments, "mpi")
+			q := cc.ClusterQueues["all.q"]
+			q.PeList = []string{"make"}
+			cc.ClusterQueues["all.q"] = q
+		})
+		Expect(applied).To(Equal([]string{
+			"modify cluster_queue all.q",
+			"delete parallel_environment mpi",
+		}))
+	})
+
+	It("adds and deletes nested host groups in reference order", func() {
+		applied := apply(func(cc *core.ClusterConfig) {
+			cc.HostGroups["@a"] = core.HostGroupConfig{Name: "@a", Hosts: []string{"@b"}}
+			cc.HostGroups["@b"] = core.HostGroupConfig{Name: "@b", Hosts: []string{"node2"}}
+		})
+		Expect(applied).To(Equal([]string{"add host_group @b", "add host_group @a"}))
+
+		applied = apply(func(cc *core.ClusterConfig) {
+			delete(cc.ClusterQueues, "all.q")
+			delete(cc.HostGroups, "@rack1")
+			delete(cc.HostGroups, "@allhosts")
+		})
+		Expect(applied).To(Equal([]string{
+			"delete cluster_queue all.q",
+			"delete host_group @allhosts",
+			"delete host_group @rack1",
+		}))
+	})
+
+	It("modifies host groups so they never contain each other", func() {
+		applied := apply(func(cc *core.ClusterConfig) {
+			cc.HostGroups["@allhosts"] = core.HostGroupConfig{Name: "@allhosts", Hosts: []string{"node1"}}
+			cc.HostGroups["@rack1"] = core.HostGroupConfig{Name: "@rack1", Hosts: []string{"@allhosts"}}
+		})
+		Expect(applied).To(Equal([]string{
+			"modify host_group @allhosts",
+			"modify host_group @rack1",
+		}))
+	})
+
+	It("deletes an exec host before the complex it uses", func() {
+		applied := apply(func(cc *core.ClusterConfig) {
+			delete(cc.ExecHosts, "node1")
+			delete(cc.ComplexEntries, "gpu")
+		})
+		Expect(applied).To(Equal([]string{
+			"delete exec_host node1",
+			"delete complex_entry gpu",
+		}))
+	})
+
+	It("adds a parallel environment before a resource quota set using it", func() {
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		cc.ParallelEnvironments["smp"] = core.ParallelEnvironmentConfig{Name: "smp", Slots: 4}
+		cc.ResourceQuotaSets = map[string]core.ResourceQuotaSetConfig{
+			"max_smp": {Name: "max_smp", Enabled: true,
+				Limits: []string{"users {*} pes smp to slots=4"}},
+		}
+		plan, err := core.Plan(qc, cc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Operations).To(HaveLen(2))
+		Expect(plan.Operations[0].String()).To(Equal("add parallel_environment smp"))
+		Expect(plan.Operations[1].String()).To(Equal("add resource_quota_set max_smp"))
+	})
+
+	It("stages adds of queues which subordinate each other", func() {
+		applied := apply(func(cc *core.ClusterConfig) {
+			cc.ClusterQueues["a.q"] = core.ClusterQueueConfig{Name: "a.q",
+				SubordinateList: []string{"b.q=1"}}
+			cc.ClusterQueues["b.q"] = core.ClusterQueueConfig{Name: "b.q",
+				SubordinateList: []string{"a.q=1"}}
+		})
+		Expect(applied).To(Equal([]string{
+			"add cluster_queue a.q (staged)",
+			"add cluster_queue b.q",
+			"modify cluster_queue a.q (staged)",
+		}))
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.ClusterQueues["a.q"].SubordinateList).To(Equal([]string{"b.q=1"}))
+
+		applied = apply(func(cc *core.ClusterConfig) {
+			delete(cc.ClusterQueues, "a.q")
+			delete(cc.ClusterQueues, "b.q")
+		})
+		Expect(applied).To(Equal([]string{
+			"modify cluster_queue a.q (staged)",
+			"delete cluster_queue b.q",
+			"delete cluster_queue a.q",
+		}))
+		cc, err = qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.ClusterQueues).To(HaveLen(1))
+	})
+
+	It("orders queues by the queues of a slotwise subordination", func() {
+		slotwise := []string{"slots=2(b.q:1:sr", "c.q:2:lr)"}
+		applied := apply(func(cc *core.ClusterConfig) {
+			cc.ClusterQueues["a.q"] = core.ClusterQueueConfig{Name: "a.q",
+				SubordinateList: slotwise}
+			cc.ClusterQueues["b.q"] = core.ClusterQueueConfig{Name: "b.q",
+				SubordinateList: []string{"a.q=1"}}
+			cc.ClusterQueues["c.q"] = core.ClusterQueueConfig{Name: "c.q"}
+		})
+		Expect(applied).To(Equal([]string{
+			"add cluster_queue c.q",
+			"add cluster_queue a.q (staged)",
+			"add cluster_queue b.q",
+			"modify cluster_queue a.q (staged)",
+		}))
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.ClusterQueues["a.q"].SubordinateList).To(Equal(slotwise))
+	})
+
+	It("restores a detached object when a later delete fails", func() {
+		Expect(qc.AddClusterQueue(core.ClusterQueueConfig{Name: "a.q",
+			SubordinateList: []string{"b.q=1"}})).To(Succeed())
+		Expect(qc.AddClusterQueue(core.ClusterQueueConfig{Name: "b.q",
+			SubordinateList: []string{"a.q=1"}, PeList: []string{"mpi"}})).To(Succeed())
+		before, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+
+		desired, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		delete(desired.ClusterQueues, "a.q")
+		delete(desired.ClusterQueues, "b.q")
+		// all.q still uses mpi on node1, so deleting mpi fails
+		delete(desired.ParallelEnvironments, "mpi")
+
+		report, err := core.ApplyWithReport(qc, desired)
+		Expect(err).To(MatchError(ContainSubstring("mpi is still referenced")))
+		Expect(report.Reverted).To(HaveLen(3))
+		after, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+})
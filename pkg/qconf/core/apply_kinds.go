@@ -0,0 +1,297 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"slices"
+)
+
+// ObjectKind identifies the type of a configuration object of a
+// ClusterConfig.
+type ObjectKind string
+
+const (
+	KindUserSetList         ObjectKind = "user_set_list"
+	KindProject             ObjectKind = "project"
+	KindUser                ObjectKind = "user"
+	KindManager             ObjectKind = "manager"
+	KindOperator            ObjectKind = "operator"
+	KindHostConfiguration   ObjectKind = "host_configuration"
+	KindHostGroup           ObjectKind = "host_group"
+	KindExecHost            ObjectKind = "exec_host"
+	KindComplexEntry        ObjectKind = "complex_entry"
+	KindCalendar            ObjectKind = "calendar"
+	KindCkptInterface       ObjectKind = "ckpt_interface"
+	KindAdminHost           ObjectKind = "admin_host"
+	KindResourceQuotaSet    ObjectKind = "resource_quota_set"
+	KindParallelEnvironment ObjectKind = "parallel_environment"
+	KindClusterQueue        ObjectKind = "cluster_queue"
+	KindSubmitHost          ObjectKind = "submit_host"
+	KindGlobalConfig        ObjectKind = "global_config"
+	KindSchedulerConfig     ObjectKind = "scheduler_config"
+)
+
+// ChangeAction is the kind of change made to a configuration object.
+type ChangeAction string
+
+const (
+	ActionAdd    ChangeAction = "add"
+	ActionModify ChangeAction = "modify"
+	ActionDelete ChangeAction = "delete"
+)
+
+// kindOps describes one object kind of a ClusterConfig: how to find its
+// objects and how to change one of them in the cluster.
+type kindOps struct {
+	kind ObjectKind
+	// list is set for kinds which are plain name lists, like managers.
+	// Their objects are nil; modifying one adds the name to the list.
+	list  bool
+	names func(cc ClusterConfig) []string
+	// lookup returns the object called name.
+	lookup func(cc ClusterConfig, name string) (any, bool)
+	// set stores obj as the object called name in cc.
+	set func(cc *ClusterConfig, name string, obj any)
+	// add, modify and del change the object called name in the cluster;
+	// obj is an object as returned by lookup. The global and scheduler
+	// configuration can only be modified.
+	add    func(qc QConf, name string, obj any) error
+	modify func(qc QConf, name string, obj any) error
+	del    func(qc QConf, name string) error
+}
+
+// mapKindOps returns the operations for kinds which are kept in a map
+// of the ClusterConfig, like cluster queues.
+func mapKindOps[T any](kind ObjectKind, field func(cc *ClusterConfig) *map[string]T,
+	add func(qc QConf, name string, obj T) error,
+	modify func(qc QConf, name string, obj T) error,
+	del func(qc QConf, name string) error) kindOps {
+
+	return kindOps{
+		kind: kind,
+		names: func(cc ClusterConfig) []string {
+			return sortedKeys(*field(&cc))
+		},
+		lookup: func(cc ClusterConfig, name string) (any, bool) {
+			obj, ok := (*field(&cc))[name]
+			return obj, ok
+		},
+		set: func(cc *ClusterConfig, name string, obj any) {
+			m := field(cc)
+			if *m == nil {
+				*m = make(map[string]T)
+			}
+			(*m)[name] = obj.(T)
+		},
+		add: func(qc QConf, name string, obj any) error {
+			return add(qc, name, obj.(T))
+		},
+		modify: func(qc QConf, name string, obj any) error {
+			return modify(qc, name, obj.(T))
+		},
+		del: del,
+	}
+}
+
+// listKindOps returns the operations for kinds which are plain name lists.
+func listKindOps(kind ObjectKind, field func(cc *ClusterConfig) *[]string,
+	add, del func(qc QConf, names []string) error) kindOps {
+
+	return kindOps{
+		kind: kind,
+		list: true,
+		names: func(cc ClusterConfig) []string {
+			return *field(&cc)
+		},
+		lookup: func(cc ClusterConfig, name string) (any, bool) {
+			return nil, slices.Contains(*field(&cc), name)
+		},
+		set: func(cc *ClusterConfig, name string, _ any) {
+			if !slices.Contains(*field(cc), name) {
+				*field(cc) = append(*field(cc), name)
+			}
+		},
+		add: func(qc QConf, name string, _ any) error {
+			return add(qc, []string{name})
+		},
+		modify: func(qc QConf, name string, _ any) error {
+			return add(qc, []string{name})
+		},
+		del: func(qc QConf, name string) error {
+			return del(qc, []string{name})
+		},
+	}
+}
+
+// singletonKindOps returns the operations for the global and the
+// scheduler configuration, which always exist and can only be modified.
+func singletonKindOps[T any](kind ObjectKind, name string, field func(cc *ClusterConfig) **T,
+	modify func(qc QConf, obj T) error) kindOps {
+
+	return kindOps{
+		kind: kind,
+		names: func(cc ClusterConfig) []string {
+			if *field(&cc) == nil {
+				return nil
+			}
+			return []string{name}
+		},
+		lookup: func(cc ClusterConfig, _ string) (any, bool) {
+			obj := *field(&cc)
+			if obj == nil {
+				return nil, false
+			}
+			return *obj, true
+		},
+		set: func(cc *ClusterConfig, _ string, obj any) {
+			v := obj.(T)
+			*field(cc) = &v
+		},
+		add: func(QConf, string, any) error {
+			return fmt.Errorf("%s can not be added", kind)
+		},
+		modify: func(qc QConf, _ string, obj any) error {
+			return modify(qc, obj.(T))
+		},
+		del: func(QConf, string) error {
+			return fmt.Errorf("%s can not be deleted", kind)
+		},
+	}
+}
+
+// applyKinds lists all object kinds in the order AddAllEntries and
+// ModifyAllEntries process them. DeleteAllEnries uses the reverse order.
+var applyKinds = []kindOps{
+	mapKindOps(KindUserSetList,
+		func(cc *ClusterConfig) *map[string]UserSetListConfig { return &cc.UserSetLists },
+		QConf.AddUserSetList, QConf.ModifyUserset, QConf.DeleteUserSetList),
+	mapKindOps(KindProject,
+		func(cc *ClusterConfig) *map[string]ProjectConfig { return &cc.Projects },
+		func(qc QConf, _ string, p ProjectConfig) error { return qc.AddProject(p) },
+		QConf.ModifyProject,
+		func(qc QConf, name string) error { return qc.DeleteProject([]string{name}) }),
+	mapKindOps(KindUser,
+		func(cc *ClusterConfig) *map[string]UserConfig { return &cc.Users },
+		func(qc QConf, _ string, u UserConfig) error { return qc.AddUser(u) },
+		QConf.ModifyUser,
+		func(qc QConf, name string) error { return qc.DeleteUser([]string{name}) }),
+	listKindOps(KindManager,
+		func(cc *ClusterConfig) *[]string { return &cc.Managers },
+		QConf.AddUserToManagerList, QConf.DeleteUserFromManagerList),
+	listKindOps(KindOperator,
+		func(cc *ClusterConfig) *[]string { return &cc.Operators },
+		QConf.AddUserToOperatorList, QConf.DeleteUserFromOperatorList),
+	mapKindOps(KindHostConfiguration,
+		func(cc *ClusterConfig) *map[string]HostConfiguration { return &cc.HostConfigurations },
+		func(qc QConf, _ string, h HostConfiguration) error { return qc.AddHostConfiguration(h) },
+		QConf.ModifyHostConfiguration, QConf.DeleteHostConfiguration),
+	mapKindOps(KindHostGroup,
+		func(cc *ClusterConfig) *map[string]HostGroupConfig { return &cc.HostGroups },
+		func(qc QConf, _ string, hg HostGroupConfig) error { return qc.AddHostGroup(hg) },
+		QConf.ModifyHostGroup, QConf.DeleteHostGroup),
+	mapKindOps(KindExecHost,
+		func(cc *ClusterConfig) *map[string]HostExecConfig { return &cc.ExecHosts },
+		func(qc QConf, _ string, h HostExecConfig) error { return qc.AddExecHost(h) },
+		QConf.ModifyExecHost, QConf.DeleteExecHost),
+	mapKindOps(KindComplexEntry,
+		func(cc *ClusterConfig) *map[string]ComplexEntryConfig { return &cc.ComplexEntries },
+		func(qc QConf, _ string, e ComplexEntryConfig) error { return qc.AddComplexEntry(e) },
+		QConf.ModifyComplexEntry, QConf.DeleteComplexEntry),
+	mapKindOps(KindCalendar,
+		func(cc *ClusterConfig) *map[string]CalendarConfig { return &cc.Calendars },
+		func(qc QConf, _ string, c CalendarConfig) error { return qc.AddCalendar(c) },
+		QConf.ModifyCalendar, QConf.DeleteCalendar),
+	mapKindOps(KindCkptInterface,
+		func(cc *ClusterConfig) *map[string]CkptInterfaceConfig { return &cc.CkptInterfaces },
+		func(qc QConf, _ string, c CkptInterfaceConfig) error { return qc.AddCkptInterface(c) },
+		QConf.ModifyCkptInterface, QConf.DeleteCkptInterface),
+	listKindOps(KindAdminHost,
+		func(cc *ClusterConfig) *[]string { return &cc.AdminHosts },
+		QConf.AddAdminHost, QConf.DeleteAdminHost),
+	mapKindOps(KindResourceQuotaSet,
+		func(cc *ClusterConfig) *map[string]ResourceQuotaSetConfig { return &cc.ResourceQuotaSets },
+		func(qc QConf, _ string, r ResourceQuotaSetConfig) error { return qc.AddResourceQuotaSet(r) },
+		QConf.ModifyResourceQuotaSet, QConf.DeleteResourceQuotaSet),
+	mapKindOps(KindParallelEnvironment,
+		func(cc *ClusterConfig) *map[string]ParallelEnvironmentConfig { return &cc.ParallelEnvironments },
+		func(qc QConf, _ string, pe ParallelEnvironmentConfig) error { return qc.AddParallelEnvironment(pe) },
+		QConf.ModifyParallelEnvironment, QConf.DeleteParallelEnvironment),
+	mapKindOps(KindClusterQueue,
+		func(cc *ClusterConfig) *map[string]ClusterQueueConfig { return &cc.ClusterQueues },
+		func(qc QConf, _ string, q ClusterQueueConfig) error { return qc.AddClusterQueue(q) },
+		QConf.ModifyClusterQueue, QConf.DeleteClusterQueue),
+	singletonKindOps(KindGlobalConfig, "global",
+		func(cc *ClusterConfig) **GlobalConfig { return &cc.GlobalConfig },
+		QConf.ModifyGlobalConfig),
+	singletonKindOps(KindSchedulerConfig, "scheduler",
+		func(cc *ClusterConfig) **SchedulerConfig { return &cc.SchedulerConfig },
+		QConf.ModifySchedulerConfig),
+	listKindOps(KindSubmitHost,
+		func(cc *ClusterConfig) *[]string { return &cc.SubmitHosts },
+		QConf.AddSubmitHosts, QConf.DeleteSubmitHost),
+}
+
+// kindIndex returns the position of kind in applyKinds, or -1.
+func kindIndex(kind ObjectKind) int {
+	return slices.IndexFunc(applyKinds, func(ops kindOps) bool {
+		return ops.kind == kind
+	})
+}
+
+// kindOpsOf returns the operations for kind.
+func kindOpsOf(kind ObjectKind) (kindOps, bool) {
+	i := kindIndex(kind)
+	if i < 0 {
+		return kindOps{}, false
+	}
+	return applyKinds[i], true
+}
+
+// ObjectKinds returns all object kinds in the order Apply adds them.
+func ObjectKinds() []ObjectKind {
+	kinds := make([]ObjectKind, len(applyKinds))
+	for i, ops := range applyKinds {
+		kinds[i] = ops.kind
+	}
+	return kinds
+}
+
+// ReplaceKinds returns a configuration with the objects of base, except
+// for the objects of the given kinds which are taken from from. The
+// objects are shared with base and from, not copied. Comparing base with
+// the result restricts a change to some kinds:
+//
+//	target := ReplaceKinds(current, desired, []ObjectKind{KindClusterQueue})
+//	comparison, err := current.CompareTo(target)
+func ReplaceKinds(base, from ClusterConfig, kinds []ObjectKind) ClusterConfig {
+	result := ClusterConfig{ClusterEnvironment: base.ClusterEnvironment}
+	for _, ops := range applyKinds {
+		src := base
+		if slices.Contains(kinds, ops.kind) {
+			src = from
+		}
+		for _, name := range ops.names(src) {
+			obj, _ := ops.lookup(src, name)
+			ops.set(&result, name, obj)
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("ReplaceKinds", func() {
+
+	It("takes only the given kinds from the other configuration", func() {
+		base := core.ClusterConfig{
+			AdminHosts: []string{"master"},
+			HostGroups: map[string]core.HostGroupConfig{
+				"@all": {Name: "@all", Hosts: []string{"node1"}},
+			},
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q", Slots: []string{"1"}},
+				"old.q": {Name: "old.q"},
+			},
+		}
+		from := core.ClusterConfig{
+			HostGroups: map[string]core.HostGroupConfig{
+				"@all": {Name: "@all", Hosts: []string{"node2"}},
+			},
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q", Slots: []string{"4"}},
+			},
+		}
+		result := core.ReplaceKinds(base, from, []core.ObjectKind{core.KindClusterQueue})
+		Expect(result.AdminHosts).To(Equal([]string{"master"}))
+		Expect(result.HostGroups).To(Equal(base.HostGroups))
+		Expect(result.ClusterQueues).To(Equal(from.ClusterQueues))
+	})
+
+	It("lists all kinds", func() {
+		Expect(core.ObjectKinds()).To(ContainElements(core.KindClusterQueue, core.KindSubmitHost))
+		Expect(core.ObjectKinds()).To(HaveLen(18))
+	})
+})
@@ -0,0 +1,357 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ErrStalePlan is returned by ApplyPlan.Execute when an object the plan
+// touches was changed in the cluster after the plan was made.
+var ErrStalePlan = errors.New("apply plan is stale")
+
+// FieldChange is the value of a single field of a configuration object
+// before and after a planned change. Field is the JSON name of the field.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before,omitempty"`
+	After  any    `json:"after,omitempty"`
+}
+
+// PlanOperation is a single planned change of a configuration object.
+// Objects of list kinds, like managers, have no fields. Staged marks an
+// intermediate step which breaks a reference cycle; see AppliedChange.
+type PlanOperation struct {
+	Kind   ObjectKind    `json:"kind"`
+	Name   string        `json:"name"`
+	Action ChangeAction  `json:"action"`
+	Staged bool          `json:"staged,omitempty"`
+	Fields []FieldChange `json:"fields,omitempty"`
+}
+
+func (o PlanOperation) String() string {
+	if o.Staged {
+		return fmt.Sprintf("%s %s %s (staged)", o.Action, o.Kind, o.Name)
+	}
+	return fmt.Sprintf("%s %s %s", o.Action, o.Kind, o.Name)
+}
+
+// ApplyPlan is the ordered list of changes needed to turn the Current
+// configuration of a cluster into the Desired one, in the order Apply
+// runs them: adds, modifications and deletions ordered by the references
+// between the objects (see ApplyComparison).
+//
+// The plan carries both configurations, so it can be stored as JSON,
+// reviewed and executed later with exactly the reviewed operations.
+type ApplyPlan struct {
+	Operations []PlanOperation `json:"operations"`
+	Current    ClusterConfig   `json:"current"`
+	Desired    ClusterConfig   `json:"desired"`
+}
+
+// Plan compares the current configuration of the cluster with desired
+// and returns the changes Apply would make, without making them.
+func Plan(qc QConf, desired ClusterConfig) (*ApplyPlan, error) {
+	currentConfig, err := qc.GetClusterConfiguration()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current cluster configuration: %w", err)
+	}
+	comparison, err := currentConfig.CompareTo(desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare configurations: %w", err)
+	}
+	return newApplyPlan(currentConfig, desired, comparison)
+}
+
+func newApplyPlan(current, desired ClusterConfig,
+	comparison *ClusterConfigComparison) (*ApplyPlan, error) {
+
+	steps, err := orderedSteps(current, comparison)
+	if err != nil {
+		return nil, fmt.Errorf("failed to order changes: %w", err)
+	}
+	p := &ApplyPlan{Current: current, Desired: desired}
+	// state holds the objects as earlier steps left them
+	state := map[objectRef]any{}
+	for _, step := range steps {
+		ops, _ := kindOpsOf(step.change.Kind)
+		ref := objectRef{step.change.Kind, step.change.Name}
+		before, ok := state[ref]
+		if !ok {
+			before, _ = ops.lookup(current, ref.name)
+		}
+		var after any
+		switch step.change.Action {
+		case ActionAdd:
+			before, after = nil, step.obj
+		case ActionModify:
+			after = step.obj
+		}
+		state[ref] = after
+		fields, err := fieldChanges(before, after)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare %s %s: %w", ref.kind, ref.name, err)
+		}
+		p.Operations = append(p.Operations, PlanOperation{
+			Kind:   step.change.Kind,
+			Name:   step.change.Name,
+			Action: step.change.Action,
+			Staged: step.change.Staged,
+			Fields: fields,
+		})
+	}
+	return p, nil
+}
+
+// fieldChanges returns the fields which differ between before and after,
+// sorted by name. A nil object has no fields.
+func fieldChanges(before, after any) ([]FieldChange, error) {
+	b, err := objectFields(before)
+	if err != nil {
+		return nil, err
+	}
+	a, err := objectFields(after)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool, len(a))
+	for name := range b {
+		names[name] = true
+	}
+	for name := range a {
+		names[name] = true
+	}
+	var changes []FieldChange
+	for _, name := range sortedKeys(names) {
+		if reflect.DeepEqual(b[name], a[name]) {
+			continue
+		}
+		changes = append(changes, FieldChange{Field: name, Before: b[name], After: a[name]})
+	}
+	return changes, nil
+}
+
+// objectFields returns the JSON representation of obj as a map.
+func objectFields(obj any) (map[string]any, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// steps returns the steps which turn Current into Desired. They are
+// computed again from both configurations and must match the operations
+// of the plan.
+func (p *ApplyPlan) steps() ([]applyStep, error) {
+	comparison, err := p.Current.CompareTo(p.Desired)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compare configurations: %w", err)
+	}
+	steps, err := orderedSteps(p.Current, comparison)
+	if err != nil {
+		return nil, fmt.Errorf("failed to order changes: %w", err)
+	}
+	matches := len(steps) == len(p.Operations)
+	for i := 0; matches && i < len(steps); i++ {
+		op := p.Operations[i]
+		matches = steps[i].change == AppliedChange{
+			Kind: op.Kind, Name: op.Name, Action: op.Action, Staged: op.Staged}
+	}
+	if !matches {
+		return nil, errors.New("apply plan operations do not match its configurations")
+	}
+	return steps, nil
+}
+
+// Execute applies the operations of the plan like ApplyWithReport,
+// including the rollback on failure. It first reads the configuration
+// of the cluster again and fails with ErrStalePlan if an object touched
+// by the plan no longer matches the configuration the plan was made for.
+func (p *ApplyPlan) Execute(qc QConf) (*ApplyReport, error) {
+	steps, err := p.steps()
+	if err != nil {
+		return &ApplyReport{}, err
+	}
+	live, err := qc.GetClusterConfiguration()
+	if err != nil {
+		return &ApplyReport{}, fmt.Errorf("failed to get current cluster configuration: %w", err)
+	}
+	if err := p.checkStale(live); err != nil {
+		return &ApplyReport{}, err
+	}
+	return applySteps(qc, live, steps)
+}
+
+// checkStale compares every object touched by the plan in live with the
+// one the plan was made for. Objects are compared by their JSON form, so
+// a plan read back from JSON compares equal.
+func (p *ApplyPlan) checkStale(live ClusterConfig) error {
+	var stale []string
+	for _, op := range p.Operations {
+		ops, ok := kindOpsOf(op.Kind)
+		if !ok {
+			return fmt.Errorf("unknown object kind %q", op.Kind)
+		}
+		planned, plannedOK := ops.lookup(p.Current, op.Name)
+		actual, actualOK := ops.lookup(live, op.Name)
+		if plannedOK != actualOK {
+			stale = append(stale, fmt.Sprintf("%s %s", op.Kind, op.Name))
+			continue
+		}
+		plannedJSON, err := json.Marshal(planned)
+		if err != nil {
+			return err
+		}
+		actualJSON, err := json.Marshal(actual)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(plannedJSON, actualJSON) {
+			stale = append(stale, fmt.Sprintf("%s %s", op.Kind, op.Name))
+		}
+	}
+	if len(stale) > 0 {
+		return fmt.Errorf("%w: changed since the plan was made: %s",
+			ErrStalePlan, strings.Join(stale, ", "))
+	}
+	return nil
+}
+
+// ShellScript renders the plan as a POSIX shell script which runs the
+// same qconf commands as Execute, using executable as qconf binary.
+// Object files passed to qconf are embedded as here-documents. The
+// script stops at the first failing command; it does not roll back.
+func (p *ApplyPlan) ShellScript(executable string) (string, error) {
+	if executable == "" {
+		executable = "qconf"
+	}
+	steps, err := p.steps()
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	fmt.Fprintf(&b, "# %d planned operations\n", len(p.Operations))
+	b.WriteString("set -e\n")
+	b.WriteString("work=$(mktemp -d)\n")
+	b.WriteString("trap 'rm -rf \"$work\"' EXIT\n")
+
+	files := 0
+	rec := &CommandLineQConf{config: CommandLineQConfConfig{Executable: executable}}
+	rec.recorder = func(args []string) (string, error) {
+		words := []string{shellQuote(executable)}
+		for _, arg := range args {
+			content, ok := rec.objectFile(arg)
+			if !ok {
+				words = append(words, shellQuote(arg))
+				continue
+			}
+			files++
+			path := fmt.Sprintf("\"$work\"/%d/%s", files, shellQuote(filepath.Base(arg)))
+			delim := hereDocDelimiter(content)
+			fmt.Fprintf(&b, "mkdir \"$work\"/%d\n", files)
+			fmt.Fprintf(&b, "cat > %s <<'%s'\n%s", path, delim, content)
+			if !strings.HasSuffix(content, "\n") {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "%s\n", delim)
+			words = append(words, path)
+		}
+		b.WriteString(strings.Join(words, " ") + "\n")
+		return "", nil
+	}
+
+	for i, step := range steps {
+		fmt.Fprintf(&b, "\n# %s\n", p.Operations[i])
+		if err := step.run(rec); err != nil {
+			return "", fmt.Errorf("failed to render %s: %w", p.Operations[i], err)
+		}
+	}
+	return b.String(), nil
+}
+
+// objectFile returns the content of arg if it is an object file written
+// for a recorded command.
+func (c *CommandLineQConf) objectFile(arg string) (string, bool) {
+	if !c.objectFiles[arg] {
+		return "", false
+	}
+	content, err := os.ReadFile(arg)
+	if err != nil {
+		return "", false
+	}
+	return string(content), true
+}
+
+// hereDocDelimiter returns a here-document delimiter which does not
+// occur as a line of content.
+func hereDocDelimiter(content string) string {
+	lines := strings.Split(content, "\n")
+	sort.Strings(lines)
+	delim := "QCONF_EOF"
+	for i := 1; ; i++ {
+		idx := sort.SearchStrings(lines, delim)
+		if idx == len(lines) || lines[idx] != delim {
+			return delim
+		}
+		delim = fmt.Sprintf("QCONF_EOF_%d", i)
+	}
+}
+
+var shellSafeWord = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+// shellQuote quotes s as a single shell word unless it only consists of
+// characters which are never special to the shell.
+func shellQuote(s string) string {
+	if shellSafeWord.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// executableOf returns the qconf binary qc runs, or "qconf" for
+// implementations which do not run a binary.
+func executableOf(qc QConf) string {
+	if c, ok := qc.(interface{ executable() string }); ok && c.executable() != "" {
+		return c.executable()
+	}
+	return "qconf"
+}
+
+func (c *CommandLineQConf) executable() string {
+	return c.config.Executable
+}
@@ -0,0 +1,71 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("recorded object files", func() {
+
+	It("only treats the files written for a recorded command as object files", func() {
+		other, err := os.CreateTemp("", "complexes")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.Remove(other.Name())
+		_, err = other.WriteString("unrelated\n")
+		Expect(err).NotTo(HaveOccurred())
+		other.Close()
+
+		var recorded []string
+		rec := &CommandLineQConf{config: CommandLineQConfConfig{Executable: "qconf"}}
+		rec.recorder = func(args []string) (string, error) {
+			for _, arg := range args {
+				if content, ok := rec.objectFile(arg); ok {
+					recorded = append(recorded, content)
+				}
+			}
+			return "", nil
+		}
+
+		_, err = rec.RunCommand("-Mc", other.Name())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(recorded).To(BeEmpty())
+
+		Expect(rec.AddCalendar(CalendarConfig{Name: "night", Year: "NONE", Week: "NONE"})).To(Succeed())
+		Expect(recorded).To(HaveLen(1))
+		Expect(recorded[0]).To(MatchRegexp(`calendar_name\s+night`))
+	})
+
+})
+
+var _ = Describe("executableOf", func() {
+
+	It("names the qconf binary wrapped by a CachingQConf", func() {
+		qc := &CommandLineQConf{config: CommandLineQConfConfig{Executable: "/opt/cs/bin/qconf"}}
+		caching := NewCachingQConf(qc, CachingQConfConfig{})
+		Expect(executableOf(caching)).To(Equal("/opt/cs/bin/qconf"))
+		Expect(executableOf(caching.WithContext(context.Background()))).To(Equal("/opt/cs/bin/qconf"))
+	})
+
+})
@@ -0,0 +1,202 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core/internal/fakeqconf"
+)
+
+var _ = Describe("Plan", func() {
+
+	var (
+		qc      *core.InMemoryQConf
+		desired core.ClusterConfig
+	)
+
+	BeforeEach(func() {
+		qc = newInMemoryQConf(core.ClusterConfig{
+			HostGroups: map[string]core.HostGroupConfig{
+				"@rack1": {Name: "@rack1", Hosts: []string{"node1", "node2"}},
+			},
+			Calendars: map[string]core.CalendarConfig{
+				"night": {Name: "night", Year: "NONE", Week: "mon-fri=18-6"},
+			},
+		})
+		var err error
+		desired, err = qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		desired.HostGroups["@rack1"] = core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node1"}}
+		desired.ParallelEnvironments["mpi"] = core.ParallelEnvironmentConfig{
+			Name: "mpi", Slots: 16, AllocationRule: "$round_robin"}
+		desired.Managers = []string{"alice"}
+		delete(desired.Calendars, "night")
+	})
+
+	It("lists typed operations in apply order without changing the cluster", func() {
+		before, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+
+		plan, err := core.Plan(qc, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Operations).To(HaveLen(4))
+		Expect(plan.Operations[0].String()).To(Equal("add manager alice"))
+		Expect(plan.Operations[0].Fields).To(BeEmpty())
+		Expect(plan.Operations[1].String()).To(Equal("add parallel_environment mpi"))
+		Expect(plan.Operations[2].String()).To(Equal("modify host_group @rack1"))
+		Expect(plan.Operations[2].Fields).To(Equal([]core.FieldChange{{
+			Field:  "hostlist",
+			Before: []any{"node1", "node2"},
+			After:  []any{"node1"},
+		}}))
+		Expect(plan.Operations[3].String()).To(Equal("delete calendar night"))
+		Expect(plan.Operations[3].Fields).To(ContainElement(core.FieldChange{
+			Field: "week", Before: "mon-fri=18-6"}))
+
+		after, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+
+	It("executes a plan read back from JSON", func() {
+		plan, err := core.Plan(qc, desired)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := json.Marshal(plan)
+		Expect(err).NotTo(HaveOccurred())
+
+		var loaded core.ApplyPlan
+		Expect(json.Unmarshal(data, &loaded)).To(Succeed())
+		report, err := loaded.Execute(qc)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Applied).To(HaveLen(4))
+
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.HostGroups["@rack1"].Hosts).To(Equal([]string{"node1"}))
+		Expect(cc.ParallelEnvironments).To(HaveKey("mpi"))
+		Expect(cc.Managers).To(Equal([]string{"alice"}))
+		Expect(cc.Calendars).To(BeEmpty())
+	})
+
+	It("refuses to execute when a touched object changed", func() {
+		plan, err := core.Plan(qc, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qc.ModifyCalendar("night", core.CalendarConfig{Year: "NONE", Week: "NONE"})).To(Succeed())
+
+		_, err = plan.Execute(qc)
+		Expect(errors.Is(err, core.ErrStalePlan)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("calendar night"))
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.ParallelEnvironments).NotTo(HaveKey("mpi"))
+	})
+
+	It("ignores changes to objects the plan does not touch", func() {
+		plan, err := core.Plan(qc, desired)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qc.AddCalendar(core.CalendarConfig{Name: "day"})).To(Succeed())
+
+		_, err = plan.Execute(qc)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Context("as shell script", func() {
+
+		It("embeds object files and uses the given executable", func() {
+			plan, err := core.Plan(qc, desired)
+			Expect(err).NotTo(HaveOccurred())
+			script, err := plan.ShellScript("/opt/sge/bin/qconf")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(script).To(HavePrefix("#!/bin/sh\n"))
+			Expect(script).To(ContainSubstring("# add parallel_environment mpi\n"))
+			Expect(script).To(ContainSubstring("pe_name"))
+			Expect(script).To(ContainSubstring("/opt/sge/bin/qconf -am alice\n"))
+			Expect(script).To(ContainSubstring("/opt/sge/bin/qconf -dcal night\n"))
+		})
+
+		It("runs the planned qconf commands", func() {
+			if !fakeqconf.Available() {
+				Skip("fakeqconf uses a bash script; skip on this platform")
+			}
+			f := fakeqconf.New(GinkgoT(), "", 0)
+			defer f.Cleanup()
+
+			plan, err := core.Plan(qc, desired)
+			Expect(err).NotTo(HaveOccurred())
+			script, err := plan.ShellScript(f.Path())
+			Expect(err).NotTo(HaveOccurred())
+			path := filepath.Join(GinkgoT().TempDir(), "plan.sh")
+			Expect(os.WriteFile(path, []byte(script), 0o700)).To(Succeed())
+			out, err := exec.Command("sh", path).CombinedOutput()
+			Expect(err).NotTo(HaveOccurred(), string(out))
+
+			calls := f.AllArgvLines()
+			Expect(calls).To(HaveLen(4))
+			Expect(calls[0]).To(Equal("-am alice"))
+			Expect(calls[1]).To(HavePrefix("-Ap "))
+			Expect(calls[3]).To(Equal("-dcal night"))
+		})
+
+		It("is printed by Apply in dry-run mode with the caller's executable", func() {
+			if !fakeqconf.Available() {
+				Skip("fakeqconf uses a bash script; skip on this platform")
+			}
+			responses := clusterResponses()
+			responses["-sprj p7"] = fakeqconf.Response{Stdout: "name p7\n"}
+			responses["-scall"] = fakeqconf.Response{Stdout: "night\n"}
+			responses["-scal night"] = fakeqconf.Response{
+				Stdout: "calendar_name night\nyear NONE\nweek NONE\n"}
+			f := fakeqconf.NewDispatch(GinkgoT(), responses,
+				fakeqconf.Response{Stdout: "no object defined\n", RC: 1})
+			defer f.Cleanup()
+			cqc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{Executable: f.Path()})
+			Expect(err).NotTo(HaveOccurred())
+			cc, err := cqc.GetClusterConfiguration()
+			Expect(err).NotTo(HaveOccurred())
+			delete(cc.Calendars, "night")
+
+			r, w, err := os.Pipe()
+			Expect(err).NotTo(HaveOccurred())
+			stdout := os.Stdout
+			os.Stdout = w
+			err = core.Apply(cqc, cc, true)
+			os.Stdout = stdout
+			w.Close()
+			printed, _ := io.ReadAll(r)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(string(printed)).To(ContainSubstring(f.Path() + " -dcal night\n"))
+			for _, call := range f.AllArgvLines() {
+				Expect(strings.HasPrefix(call, "-dcal")).To(BeFalse())
+			}
+		})
+	})
+})
@@ -0,0 +1,192 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"slices"
+)
+
+// AppliedChange is a single object changed by ApplyWithReport.
+type AppliedChange struct {
+	Kind   ObjectKind   `json:"kind"`
+	Name   string       `json:"name"`
+	Action ChangeAction `json:"action"`
+	// Staged marks an intermediate step which breaks a reference cycle,
+	// like a modification which detaches an object before it is deleted.
+	Staged bool `json:"staged,omitempty"`
+}
+
+func (c AppliedChange) String() string {
+	if c.Staged {
+		return fmt.Sprintf("%s %s %s (staged)", c.Action, c.Kind, c.Name)
+	}
+	return fmt.Sprintf("%s %s %s", c.Action, c.Kind, c.Name)
+}
+
+// RevertFailure is an applied change which could not be reverted.
+type RevertFailure struct {
+	AppliedChange
+	Err error `json:"-"`
+}
+
+// ApplyReport describes what ApplyWithReport changed in the cluster. When
+// applying failed, Reverted lists the changes which were rolled back and
+// NotReverted the ones which are still in place.
+type ApplyReport struct {
+	Applied     []AppliedChange `json:"applied"`
+	Reverted    []AppliedChange `json:"reverted,omitempty"`
+	NotReverted []RevertFailure `json:"not_reverted,omitempty"`
+}
+
+// ApplyError is returned by ApplyWithReport when a change could not be
+// applied. Err is the original failure; Report tells how far the
+// rollback got.
+type ApplyError struct {
+	Err    error
+	Report *ApplyReport
+}
+
+func (e *ApplyError) Error() string {
+	if len(e.Report.NotReverted) > 0 {
+		return fmt.Sprintf("%v; rollback incomplete: %d of %d applied changes could not be reverted",
+			e.Err, len(e.Report.NotReverted), len(e.Report.Applied))
+	}
+	return fmt.Sprintf("%v; all %d applied changes were reverted", e.Err, len(e.Report.Applied))
+}
+
+func (e *ApplyError) Unwrap() error {
+	return e.Err
+}
+
+// journalEntry is an applied change together with the function which
+// reverts it.
+type journalEntry struct {
+	change AppliedChange
+	revert func(qc QConf) error
+}
+
+// revertStep returns the function which reverts step, using before as
+// before-image. A modification of an object which was added by an
+// earlier step is reverted by removing the object.
+func revertStep(step applyStep, before ClusterConfig, added bool) func(qc QConf) error {
+	ops, _ := kindOpsOf(step.change.Kind)
+	name := step.change.Name
+	switch step.change.Action {
+	case ActionAdd:
+		return func(qc QConf) error { return ops.del(qc, name) }
+	case ActionModify:
+		obj, existed := ops.lookup(before, name)
+		switch {
+		case added:
+			return func(QConf) error { return nil }
+		case ops.list && existed:
+			return func(QConf) error { return nil }
+		case ops.list:
+			return func(qc QConf) error { return ops.del(qc, name) }
+		case !existed:
+			return func(QConf) error {
+				return fmt.Errorf("%s %s is not in the before-image", step.change.Kind, name)
+			}
+		}
+		return func(qc QConf) error { return ops.modify(qc, name, obj) }
+	}
+	obj, _ := ops.lookup(before, name)
+	return func(qc QConf) error { return ops.add(qc, name, obj) }
+}
+
+// ApplyWithReport applies the differences between the current cluster
+// configuration and newConfig like Apply, but treats them as one
+// transaction: the current configuration is kept as before-image and when
+// an add, modify or delete fails, all changes applied so far are reverted
+// in reverse order. Reverting continues after errors; changes which could
+// not be reverted are listed in the report.
+//
+// On failure the returned error is an *ApplyError which holds the same
+// report.
+func ApplyWithReport(qc QConf, newConfig ClusterConfig) (*ApplyReport, error) {
+	currentConfig, err := qc.GetClusterConfiguration()
+	if err != nil {
+		return &ApplyReport{}, fmt.Errorf("failed to get current cluster configuration: %w", err)
+	}
+
+	comparison, err := currentConfig.CompareTo(newConfig)
+	if err != nil {
+		return &ApplyReport{}, fmt.Errorf("failed to compare configurations: %w", err)
+	}
+
+	return ApplyComparison(qc, currentConfig, comparison)
+}
+
+// ApplyComparison applies comparison, made by comparing currentConfig
+// with a desired configuration, to the cluster like ApplyWithReport.
+// The changes are applied object by object in the order of the references
+// between the objects, so queues are detached from parallel environments
+// before these are deleted and host groups are added before the queues
+// using them. On failure the cluster is rolled back to currentConfig.
+func ApplyComparison(qc QConf, currentConfig ClusterConfig,
+	comparison *ClusterConfigComparison) (*ApplyReport, error) {
+
+	steps, err := orderedSteps(currentConfig, comparison)
+	if err != nil {
+		return &ApplyReport{}, fmt.Errorf("failed to order changes: %w", err)
+	}
+	return applySteps(qc, currentConfig, steps)
+}
+
+// applySteps runs steps one after the other and rolls back to
+// currentConfig on failure.
+func applySteps(qc QConf, currentConfig ClusterConfig, steps []applyStep) (*ApplyReport, error) {
+	report := &ApplyReport{}
+	var journal []journalEntry
+	added := map[objectRef]bool{}
+	for _, step := range steps {
+		ref := objectRef{step.change.Kind, step.change.Name}
+		if err := step.run(qc); err != nil {
+			for _, entry := range journal {
+				report.Applied = append(report.Applied, entry.change)
+			}
+			for _, entry := range slices.Backward(journal) {
+				if rerr := entry.revert(qc); rerr != nil {
+					report.NotReverted = append(report.NotReverted,
+						RevertFailure{AppliedChange: entry.change, Err: rerr})
+					continue
+				}
+				report.Reverted = append(report.Reverted, entry.change)
+			}
+			return report, &ApplyError{
+				Err:    fmt.Errorf("failed to %s: %w", step.change, err),
+				Report: report,
+			}
+		}
+		journal = append(journal, journalEntry{
+			change: step.change,
+			revert: revertStep(step, currentConfig, added[ref]),
+		})
+		if step.change.Action == ActionAdd {
+			added[ref] = true
+		}
+	}
+
+	for _, entry := range journal {
+		report.Applied = append(report.Applied, entry.change)
+	}
+	return report, nil
+}
@@ -0,0 +1,196 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var errInjected = errors.New("injected failure")
+
+// failingQConf is an InMemoryQConf on which selected calls fail.
+type failingQConf struct {
+	*core.InMemoryQConf
+	fail map[string]bool
+}
+
+func (f *failingQConf) AddClusterQueue(q core.ClusterQueueConfig) error {
+	if f.fail["AddClusterQueue"] {
+		return errInjected
+	}
+	return f.InMemoryQConf.AddClusterQueue(q)
+}
+
+func (f *failingQConf) DeleteCalendar(name string) error {
+	if f.fail["DeleteCalendar"] {
+		return errInjected
+	}
+	return f.InMemoryQConf.DeleteCalendar(name)
+}
+
+func (f *failingQConf) DeleteParallelEnvironment(name string) error {
+	if f.fail["DeleteParallelEnvironment"] {
+		return errInjected
+	}
+	return f.InMemoryQConf.DeleteParallelEnvironment(name)
+}
+
+var _ = Describe("ApplyWithReport", func() {
+
+	var (
+		qc     *failingQConf
+		before core.ClusterConfig
+	)
+
+	// desired returns the current configuration of the cluster changed
+	// by change.
+	desired := func(change func(cc *core.ClusterConfig)) core.ClusterConfig {
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		change(&cc)
+		return cc
+	}
+
+	BeforeEach(func() {
+		qc = &failingQConf{
+			InMemoryQConf: newInMemoryQConf(core.ClusterConfig{
+				HostGroups: map[string]core.HostGroupConfig{
+					"@rack1": {Name: "@rack1", Hosts: []string{"node1", "node2"}},
+				},
+				Calendars: map[string]core.CalendarConfig{
+					"night": {Name: "night", Year: "NONE", Week: "mon-fri=18-6"},
+				},
+				ParallelEnvironments: map[string]core.ParallelEnvironmentConfig{
+					"old.pe": {Name: "old.pe", Slots: 8, AllocationRule: "$pe_slots",
+						StartProcArgs: "/bin/true", StopProcArgs: "/bin/true",
+						ControlSlaves: "FALSE", UrgencySlots: "min"},
+				},
+			}),
+			fail: map[string]bool{},
+		}
+		var err error
+		before, err = qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("reports all applied changes on success", func() {
+		report, err := core.ApplyWithReport(qc, desired(func(cc *core.ClusterConfig) {
+			cc.Projects = map[string]core.ProjectConfig{
+				"p1": {Name: "p1", ACL: []string{"NONE"}, XACL: []string{"NONE"}},
+			}
+			cc.HostGroups["@rack1"] = core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node1"}}
+			delete(cc.Calendars, "night")
+		}))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(report.Applied).To(Equal([]core.AppliedChange{
+			{Kind: core.KindProject, Name: "p1", Action: core.ActionAdd},
+			{Kind: core.KindHostGroup, Name: "@rack1", Action: core.ActionModify},
+			{Kind: core.KindCalendar, Name: "night", Action: core.ActionDelete},
+		}))
+		Expect(report.Reverted).To(BeEmpty())
+	})
+
+	It("removes added objects when a later add fails", func() {
+		qc.fail["AddClusterQueue"] = true
+		report, err := core.ApplyWithReport(qc, desired(func(cc *core.ClusterConfig) {
+			cc.ParallelEnvironments["mpi"] = core.ParallelEnvironmentConfig{
+				Name: "mpi", Slots: 16, AllocationRule: "$round_robin"}
+			cc.ClusterQueues = map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q", PeList: []string{"mpi"}},
+			}
+		}))
+		Expect(errors.Is(err, errInjected)).To(BeTrue())
+		var applyErr *core.ApplyError
+		Expect(errors.As(err, &applyErr)).To(BeTrue())
+		Expect(applyErr.Report).To(BeIdenticalTo(report))
+
+		mpiAdd := core.AppliedChange{Kind: core.KindParallelEnvironment, Name: "mpi", Action: core.ActionAdd}
+		Expect(report.Applied).To(Equal([]core.AppliedChange{mpiAdd}))
+		Expect(report.Reverted).To(Equal([]core.AppliedChange{mpiAdd}))
+		Expect(report.NotReverted).To(BeEmpty())
+
+		after, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+
+	It("restores modified and deleted objects when a delete fails", func() {
+		qc.fail["DeleteCalendar"] = true
+		report, err := core.ApplyWithReport(qc, desired(func(cc *core.ClusterConfig) {
+			cc.Projects = map[string]core.ProjectConfig{
+				"p1": {Name: "p1", ACL: []string{"NONE"}, XACL: []string{"NONE"}},
+			}
+			cc.HostGroups["@rack1"] = core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node3"}}
+			delete(cc.ParallelEnvironments, "old.pe")
+			delete(cc.Calendars, "night")
+		}))
+		Expect(errors.Is(err, errInjected)).To(BeTrue())
+		Expect(err.Error()).To(ContainSubstring("all 3 applied changes were reverted"))
+		Expect(report.Reverted).To(Equal([]core.AppliedChange{
+			{Kind: core.KindParallelEnvironment, Name: "old.pe", Action: core.ActionDelete},
+			{Kind: core.KindHostGroup, Name: "@rack1", Action: core.ActionModify},
+			{Kind: core.KindProject, Name: "p1", Action: core.ActionAdd},
+		}))
+
+		after, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+
+	It("reports changes which could not be reverted", func() {
+		qc.fail["AddClusterQueue"] = true
+		qc.fail["DeleteParallelEnvironment"] = true
+		report, err := core.ApplyWithReport(qc, desired(func(cc *core.ClusterConfig) {
+			cc.ParallelEnvironments["mpi"] = core.ParallelEnvironmentConfig{
+				Name: "mpi", Slots: 16, AllocationRule: "$round_robin"}
+			cc.ClusterQueues = map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q"},
+			}
+		}))
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("rollback incomplete"))
+		Expect(report.Reverted).To(BeEmpty())
+		Expect(report.NotReverted).To(HaveLen(1))
+		Expect(report.NotReverted[0].Name).To(Equal("mpi"))
+		Expect(report.NotReverted[0].Err).To(MatchError(errInjected))
+	})
+
+	It("is used by Apply", func() {
+		qc.fail["AddClusterQueue"] = true
+		err := core.Apply(qc, desired(func(cc *core.ClusterConfig) {
+			cc.Managers = append(cc.Managers, "alice")
+			cc.ClusterQueues = map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q"},
+			}
+		}), false)
+		var applyErr *core.ApplyError
+		Expect(errors.As(err, &applyErr)).To(BeTrue())
+		Expect(applyErr.Report.Reverted).To(HaveLen(1))
+
+		after, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(after).To(Equal(before))
+	})
+})
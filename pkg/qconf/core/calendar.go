@@ -0,0 +1,428 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CalendarState is the state a calendar puts the queues it is attached
+// to into.
+type CalendarState string
+
+const (
+	CalendarOn        CalendarState = "on"
+	CalendarOff       CalendarState = "off"
+	CalendarSuspended CalendarState = "suspended"
+)
+
+// Calendar is a parsed calendar (sge_calendar(5)). Year entries take
+// precedence over week entries; at times matched by neither the
+// calendar is on.
+type Calendar struct {
+	Name string              `json:"calendar_name"`
+	Year []CalendarYearEntry `json:"year,omitempty"`
+	Week []CalendarWeekEntry `json:"week,omitempty"`
+}
+
+// CalendarYearEntry is an entry of the year field, like
+// "24.12.2026-26.12.2026=off". Without Days the entry applies to every
+// day, without Times to the whole day.
+type CalendarYearEntry struct {
+	Days  []CalendarDateRange `json:"days,omitempty"`
+	Times []CalendarTimeRange `json:"times,omitempty"`
+	State CalendarState       `json:"state"`
+}
+
+// CalendarWeekEntry is an entry of the week field, like
+// "mon-fri=8-18=on". Without Days the entry applies to every day of the
+// week, without Times to the whole day.
+type CalendarWeekEntry struct {
+	Days  []CalendarWeekdayRange `json:"days,omitempty"`
+	Times []CalendarTimeRange    `json:"times,omitempty"`
+	State CalendarState          `json:"state"`
+}
+
+// CalendarDate is a day of a year field. A zero Year stands for the day
+// in every year.
+type CalendarDate struct {
+	Day   int        `json:"day"`
+	Month time.Month `json:"month"`
+	Year  int        `json:"year,omitempty"`
+}
+
+// CalendarDateRange is an inclusive range of days. A single day has
+// equal From and To.
+type CalendarDateRange struct {
+	From CalendarDate `json:"from"`
+	To   CalendarDate `json:"to"`
+}
+
+// CalendarWeekdayRange is an inclusive range of days of the week, which
+// may wrap around the end of the week like "fri-mon".
+type CalendarWeekdayRange struct {
+	From time.Weekday `json:"from"`
+	To   time.Weekday `json:"to"`
+}
+
+// CalendarTimeRange is a range of the time of day, from From up to but
+// excluding To, as offsets from midnight. A range whose To is before its
+// From, like "20-6", covers the evening and the morning of the day.
+type CalendarTimeRange struct {
+	From time.Duration `json:"from"`
+	To   time.Duration `json:"to"`
+}
+
+var calendarMonths = []string{"jan", "feb", "mar", "apr", "may", "jun",
+	"jul", "aug", "sep", "oct", "nov", "dec"}
+
+var calendarWeekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseCalendar parses the year and week fields of cfg.
+func ParseCalendar(cfg CalendarConfig) (Calendar, error) {
+	year, err := ParseCalendarYear(cfg.Year)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("calendar %s: %w", cfg.Name, err)
+	}
+	week, err := ParseCalendarWeek(cfg.Week)
+	if err != nil {
+		return Calendar{}, fmt.Errorf("calendar %s: %w", cfg.Name, err)
+	}
+	return Calendar{Name: cfg.Name, Year: year, Week: week}, nil
+}
+
+// Config returns c in the text form of CalendarConfig.
+func (c Calendar) Config() CalendarConfig {
+	return CalendarConfig{
+		Name: c.Name,
+		Year: FormatCalendarYear(c.Year),
+		Week: FormatCalendarWeek(c.Week),
+	}
+}
+
+// ParseCalendarYear parses a year field like
+// "1.1.2026=off 24.12.2026-26.12.2026=8-12=on". "NONE" or an empty
+// string has no entries.
+func ParseCalendarYear(s string) ([]CalendarYearEntry, error) {
+	var entries []CalendarYearEntry
+	err := parseCalendarEntries(s, "year", func(days string, times []CalendarTimeRange, state CalendarState) error {
+		e := CalendarYearEntry{Times: times, State: state}
+		if days != "" {
+			for _, r := range strings.Split(days, ",") {
+				dr, err := parseCalendarDateRange(r)
+				if err != nil {
+					return err
+				}
+				e.Days = append(e.Days, dr)
+			}
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+// ParseCalendarWeek parses a week field like
+// "mon-fri=18-24,0-6=on sat-sun=on". "NONE" or an empty string has no
+// entries.
+func ParseCalendarWeek(s string) ([]CalendarWeekEntry, error) {
+	var entries []CalendarWeekEntry
+	err := parseCalendarEntries(s, "week", func(days string, times []CalendarTimeRange, state CalendarState) error {
+		e := CalendarWeekEntry{Times: times, State: state}
+		if days != "" {
+			for _, r := range strings.Split(days, ",") {
+				from, to, ok := strings.Cut(r, "-")
+				if !ok {
+					to = from
+				}
+				f, err := parseCalendarWeekday(from)
+				if err != nil {
+					return err
+				}
+				t, err := parseCalendarWeekday(to)
+				if err != nil {
+					return err
+				}
+				e.Days = append(e.Days, CalendarWeekdayRange{From: f, To: t})
+			}
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+// parseCalendarEntries splits the space-separated entries of a year or
+// week field into their day list, time ranges and state and passes them
+// to add. The parts of an entry are told apart by their content: day
+// lists contain dates or weekday names, time ranges only digits, ":"
+// and "-". A missing state means off.
+func parseCalendarEntries(s, field string, add func(days string, times []CalendarTimeRange, state CalendarState) error) error {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "NONE") {
+		return nil
+	}
+	for _, entry := range strings.Fields(s) {
+		parts := strings.Split(strings.ToLower(entry), "=")
+		if len(parts) > 3 {
+			return fmt.Errorf("invalid %s entry %q: too many parts", field, entry)
+		}
+		var days string
+		var times []CalendarTimeRange
+		state := CalendarOff
+		for i, part := range parts {
+			switch {
+			case part == string(CalendarOn) || part == string(CalendarOff) || part == string(CalendarSuspended):
+				if i != len(parts)-1 {
+					return fmt.Errorf("invalid %s entry %q: state must come last", field, entry)
+				}
+				state = CalendarState(part)
+			case strings.Trim(part, "0123456789:-,") == "" && !strings.Contains(part, "."):
+				if times != nil {
+					return fmt.Errorf("invalid %s entry %q: two time ranges", field, entry)
+				}
+				var err error
+				if times, err = parseCalendarTimeRanges(part); err != nil {
+					return fmt.Errorf("invalid %s entry %q: %w", field, entry, err)
+				}
+			default:
+				if i != 0 {
+					return fmt.Errorf("invalid %s entry %q: days must come first", field, entry)
+				}
+				days = part
+			}
+		}
+		if err := add(days, times, state); err != nil {
+			return fmt.Errorf("invalid %s entry %q: %w", field, entry, err)
+		}
+	}
+	return nil
+}
+
+func parseCalendarDateRange(s string) (CalendarDateRange, error) {
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		to = from
+	}
+	f, err := parseCalendarDate(from)
+	if err != nil {
+		return CalendarDateRange{}, err
+	}
+	t, err := parseCalendarDate(to)
+	if err != nil {
+		return CalendarDateRange{}, err
+	}
+	if (f.Year == 0) != (t.Year == 0) {
+		return CalendarDateRange{}, fmt.Errorf("date range %q mixes dates with and without year", s)
+	}
+	if f.Year != 0 && f.after(t) {
+		return CalendarDateRange{}, fmt.Errorf("date range %q ends before it starts", s)
+	}
+	return CalendarDateRange{From: f, To: t}, nil
+}
+
+// parseCalendarDate parses a day like "24.12.2026", "24.dec.2026" or,
+// for every year, "24.12".
+func parseCalendarDate(s string) (CalendarDate, error) {
+	fields := strings.Split(s, ".")
+	if len(fields) < 2 || len(fields) > 3 {
+		return CalendarDate{}, fmt.Errorf("invalid date %q", s)
+	}
+	var d CalendarDate
+	var err error
+	if d.Day, err = strconv.Atoi(fields[0]); err != nil {
+		return CalendarDate{}, fmt.Errorf("invalid day in date %q", s)
+	}
+	if m, err := strconv.Atoi(fields[1]); err == nil {
+		d.Month = time.Month(m)
+	} else {
+		for i, name := range calendarMonths {
+			if fields[1] == name {
+				d.Month = time.Month(i + 1)
+			}
+		}
+	}
+	if d.Month < time.January || d.Month > time.December {
+		return CalendarDate{}, fmt.Errorf("invalid month in date %q", s)
+	}
+	year := 2000 // a leap year, so 29.2 is valid without a year
+	if len(fields) == 3 {
+		if d.Year, err = strconv.Atoi(fields[2]); err != nil || d.Year < 1970 {
+			return CalendarDate{}, fmt.Errorf("invalid year in date %q", s)
+		}
+		year = d.Year
+	}
+	if d.Day < 1 || time.Date(year, d.Month, d.Day, 0, 0, 0, 0, time.UTC).Day() != d.Day {
+		return CalendarDate{}, fmt.Errorf("invalid day in date %q", s)
+	}
+	return d, nil
+}
+
+func parseCalendarWeekday(s string) (time.Weekday, error) {
+	for i, name := range calendarWeekdays {
+		if s == name {
+			return time.Weekday(i), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of the week %q", s)
+}
+
+func parseCalendarTimeRanges(s string) ([]CalendarTimeRange, error) {
+	var ranges []CalendarTimeRange
+	for _, r := range strings.Split(s, ",") {
+		from, to, ok := strings.Cut(r, "-")
+		if !ok {
+			return nil, fmt.Errorf("invalid time range %q", r)
+		}
+		f, err := parseCalendarTime(from)
+		if err != nil {
+			return nil, err
+		}
+		t, err := parseCalendarTime(to)
+		if err != nil {
+			return nil, err
+		}
+		if f == t {
+			return nil, fmt.Errorf("empty time range %q", r)
+		}
+		ranges = append(ranges, CalendarTimeRange{From: f, To: t})
+	}
+	return ranges, nil
+}
+
+// parseCalendarTime parses a time of day like "8", "8:30" or "8:30:15".
+func parseCalendarTime(s string) (time.Duration, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) > 3 {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	limits := []int{24, 59, 59}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		if i >= len(fields) {
+			break
+		}
+		v, err := strconv.Atoi(fields[i])
+		if err != nil || v < 0 || v > limits[i] {
+			return 0, fmt.Errorf("invalid time %q", s)
+		}
+		d += time.Duration(v) * unit
+	}
+	if d > 24*time.Hour {
+		return 0, fmt.Errorf("invalid time %q", s)
+	}
+	return d, nil
+}
+
+// FormatCalendarYear returns the text form of a year field, "NONE"
+// without entries. States are always written, also the default off.
+func FormatCalendarYear(entries []CalendarYearEntry) string {
+	if len(entries) == 0 {
+		return "NONE"
+	}
+	words := make([]string, 0, len(entries))
+	for _, e := range entries {
+		var days []string
+		for _, r := range e.Days {
+			if r.From == r.To {
+				days = append(days, r.From.String())
+			} else {
+				days = append(days, r.From.String()+"-"+r.To.String())
+			}
+		}
+		words = append(words, formatCalendarEntry(days, e.Times, e.State))
+	}
+	return strings.Join(words, " ")
+}
+
+// FormatCalendarWeek returns the text form of a week field, "NONE"
+// without entries. States are always written, also the default off.
+func FormatCalendarWeek(entries []CalendarWeekEntry) string {
+	if len(entries) == 0 {
+		return "NONE"
+	}
+	words := make([]string, 0, len(entries))
+	for _, e := range entries {
+		var days []string
+		for _, r := range e.Days {
+			day := calendarWeekdays[r.From]
+			if r.From != r.To {
+				day += "-" + calendarWeekdays[r.To]
+			}
+			days = append(days, day)
+		}
+		words = append(words, formatCalendarEntry(days, e.Times, e.State))
+	}
+	return strings.Join(words, " ")
+}
+
+func formatCalendarEntry(days []string, times []CalendarTimeRange, state CalendarState) string {
+	var parts []string
+	if len(days) > 0 {
+		parts = append(parts, strings.Join(days, ","))
+	}
+	if len(times) > 0 {
+		ranges := make([]string, 0, len(times))
+		for _, r := range times {
+			ranges = append(ranges, formatCalendarTime(r.From)+"-"+formatCalendarTime(r.To))
+		}
+		parts = append(parts, strings.Join(ranges, ","))
+	}
+	if state == "" {
+		state = CalendarOff
+	}
+	return strings.Join(append(parts, string(state)), "=")
+}
+
+// formatCalendarTime writes a time of day as short as possible: "8",
+// "8:30" or "8:30:15".
+func formatCalendarTime(d time.Duration) string {
+	h, m, s := int(d/time.Hour), int(d%time.Hour/time.Minute), int(d%time.Minute/time.Second)
+	switch {
+	case s != 0:
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	case m != 0:
+		return fmt.Sprintf("%d:%02d", h, m)
+	}
+	return strconv.Itoa(h)
+}
+
+// String returns d like "24.12.2026", or "24.12" without a year.
+func (d CalendarDate) String() string {
+	if d.Year == 0 {
+		return fmt.Sprintf("%d.%d", d.Day, d.Month)
+	}
+	return fmt.Sprintf("%d.%d.%d", d.Day, d.Month, d.Year)
+}
+
+// after reports whether d is a later day than o. Without years only
+// month and day are compared.
+func (d CalendarDate) after(o CalendarDate) bool {
+	if d.Year != o.Year {
+		return d.Year > o.Year
+	}
+	if d.Month != o.Month {
+		return d.Month > o.Month
+	}
+	return d.Day > o.Day
+}
@@ -0,0 +1,171 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"sort"
+	"time"
+)
+
+// StateAt returns the state c puts a queue into at t, evaluated in the
+// location of t. The first matching year entry decides; without one the
+// first matching week entry; without either the queue is on.
+func (c Calendar) StateAt(t time.Time) CalendarState {
+	date := CalendarDate{Day: t.Day(), Month: t.Month(), Year: t.Year()}
+	tod := timeOfDay(t)
+	for _, e := range c.Year {
+		if (len(e.Days) == 0 || anyDateRange(e.Days, date)) && inTimeRanges(e.Times, tod) {
+			return e.State
+		}
+	}
+	for _, e := range c.Week {
+		if (len(e.Days) == 0 || anyWeekdayRange(e.Days, t.Weekday())) && inTimeRanges(e.Times, tod) {
+			return e.State
+		}
+	}
+	return CalendarOn
+}
+
+// NextTransition returns the first time after t at which the state of c
+// changes, and the state from then on. It reports false when the state
+// never changes again.
+func (c Calendar) NextTransition(t time.Time) (time.Time, CalendarState, bool) {
+	current := c.StateAt(t)
+	// Week entries repeat every week, so their changes show within 8
+	// days after t. Dates repeat every year; with them the search goes
+	// on for a year after t, or until the end of the last year named in
+	// a date.
+	end := t.AddDate(0, 0, 8)
+	for _, e := range c.Year {
+		if len(e.Days) == 0 {
+			continue
+		}
+		for _, r := range e.Days {
+			last := t.AddDate(1, 0, 1)
+			if r.To.Year != 0 {
+				last = time.Date(r.To.Year+1, time.January, 1, 0, 0, 0, 0, t.Location())
+			}
+			if last.After(end) {
+				end = last
+			}
+		}
+	}
+	times := c.transitionTimes()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	for ; !day.After(end); day = day.AddDate(0, 0, 1) {
+		for _, tod := range times {
+			candidate := atTimeOfDay(day, tod)
+			if !candidate.After(t) {
+				continue
+			}
+			if state := c.StateAt(candidate); state != current {
+				return candidate, state, true
+			}
+		}
+	}
+	return time.Time{}, "", false
+}
+
+// transitionTimes returns the sorted times of day at which a state can
+// change: midnight and the bounds of all time ranges.
+func (c Calendar) transitionTimes() []time.Duration {
+	seen := map[time.Duration]bool{0: true}
+	add := func(ranges []CalendarTimeRange) {
+		for _, r := range ranges {
+			seen[r.From%(24*time.Hour)] = true
+			seen[r.To%(24*time.Hour)] = true
+		}
+	}
+	for _, e := range c.Year {
+		add(e.Times)
+	}
+	for _, e := range c.Week {
+		add(e.Times)
+	}
+	times := make([]time.Duration, 0, len(seen))
+	for d := range seen {
+		times = append(times, d)
+	}
+	sort.Slice(times, func(i, j int) bool { return times[i] < times[j] })
+	return times
+}
+
+func timeOfDay(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+// atTimeOfDay returns the wall clock time tod on day, which is a
+// midnight.
+func atTimeOfDay(day time.Time, tod time.Duration) time.Time {
+	return time.Date(day.Year(), day.Month(), day.Day(),
+		int(tod/time.Hour), int(tod%time.Hour/time.Minute), int(tod%time.Minute/time.Second), 0, day.Location())
+}
+
+func inTimeRanges(ranges []CalendarTimeRange, tod time.Duration) bool {
+	if len(ranges) == 0 {
+		return true
+	}
+	for _, r := range ranges {
+		if r.From < r.To && tod >= r.From && tod < r.To {
+			return true
+		}
+		if r.From > r.To && (tod >= r.From || tod < r.To) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyDateRange(ranges []CalendarDateRange, d CalendarDate) bool {
+	for _, r := range ranges {
+		if r.From.Year == 0 {
+			// yearly range, which may wrap around the end of the year
+			day := CalendarDate{Day: d.Day, Month: d.Month}
+			if r.To.after(r.From) || r.To == r.From {
+				if !r.From.after(day) && !day.after(r.To) {
+					return true
+				}
+			} else if !r.From.after(day) || !day.after(r.To) {
+				return true
+			}
+			continue
+		}
+		if !r.From.after(d) && !d.after(r.To) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyWeekdayRange(ranges []CalendarWeekdayRange, wd time.Weekday) bool {
+	// count the week from monday, like the calendar does
+	pos := func(d time.Weekday) int { return (int(d) + 6) % 7 }
+	for _, r := range ranges {
+		from, to, day := pos(r.From), pos(r.To), pos(wd)
+		if from <= to && day >= from && day <= to {
+			return true
+		}
+		if from > to && (day >= from || day <= to) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,175 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("Calendar", func() {
+
+	at := func(s string) time.Time {
+		t, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+		Expect(err).NotTo(HaveOccurred())
+		return t
+	}
+
+	Context("parsing", func() {
+
+		It("parses year and week entries", func() {
+			c, err := core.ParseCalendar(core.CalendarConfig{
+				Name: "night",
+				Year: "1.1.2027=off 24.dec.2026-26.12.2026=8-12:30=on",
+				Week: "Mon-Fri=6-20 sat,sun=suspended",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Year).To(Equal([]core.CalendarYearEntry{
+				{Days: []core.CalendarDateRange{{
+					From: core.CalendarDate{Day: 1, Month: time.January, Year: 2027},
+					To:   core.CalendarDate{Day: 1, Month: time.January, Year: 2027},
+				}}, State: core.CalendarOff},
+				{Days: []core.CalendarDateRange{{
+					From: core.CalendarDate{Day: 24, Month: time.December, Year: 2026},
+					To:   core.CalendarDate{Day: 26, Month: time.December, Year: 2026},
+				}}, Times: []core.CalendarTimeRange{{From: 8 * time.Hour, To: 12*time.Hour + 30*time.Minute}},
+					State: core.CalendarOn},
+			}))
+			Expect(c.Week).To(Equal([]core.CalendarWeekEntry{
+				{Days: []core.CalendarWeekdayRange{{From: time.Monday, To: time.Friday}},
+					Times: []core.CalendarTimeRange{{From: 6 * time.Hour, To: 20 * time.Hour}},
+					State: core.CalendarOff},
+				{Days: []core.CalendarWeekdayRange{{From: time.Saturday, To: time.Saturday},
+					{From: time.Sunday, To: time.Sunday}}, State: core.CalendarSuspended},
+			}))
+			Expect(c.Config()).To(Equal(core.CalendarConfig{
+				Name: "night",
+				Year: "1.1.2027=off 24.12.2026-26.12.2026=8-12:30=on",
+				Week: "mon-fri=6-20=off sat,sun=suspended",
+			}))
+		})
+
+		It("accepts NONE and entries without days", func() {
+			c, err := core.ParseCalendar(core.CalendarConfig{Name: "c", Year: "NONE", Week: "20-6=on"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(c.Year).To(BeEmpty())
+			Expect(c.Week).To(Equal([]core.CalendarWeekEntry{{
+				Times: []core.CalendarTimeRange{{From: 20 * time.Hour, To: 6 * time.Hour}},
+				State: core.CalendarOn}}))
+			Expect(core.FormatCalendarYear(c.Year)).To(Equal("NONE"))
+		})
+
+		DescribeTable("rejects invalid fields",
+			func(year, week, message string) {
+				_, err := core.ParseCalendar(core.CalendarConfig{Name: "c", Year: year, Week: week})
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("invalid day", "30.2.2026=off", "NONE", `invalid day in date "30.2.2026"`),
+			Entry("invalid month", "1.13.2026", "NONE", "invalid month"),
+			Entry("reversed date range", "2.1.2026-1.1.2026", "NONE", "ends before it starts"),
+			Entry("unknown weekday", "NONE", "mon-fry=on", `invalid day of the week "fry"`),
+			Entry("invalid hour", "NONE", "mon=6-25", `invalid time "25"`),
+			Entry("empty time range", "NONE", "mon=6-6", "empty time range"),
+			Entry("state not last", "NONE", "mon=on=6-8", "state must come last"),
+			Entry("too many parts", "NONE", "mon=6-8=on=off", "too many parts"),
+		)
+	})
+
+	Context("state", func() {
+
+		var c core.Calendar
+
+		BeforeEach(func() {
+			var err error
+			c, err = core.ParseCalendar(core.CalendarConfig{
+				Name: "office",
+				Year: "24.12-26.12=off",
+				Week: "mon-fri=8-18=on mon-fri=off sat-sun=suspended",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("evaluates year entries before week entries", func() {
+			Expect(c.StateAt(at("2026-10-16 09:00"))).To(Equal(core.CalendarOn))        // friday
+			Expect(c.StateAt(at("2026-10-16 18:00"))).To(Equal(core.CalendarOff))       // friday evening
+			Expect(c.StateAt(at("2026-10-17 12:00"))).To(Equal(core.CalendarSuspended)) // saturday
+			Expect(c.StateAt(at("2026-12-24 12:00"))).To(Equal(core.CalendarOff))       // thursday, holiday
+			Expect(core.Calendar{}.StateAt(at("2026-10-16 09:00"))).To(Equal(core.CalendarOn))
+		})
+
+		It("finds the next transition", func() {
+			next, state, ok := c.NextTransition(at("2026-10-16 09:00"))
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2026-10-16 18:00")))
+			Expect(state).To(Equal(core.CalendarOff))
+
+			next, state, ok = c.NextTransition(next)
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2026-10-17 00:00")))
+			Expect(state).To(Equal(core.CalendarSuspended))
+
+			next, state, ok = c.NextTransition(at("2026-12-23 12:00"))
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2026-12-23 18:00")))
+			Expect(state).To(Equal(core.CalendarOff))
+			next, state, ok = c.NextTransition(next)
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2026-12-27 00:00")))
+			Expect(state).To(Equal(core.CalendarSuspended))
+		})
+
+		It("finds transitions more than a week ahead", func() {
+			holidays, err := core.ParseCalendar(core.CalendarConfig{Name: "holidays",
+				Year: "25.12=off 1.3.2027=suspended"})
+			Expect(err).NotTo(HaveOccurred())
+			next, state, ok := holidays.NextTransition(at("2026-10-16 09:00"))
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2026-12-25 00:00")))
+			Expect(state).To(Equal(core.CalendarOff))
+
+			next, state, ok = holidays.NextTransition(at("2026-12-26 09:00"))
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2027-03-01 00:00")))
+			Expect(state).To(Equal(core.CalendarSuspended))
+		})
+
+		It("handles ranges past midnight and calendars without transitions", func() {
+			night, err := core.ParseCalendar(core.CalendarConfig{Name: "night", Week: "20-6=on 0-24=off"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(night.StateAt(at("2026-10-16 05:59"))).To(Equal(core.CalendarOn))
+			next, state, ok := night.NextTransition(at("2026-10-16 05:59"))
+			Expect(ok).To(BeTrue())
+			Expect(next).To(Equal(at("2026-10-16 06:00")))
+			Expect(state).To(Equal(core.CalendarOff))
+
+			_, _, ok = core.Calendar{}.NextTransition(at("2026-10-16 09:00"))
+			Expect(ok).To(BeFalse())
+
+			past, err := core.ParseCalendar(core.CalendarConfig{Name: "past", Year: "1.1.2020=off"})
+			Expect(err).NotTo(HaveOccurred())
+			_, _, ok = past.NextTransition(at("2026-10-16 09:00"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+})
@@ -0,0 +1,211 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Cluster configuration validation codes. Like the share tree codes they
+// are stable identifiers callers can branch on instead of message text.
+const (
+	// ConfigCodeUnknownPE signals a reference to a parallel environment
+	// which is not defined.
+	ConfigCodeUnknownPE = "CONFIG_UNKNOWN_PE"
+	// ConfigCodeUnknownCkpt signals a reference to a checkpointing
+	// interface which is not defined.
+	ConfigCodeUnknownCkpt = "CONFIG_UNKNOWN_CKPT"
+	// ConfigCodeUnknownCalendar signals a reference to a calendar which
+	// is not defined.
+	ConfigCodeUnknownCalendar = "CONFIG_UNKNOWN_CALENDAR"
+	// ConfigCodeUnknownUserSet signals a reference to a user set (access
+	// list or department) which is not defined.
+	ConfigCodeUnknownUserSet = "CONFIG_UNKNOWN_USERSET"
+	// ConfigCodeUnknownProject signals a reference to a project which is
+	// not defined.
+	ConfigCodeUnknownProject = "CONFIG_UNKNOWN_PROJECT"
+	// ConfigCodeUnknownQueue signals a reference to a cluster queue which
+	// is not defined, like a subordinate queue.
+	ConfigCodeUnknownQueue = "CONFIG_UNKNOWN_QUEUE"
+	// ConfigCodeUnknownHostGroup signals a reference to a host group
+	// which is not defined.
+	ConfigCodeUnknownHostGroup = "CONFIG_UNKNOWN_HOSTGROUP"
+	// ConfigCodeUnknownComplex signals a value for a complex attribute
+	// which is not defined.
+	ConfigCodeUnknownComplex = "CONFIG_UNKNOWN_COMPLEX"
+	// ConfigCodeHostGroupCycle signals host groups which contain each
+	// other, directly or through nested groups.
+	ConfigCodeHostGroupCycle = "CONFIG_HOSTGROUP_CYCLE"
+	// ConfigCodeSelfSubordinate signals a queue which is its own
+	// subordinate.
+	ConfigCodeSelfSubordinate = "CONFIG_SELF_SUBORDINATE"
+	// ConfigCodeNameMismatch signals an object whose name differs from
+	// the key it is stored under in the ClusterConfig.
+	ConfigCodeNameMismatch = "CONFIG_NAME_MISMATCH"
+)
+
+// unknownRefCodes maps the kind of a referenced object to the code for a
+// reference to an undefined object of that kind.
+var unknownRefCodes = map[ObjectKind]string{
+	KindParallelEnvironment: ConfigCodeUnknownPE,
+	KindCkptInterface:       ConfigCodeUnknownCkpt,
+	KindCalendar:            ConfigCodeUnknownCalendar,
+	KindUserSetList:         ConfigCodeUnknownUserSet,
+	KindProject:             ConfigCodeUnknownProject,
+	KindClusterQueue:        ConfigCodeUnknownQueue,
+	KindHostGroup:           ConfigCodeUnknownHostGroup,
+	KindComplexEntry:        ConfigCodeUnknownComplex,
+}
+
+// ConfigIssue is a problem found by ValidateClusterConfig in the object
+// Name of Kind. Field is the JSON name of the field the problem is in and
+// Host the host or host group of the override it is in, if any. Ref is
+// the referenced object which is missing.
+type ConfigIssue struct {
+	Code    string     `json:"code"`
+	Kind    ObjectKind `json:"kind"`
+	Name    string     `json:"name"`
+	Field   string     `json:"field,omitempty"`
+	Host    string     `json:"host,omitempty"`
+	Ref     string     `json:"ref,omitempty"`
+	Message string     `json:"message"`
+}
+
+// Error implements the error interface so a single issue can flow
+// through normal error plumbing when convenient.
+func (i ConfigIssue) Error() string {
+	return fmt.Sprintf("%s at %s %s: %s", i.Code, i.Kind, i.Name, i.Message)
+}
+
+// ValidateClusterConfig checks that every reference between the objects
+// of cc points to a defined object and returns all problems found. An
+// empty slice means the configuration is consistent.
+//
+// Checked are the references of cluster queues (pe_list, ckpt_list,
+// calendar, user_lists, xuser_lists, projects, xprojects,
+// subordinate_list, complex_values and the host groups in hostlist), of
+// host groups to nested host groups, of exec hosts to complexes, user
+// sets and projects, of parallel environments and projects to user sets,
+// of users to their default project and of resource quota sets to user
+// sets, projects, parallel environments, queues, host groups and
+// complexes. References inside [host=...] overrides are checked as well,
+// including the host group an override is made for. Plain host names are
+// not checked, as qmaster accepts every resolvable host.
+func ValidateClusterConfig(cc ClusterConfig) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, ops := range applyKinds {
+		if ops.list || ops.kind == KindGlobalConfig || ops.kind == KindSchedulerConfig {
+			continue
+		}
+		for _, name := range ops.names(cc) {
+			obj, _ := ops.lookup(cc, name)
+			if objName, ok := nameOf(obj); ok && objName != name {
+				issues = append(issues, ConfigIssue{
+					Code: ConfigCodeNameMismatch, Kind: ops.kind, Name: name,
+					Message: fmt.Sprintf("stored as %q but named %q", name, objName),
+				})
+			}
+			for _, ref := range objectFieldRefs(obj) {
+				if issue, ok := checkRef(cc, ops.kind, name, ref); ok {
+					issues = append(issues, issue)
+				}
+			}
+		}
+	}
+	return append(issues, hostGroupCycles(cc)...)
+}
+
+// checkRef returns the issue for ref, made by the object name of kind, if
+// it refers to an undefined object or to the object itself.
+func checkRef(cc ClusterConfig, kind ObjectKind, name string, ref fieldRef) (ConfigIssue, bool) {
+	issue := ConfigIssue{Kind: kind, Name: name, Field: ref.field, Host: ref.host, Ref: ref.name}
+	where := ref.field
+	if ref.host != "" {
+		where = fmt.Sprintf("%s override for %s", ref.field, ref.host)
+	}
+	if kind == KindClusterQueue && ref.kind == KindClusterQueue && ref.name == name {
+		issue.Code = ConfigCodeSelfSubordinate
+		issue.Message = fmt.Sprintf("%s refers to the queue itself", where)
+		return issue, true
+	}
+	code, checked := unknownRefCodes[ref.kind]
+	if !checked {
+		return ConfigIssue{}, false
+	}
+	ops, _ := kindOpsOf(ref.kind)
+	if _, ok := ops.lookup(cc, ref.name); ok {
+		return ConfigIssue{}, false
+	}
+	issue.Code = code
+	issue.Message = fmt.Sprintf("%s refers to unknown %s %q", where,
+		strings.ReplaceAll(string(ref.kind), "_", " "), ref.name)
+	return issue, true
+}
+
+// hostGroupCycles returns an issue for every host group which contains
+// itself through nested host groups.
+func hostGroupCycles(cc ClusterConfig) []ConfigIssue {
+	var issues []ConfigIssue
+	for _, name := range sortedKeys(cc.HostGroups) {
+		path := []string{name}
+		seen := map[string]bool{}
+		var visit func(group string) bool
+		visit = func(group string) bool {
+			for _, member := range cc.HostGroups[group].Hosts {
+				if !strings.HasPrefix(member, "@") || seen[member] {
+					continue
+				}
+				path = append(path, member)
+				if member == name {
+					return true
+				}
+				seen[member] = true
+				if visit(member) {
+					return true
+				}
+				path = path[:len(path)-1]
+			}
+			return false
+		}
+		if visit(name) {
+			issues = append(issues, ConfigIssue{
+				Code: ConfigCodeHostGroupCycle, Kind: KindHostGroup, Name: name,
+				Field: "hostlist", Ref: path[1],
+				Message: "host group contains itself: " + strings.Join(path, " -> "),
+			})
+		}
+	}
+	return issues
+}
+
+// nameOf returns the Name field of a configuration object.
+func nameOf(obj any) (string, bool) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Struct {
+		return "", false
+	}
+	f := v.FieldByName("Name")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return "", false
+	}
+	return f.String(), true
+}
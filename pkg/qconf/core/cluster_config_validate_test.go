@@ -0,0 +1,177 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("ValidateClusterConfig", func() {
+
+	var cc core.ClusterConfig
+
+	BeforeEach(func() {
+		cc = core.ClusterConfig{
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {
+					Name:            "all.q",
+					HostList:        []string{"@allhosts", "node9"},
+					PeList:          []string{"make", "[node1=make mpi]"},
+					CkptList:        []string{"NONE"},
+					Calendar:        []string{"NONE", "[@allhosts=night]"},
+					UserLists:       []string{"staff"},
+					Projects:        []string{"p1"},
+					SubordinateList: []string{"low.q=1"},
+					ComplexValues:   []string{"gpu=2", "[node1=gpu=4]"},
+					Slots:           []string{"8", "[@rack1=16]"},
+				},
+				"low.q": {Name: "low.q"},
+			},
+			ParallelEnvironments: map[string]core.ParallelEnvironmentConfig{
+				"make": {Name: "make", UserLists: []string{"staff"}},
+				"mpi":  {Name: "mpi"},
+			},
+			Calendars: map[string]core.CalendarConfig{
+				"night": {Name: "night"},
+			},
+			UserSetLists: map[string]core.UserSetListConfig{
+				"staff": {Name: "staff"},
+			},
+			Projects: map[string]core.ProjectConfig{
+				"p1": {Name: "p1", ACL: []string{"staff"}, XACL: []string{"NONE"}},
+			},
+			Users: map[string]core.UserConfig{
+				"alice": {Name: "alice", DefaultProject: "p1"},
+				"bob":   {Name: "bob", DefaultProject: "NONE"},
+			},
+			HostGroups: map[string]core.HostGroupConfig{
+				"@allhosts": {Name: "@allhosts", Hosts: []string{"@rack1", "node9"}},
+				"@rack1":    {Name: "@rack1", Hosts: []string{"node1", "node2"}},
+			},
+			ExecHosts: map[string]core.HostExecConfig{
+				"node1": {Name: "node1", ComplexValues: map[string]string{"gpu": "4"}},
+			},
+			ComplexEntries: map[string]core.ComplexEntryConfig{
+				"gpu":   {Name: "gpu"},
+				"slots": {Name: "slots"},
+			},
+			ResourceQuotaSets: map[string]core.ResourceQuotaSetConfig{
+				"max_slots": {Name: "max_slots", Limits: []string{
+					"users {@staff,bob} projects p1 queues all.q@@rack1 hosts {@allhosts} to slots=10",
+				}},
+			},
+		}
+	})
+
+	It("accepts a consistent configuration", func() {
+		Expect(core.ValidateClusterConfig(cc)).To(BeEmpty())
+		Expect(core.ValidateClusterConfig(core.ClusterConfig{})).To(BeEmpty())
+	})
+
+	It("flags a parallel environment only referenced in a host override", func() {
+		delete(cc.ParallelEnvironments, "mpi")
+		Expect(core.ValidateClusterConfig(cc)).To(ConsistOf(core.ConfigIssue{
+			Code:    core.ConfigCodeUnknownPE,
+			Kind:    core.KindClusterQueue,
+			Name:    "all.q",
+			Field:   "pe_list",
+			Host:    "node1",
+			Ref:     "mpi",
+			Message: `pe_list override for node1 refers to unknown parallel environment "mpi"`,
+		}))
+	})
+
+	It("flags references of every kind with a machine-readable code", func() {
+		cc.Calendars = nil
+		cc.UserSetLists = nil
+		cc.Projects = nil
+		cc.ComplexEntries = nil
+		delete(cc.ClusterQueues, "low.q")
+		delete(cc.HostGroups, "@rack1")
+
+		byCode := map[string][]string{}
+		for _, issue := range core.ValidateClusterConfig(cc) {
+			byCode[issue.Code] = append(byCode[issue.Code],
+				string(issue.Kind)+" "+issue.Name+" "+issue.Field+" "+issue.Host)
+		}
+		Expect(byCode[core.ConfigCodeUnknownCalendar]).To(ConsistOf(
+			"cluster_queue all.q calendar @allhosts"))
+		Expect(byCode[core.ConfigCodeUnknownUserSet]).To(ConsistOf(
+			"cluster_queue all.q user_lists ",
+			"parallel_environment make user_lists ",
+			"resource_quota_set max_slots limits "))
+		Expect(byCode[core.ConfigCodeUnknownProject]).To(ConsistOf(
+			"cluster_queue all.q projects ",
+			"user alice default_project ",
+			"resource_quota_set max_slots limits "))
+		Expect(byCode[core.ConfigCodeUnknownQueue]).To(ConsistOf(
+			"cluster_queue all.q subordinate_list "))
+		Expect(byCode[core.ConfigCodeUnknownComplex]).To(ConsistOf(
+			"cluster_queue all.q complex_values ",
+			"cluster_queue all.q complex_values node1",
+			"exec_host node1 complex_values ",
+			"resource_quota_set max_slots limits "))
+		Expect(byCode[core.ConfigCodeUnknownHostGroup]).To(ConsistOf(
+			"cluster_queue all.q slots @rack1",
+			"host_group @allhosts hostlist "))
+	})
+
+	It("resolves the queues of a slotwise subordination", func() {
+		q := cc.ClusterQueues["all.q"]
+		q.SubordinateList = []string{"slots=2(low.q:1:sr", "other.q:2:lr)"}
+		cc.ClusterQueues["all.q"] = q
+		Expect(core.ValidateClusterConfig(cc)).To(ConsistOf(core.ConfigIssue{
+			Code:    core.ConfigCodeUnknownQueue,
+			Kind:    core.KindClusterQueue,
+			Name:    "all.q",
+			Field:   "subordinate_list",
+			Ref:     "other.q",
+			Message: `subordinate_list refers to unknown cluster queue "other.q"`,
+		}))
+
+		cc.ClusterQueues["other.q"] = core.ClusterQueueConfig{Name: "other.q"}
+		Expect(core.ValidateClusterConfig(cc)).To(BeEmpty())
+	})
+
+	It("flags host groups which contain each other", func() {
+		cc.HostGroups["@rack1"] = core.HostGroupConfig{Name: "@rack1", Hosts: []string{"@allhosts"}}
+		issues := core.ValidateClusterConfig(cc)
+		Expect(issues).To(HaveLen(2))
+		Expect(issues[0].Code).To(Equal(core.ConfigCodeHostGroupCycle))
+		Expect(issues[0].Message).To(ContainSubstring("@allhosts -> @rack1 -> @allhosts"))
+	})
+
+	It("flags self subordination and misnamed objects", func() {
+		q := cc.ClusterQueues["low.q"]
+		q.SubordinateList = []string{"low.q"}
+		cc.ClusterQueues["low.q"] = q
+		cc.Calendars["day"] = core.CalendarConfig{Name: "night"}
+
+		var found []string
+		for _, issue := range core.ValidateClusterConfig(cc) {
+			found = append(found, issue.Code)
+			Expect(issue.Error()).To(HavePrefix(issue.Code + " at "))
+		}
+		Expect(found).To(ConsistOf(core.ConfigCodeSelfSubordinate, core.ConfigCodeNameMismatch))
+	})
+})
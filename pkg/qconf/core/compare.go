@@ -36,6 +36,13 @@ type ClusterConfigComparison struct {
 	DiffModified *ClusterConfig `json:"diff_modified,omitempty"`
 	// Contains removed objects
 	DiffRemoved *ClusterConfig `json:"diff_removed,omitempty"`
+	// FieldDiffs lists every added and deleted object and every changed
+	// field of a modified object, ordered by kind like AddAllEntries and
+	// by name.
+	FieldDiffs []FieldDiff `json:"field_diffs,omitempty"`
+	// Patch is the RFC 6902 JSON Patch turning the JSON of the old
+	// ClusterConfig into the new one.
+	Patch []JSONPatchOperation `json:"patch,omitempty"`
 }
 
 func NewClusterConfigComparison() *ClusterConfigComparison {
@@ -46,9 +53,12 @@ func NewClusterConfigComparison() *ClusterConfigComparison {
 	}
 }
 
+// String returns the field diffs as unified text; see FormatDiff.
 func (c *ClusterConfigComparison) String() string {
-	return fmt.Sprintf("IsSame: %t, GlobalConfigChanged: %t, Added: %v, Modified: %v, Removed: %v",
-		c.IsSame, c.GlobalConfigChanged, c.DiffAdded, c.DiffModified, c.DiffRemoved)
+	if len(c.FieldDiffs) == 0 {
+		return "no differences"
+	}
+	return c.FormatDiff(false)
 }
 
 // CompareTo compares the current ClusterConfig with the new ClusterConfig and
@@ -226,6 +236,11 @@ func (c *ClusterConfig) CompareTo(new ClusterConfig) (*ClusterConfigComparison,
 	comparison.DiffRemoved.SubmitHosts = append(comparison.DiffRemoved.SubmitHosts,
 		resultSubmitHosts.Removed...)
 
+	comparison.FieldDiffs, comparison.Patch, err = diffFields(*c, new)
+	if err != nil {
+		return nil, fmt.Errorf("error finding field differences: %w", err)
+	}
+
 	return comparison, nil
 }
 
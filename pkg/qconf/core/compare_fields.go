@@ -0,0 +1,456 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// FieldDiff is a single change between two ClusterConfigs: an object
+// which was added or deleted, or a field of a modified object.
+type FieldDiff struct {
+	Kind   ObjectKind   `json:"kind"`
+	Name   string       `json:"name"`
+	Action ChangeAction `json:"action"`
+	// Field is the JSON path of the changed field within the object,
+	// with map keys like the ones of ExtraFields separated by dots:
+	// "slots" or "extra_fields.custom_key". It is empty for added and
+	// deleted objects.
+	Field string `json:"field,omitempty"`
+	// Host is set for a per-host override of a cluster queue attribute:
+	// the host or host group of the override.
+	Host string `json:"host,omitempty"`
+	// Old and New are the JSON values before and after the change. Old is
+	// nil for added fields and objects, New for removed ones.
+	Old any `json:"old,omitempty"`
+	New any `json:"new,omitempty"`
+}
+
+// String returns the field like "cluster_queue all.q slots[@gpu]".
+func (d FieldDiff) String() string {
+	s := string(d.Kind) + " " + d.Name
+	if d.Field != "" {
+		s += " " + d.fieldLabel()
+	}
+	return s
+}
+
+func (d FieldDiff) fieldLabel() string {
+	if d.Host != "" {
+		return d.Field + "[" + d.Host + "]"
+	}
+	return d.Field
+}
+
+// JSONPatchOperation is an operation of an RFC 6902 JSON Patch.
+type JSONPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	Value any    `json:"value,omitempty"`
+}
+
+// MarshalJSON writes the value of every operation except remove, also
+// when it is null, false or zero.
+func (o JSONPatchOperation) MarshalJSON() ([]byte, error) {
+	if o.Op == "remove" {
+		return json.Marshal(struct {
+			Op   string `json:"op"`
+			Path string `json:"path"`
+		}{o.Op, o.Path})
+	}
+	return json.Marshal(struct {
+		Op    string `json:"op"`
+		Path  string `json:"path"`
+		Value any    `json:"value"`
+	}{o.Op, o.Path, o.Value})
+}
+
+// kindJSONFields maps every object kind to its field of ClusterConfig.
+var kindJSONFields = map[ObjectKind]string{
+	KindUserSetList:         "user_set_lists",
+	KindProject:             "projects",
+	KindUser:                "users",
+	KindManager:             "managers",
+	KindOperator:            "operators",
+	KindHostConfiguration:   "host_configurations",
+	KindHostGroup:           "host_groups",
+	KindExecHost:            "exec_hosts",
+	KindComplexEntry:        "complex_entries",
+	KindCalendar:            "calendars",
+	KindCkptInterface:       "ckpt_interfaces",
+	KindAdminHost:           "admin_hosts",
+	KindResourceQuotaSet:    "resource_quota_sets",
+	KindParallelEnvironment: "parallel_environments",
+	KindClusterQueue:        "cluster_queues",
+	KindSubmitHost:          "submit_hosts",
+	KindGlobalConfig:        "global_config",
+	KindSchedulerConfig:     "scheduler_config",
+}
+
+// fieldDiffer collects the field diffs and the JSON Patch turning one
+// ClusterConfig into another.
+type fieldDiffer struct {
+	diffs []FieldDiff
+	patch []JSONPatchOperation
+}
+
+// diffFields compares old and new field by field. Like CompareTo it
+// ignores the ClusterEnvironment.
+func diffFields(old, new ClusterConfig) ([]FieldDiff, []JSONPatchOperation, error) {
+	oldTree, err := jsonTree(old)
+	if err != nil {
+		return nil, nil, err
+	}
+	newTree, err := jsonTree(new)
+	if err != nil {
+		return nil, nil, err
+	}
+	var d fieldDiffer
+	for _, ops := range applyKinds {
+		field := kindJSONFields[ops.kind]
+		pointer := "/" + field
+		oldValue, newValue := oldTree[field], newTree[field]
+		switch {
+		case ops.list:
+			d.diffList(ops.kind, pointer, oldValue, newValue)
+		case ops.kind == KindGlobalConfig || ops.kind == KindSchedulerConfig:
+			d.diffObject(ops.kind, ops.names(old), ops.names(new), pointer, oldValue, newValue, true)
+		default:
+			oldObjects, _ := oldValue.(map[string]any)
+			newObjects, _ := newValue.(map[string]any)
+			// JSON Patch cannot add members to null, so a missing map
+			// is replaced as a whole.
+			patchObjects := oldValue != nil && newValue != nil
+			if !patchObjects && !reflect.DeepEqual(oldValue, newValue) {
+				d.patch = append(d.patch, JSONPatchOperation{Op: "replace", Path: pointer, Value: newValue})
+			}
+			for _, name := range unionKeys(oldObjects, newObjects) {
+				o, inOld := oldObjects[name]
+				n, inNew := newObjects[name]
+				d.diffObject(ops.kind, boolNames(name, inOld), boolNames(name, inNew),
+					pointer+"/"+escapeJSONPointer(name), o, n, patchObjects)
+			}
+		}
+	}
+	return d.diffs, d.patch, nil
+}
+
+// boolNames returns name in a slice if ok, for diffObject.
+func boolNames(name string, ok bool) []string {
+	if ok {
+		return []string{name}
+	}
+	return nil
+}
+
+// diffObject compares an object which is called oldNames[0] in the old
+// and newNames[0] in the new config; a missing name means the object
+// does not exist there.
+func (d *fieldDiffer) diffObject(kind ObjectKind, oldNames, newNames []string, pointer string,
+	old, new any, patch bool) {
+	switch {
+	case len(oldNames) == 0 && len(newNames) == 0:
+	case len(oldNames) == 0:
+		d.diffs = append(d.diffs, FieldDiff{Kind: kind, Name: newNames[0], Action: ActionAdd, New: new})
+		if patch {
+			d.patch = append(d.patch, JSONPatchOperation{Op: "add", Path: pointer, Value: new})
+		}
+	case len(newNames) == 0:
+		d.diffs = append(d.diffs, FieldDiff{Kind: kind, Name: oldNames[0], Action: ActionDelete, Old: old})
+		if patch {
+			d.patch = append(d.patch, JSONPatchOperation{Op: "remove", Path: pointer})
+		}
+	default:
+		d.diffValue(kind, newNames[0], pointer, nil, old, new, true, true, patch)
+	}
+}
+
+// diffValue compares the values at path within a modified object and
+// descends into maps, so a change of a single ExtraFields key is
+// reported as such.
+func (d *fieldDiffer) diffValue(kind ObjectKind, name, pointer string, path []string,
+	old, new any, inOld, inNew, patch bool) {
+	oldMap, oldIsMap := old.(map[string]any)
+	newMap, newIsMap := new.(map[string]any)
+	if oldIsMap && newIsMap {
+		for _, key := range unionKeys(oldMap, newMap) {
+			o, inO := oldMap[key]
+			n, inN := newMap[key]
+			d.diffValue(kind, name, pointer+"/"+escapeJSONPointer(key), append(path[:len(path):len(path)], key),
+				o, n, inO, inN, patch)
+		}
+		return
+	}
+	if inOld == inNew && reflect.DeepEqual(old, new) {
+		return
+	}
+	if patch {
+		switch {
+		case !inOld:
+			d.patch = append(d.patch, JSONPatchOperation{Op: "add", Path: pointer, Value: new})
+		case !inNew:
+			d.patch = append(d.patch, JSONPatchOperation{Op: "remove", Path: pointer})
+		default:
+			d.patch = append(d.patch, JSONPatchOperation{Op: "replace", Path: pointer, Value: new})
+		}
+	}
+	field := strings.Join(path, ".")
+	if kind == KindClusterQueue && len(path) == 1 && field != "hostlist" {
+		if oldOverrides, ok := queueOverrides(field, old); ok {
+			if newOverrides, ok := queueOverrides(field, new); ok {
+				for _, host := range unionKeys(oldOverrides, newOverrides) {
+					o, inO := oldOverrides[host]
+					n, inN := newOverrides[host]
+					if inO && inN && o == n {
+						continue
+					}
+					diff := FieldDiff{Kind: kind, Name: name, Action: ActionModify, Field: field, Host: host}
+					if inO {
+						diff.Old = o
+					}
+					if inN {
+						diff.New = n
+					}
+					d.diffs = append(d.diffs, diff)
+				}
+				return
+			}
+		}
+	}
+	d.diffs = append(d.diffs, FieldDiff{Kind: kind, Name: name, Action: ActionModify, Field: field,
+		Old: old, New: new})
+}
+
+// diffList compares a plain name list like the managers.
+func (d *fieldDiffer) diffList(kind ObjectKind, pointer string, old, new any) {
+	oldNames, newNames := jsonStrings(old), jsonStrings(new)
+	var removed []int
+	for i, name := range oldNames {
+		if !slices.Contains(newNames, name) {
+			d.diffs = append(d.diffs, FieldDiff{Kind: kind, Name: name, Action: ActionDelete})
+			removed = append(removed, i)
+		}
+	}
+	var added []string
+	for _, name := range newNames {
+		if !slices.Contains(oldNames, name) {
+			d.diffs = append(d.diffs, FieldDiff{Kind: kind, Name: name, Action: ActionAdd})
+			added = append(added, name)
+		}
+	}
+	if old == nil {
+		if len(added) > 0 {
+			d.patch = append(d.patch, JSONPatchOperation{Op: "add", Path: pointer, Value: new})
+		}
+		return
+	}
+	// remove from the back so the indices stay valid
+	for i := len(removed) - 1; i >= 0; i-- {
+		d.patch = append(d.patch, JSONPatchOperation{Op: "remove", Path: fmt.Sprintf("%s/%d", pointer, removed[i])})
+	}
+	for _, name := range added {
+		d.patch = append(d.patch, JSONPatchOperation{Op: "add", Path: pointer + "/-", Value: name})
+	}
+}
+
+// queueOverrides splits the JSON value of a cluster queue list attribute
+// into its values by host or host group, with the cluster-wide value
+// under "". It reports false for values which are not string lists.
+func queueOverrides(field string, v any) (map[string]string, bool) {
+	if v == nil {
+		return map[string]string{}, true
+	}
+	entries, ok := v.([]any)
+	if !ok {
+		return nil, false
+	}
+	overrides := make(map[string]string)
+	var defaults []string
+	for _, e := range entries {
+		s, ok := e.(string)
+		if !ok {
+			return nil, false
+		}
+		if host, value, ok := parseOverride(s); ok {
+			overrides[host] = value
+		} else {
+			defaults = append(defaults, s)
+		}
+	}
+	if len(defaults) > 0 {
+		sep := ","
+		if queueSpaceListFields[field] {
+			sep = " "
+		}
+		overrides[""] = strings.Join(defaults, sep)
+	}
+	return overrides, true
+}
+
+// jsonTree returns the JSON representation of cc as generic values.
+func jsonTree(cc ClusterConfig) (map[string]any, error) {
+	data, err := json.Marshal(cc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cluster configuration: %w", err)
+	}
+	var tree map[string]any
+	if err := json.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("failed to decode cluster configuration: %w", err)
+	}
+	return tree, nil
+}
+
+func jsonStrings(v any) []string {
+	values, _ := v.([]any)
+	strs := make([]string, 0, len(values))
+	for _, value := range values {
+		if s, ok := value.(string); ok {
+			strs = append(strs, s)
+		}
+	}
+	return strs
+}
+
+func unionKeys[T any](a, b map[string]T) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapeJSONPointer escapes a reference token of a JSON Pointer
+// (RFC 6901).
+func escapeJSONPointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// Colors of the terminal output of FormatDiff.
+const (
+	diffColorHeader  = "\x1b[36m"
+	diffColorRemoved = "\x1b[31m"
+	diffColorAdded   = "\x1b[32m"
+	diffColorReset   = "\x1b[0m"
+)
+
+// FormatDiff renders the field diffs of c as unified text: a "@@ kind
+// name @@" header for every object followed by "-field: old" and
+// "+field: new" lines. Added and deleted objects list all their fields.
+// With color the lines carry ANSI colors for a terminal.
+func (c *ClusterConfigComparison) FormatDiff(color bool) string {
+	var sb strings.Builder
+	paint := func(code, line string) {
+		if color {
+			line = code + line + diffColorReset
+		}
+		sb.WriteString(line + "\n")
+	}
+	var last FieldDiff
+	for i, d := range c.FieldDiffs {
+		if i == 0 || d.Kind != last.Kind || d.Name != last.Name || d.Action != last.Action {
+			header := "@@ " + string(d.Kind) + " " + d.Name
+			switch d.Action {
+			case ActionAdd:
+				header += " (added)"
+			case ActionDelete:
+				header += " (deleted)"
+			}
+			paint(diffColorHeader, header+" @@")
+		}
+		last = d
+		switch d.Action {
+		case ActionAdd:
+			flattenJSON("", d.New, func(field string, v any) {
+				paint(diffColorAdded, "+"+field+": "+formatDiffValue(v))
+			})
+		case ActionDelete:
+			flattenJSON("", d.Old, func(field string, v any) {
+				paint(diffColorRemoved, "-"+field+": "+formatDiffValue(v))
+			})
+		default:
+			if d.Old != nil {
+				paint(diffColorRemoved, "-"+d.fieldLabel()+": "+formatDiffValue(d.Old))
+			}
+			if d.New != nil {
+				paint(diffColorAdded, "+"+d.fieldLabel()+": "+formatDiffValue(d.New))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// JSONPatch returns the field diffs of c as an RFC 6902 JSON Patch
+// document which turns the JSON of the old ClusterConfig into the new
+// one.
+func (c *ClusterConfigComparison) JSONPatch() ([]byte, error) {
+	patch := c.Patch
+	if patch == nil {
+		patch = []JSONPatchOperation{}
+	}
+	return json.MarshalIndent(patch, "", "  ")
+}
+
+// flattenJSON calls fn for every value in v which is not a map, with its
+// dot separated path below prefix, in key order.
+func flattenJSON(prefix string, v any, fn func(field string, v any)) {
+	m, ok := v.(map[string]any)
+	if !ok {
+		if prefix != "" {
+			fn(prefix, v)
+		}
+		return
+	}
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		field := k
+		if prefix != "" {
+			field = prefix + "." + k
+		}
+		flattenJSON(field, m[k], fn)
+	}
+}
+
+// formatDiffValue writes strings as they are and everything else as
+// JSON.
+func formatDiffValue(v any) string {
+	if s, ok := v.(string); ok {
+		return s
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
@@ -0,0 +1,138 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("Field diffs", func() {
+
+	old := core.ClusterConfig{
+		Managers: []string{"root", "alice"},
+		ClusterQueues: map[string]core.ClusterQueueConfig{
+			"all.q": {
+				Name:     "all.q",
+				HostList: []string{"@allhosts"},
+				Slots:    []string{"4", "[@gpu=8]", "[node1=2]"},
+				PeList:   []string{"make", "smp"},
+				ExtraFields: map[string]string{
+					"custom": "a",
+					"gone":   "x",
+				},
+			},
+			"old.q": {Name: "old.q"},
+		},
+		SchedulerConfig: &core.SchedulerConfig{MaxUJobs: 0, LoadFormula: "np_load_avg"},
+	}
+	new := core.ClusterConfig{
+		Managers: []string{"root", "bob"},
+		ClusterQueues: map[string]core.ClusterQueueConfig{
+			"all.q": {
+				Name:     "all.q",
+				HostList: []string{"@allhosts"},
+				Slots:    []string{"4", "[@gpu=16]", "[node2=1]"},
+				PeList:   []string{"make", "smp", "mpi"},
+				ExtraFields: map[string]string{
+					"custom": "b",
+				},
+			},
+		},
+		Calendars: map[string]core.CalendarConfig{
+			"night": {Name: "night", Week: "mon-fri=20-6=on"},
+		},
+		SchedulerConfig: &core.SchedulerConfig{MaxUJobs: 10, LoadFormula: "np_load_avg"},
+	}
+
+	It("reports fields, extra field keys and per-host overrides", func() {
+		comparison, err := old.CompareTo(new)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(comparison.FieldDiffs).To(Equal([]core.FieldDiff{
+			{Kind: core.KindManager, Name: "alice", Action: core.ActionDelete},
+			{Kind: core.KindManager, Name: "bob", Action: core.ActionAdd},
+			{Kind: core.KindCalendar, Name: "night", Action: core.ActionAdd, New: map[string]any{
+				"calendar_name": "night", "year": "", "week": "mon-fri=20-6=on"}},
+			{Kind: core.KindClusterQueue, Name: "all.q", Action: core.ActionModify,
+				Field: "extra_fields.custom", Old: "a", New: "b"},
+			{Kind: core.KindClusterQueue, Name: "all.q", Action: core.ActionModify,
+				Field: "extra_fields.gone", Old: "x"},
+			{Kind: core.KindClusterQueue, Name: "all.q", Action: core.ActionModify,
+				Field: "pe_list", Old: "make smp", New: "make smp mpi"},
+			{Kind: core.KindClusterQueue, Name: "all.q", Action: core.ActionModify,
+				Field: "slots", Host: "@gpu", Old: "8", New: "16"},
+			{Kind: core.KindClusterQueue, Name: "all.q", Action: core.ActionModify,
+				Field: "slots", Host: "node1", Old: "2"},
+			{Kind: core.KindClusterQueue, Name: "all.q", Action: core.ActionModify,
+				Field: "slots", Host: "node2", New: "1"},
+			{Kind: core.KindClusterQueue, Name: "old.q", Action: core.ActionDelete,
+				Old: comparison.FieldDiffs[9].Old},
+			{Kind: core.KindSchedulerConfig, Name: "scheduler", Action: core.ActionModify,
+				Field: "maxujobs", Old: 0.0, New: 10.0},
+		}))
+		Expect(comparison.FieldDiffs[6].String()).To(Equal("cluster_queue all.q slots[@gpu]"))
+	})
+
+	It("renders unified text with and without color", func() {
+		comparison, err := old.CompareTo(new)
+		Expect(err).NotTo(HaveOccurred())
+		text := comparison.String()
+		Expect(text).To(HavePrefix("@@ manager alice (deleted) @@\n@@ manager bob (added) @@\n" +
+			"@@ calendar night (added) @@\n+calendar_name: night\n+week: mon-fri=20-6=on\n+year: \n" +
+			"@@ cluster_queue all.q @@\n-extra_fields.custom: a\n+extra_fields.custom: b\n" +
+			"-extra_fields.gone: x\n-pe_list: make smp\n+pe_list: make smp mpi\n" +
+			"-slots[@gpu]: 8\n+slots[@gpu]: 16\n-slots[node1]: 2\n+slots[node2]: 1\n" +
+			"@@ cluster_queue old.q (deleted) @@\n"))
+		Expect(text).To(HaveSuffix("@@ scheduler_config scheduler @@\n-maxujobs: 0\n+maxujobs: 10\n"))
+
+		colored := comparison.FormatDiff(true)
+		Expect(colored).To(ContainSubstring("\x1b[31m-slots[@gpu]: 8\x1b[0m\n\x1b[32m+slots[@gpu]: 16\x1b[0m\n"))
+
+		same, err := old.CompareTo(old)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(same.FieldDiffs).To(BeEmpty())
+		Expect(same.String()).To(Equal("no differences"))
+	})
+
+	It("produces a JSON Patch", func() {
+		comparison, err := old.CompareTo(new)
+		Expect(err).NotTo(HaveOccurred())
+		data, err := comparison.JSONPatch()
+		Expect(err).NotTo(HaveOccurred())
+		var patch []map[string]any
+		Expect(json.Unmarshal(data, &patch)).To(Succeed())
+		Expect(patch).To(Equal([]map[string]any{
+			{"op": "remove", "path": "/managers/1"},
+			{"op": "add", "path": "/managers/-", "value": "bob"},
+			{"op": "replace", "path": "/calendars", "value": map[string]any{
+				"night": map[string]any{"calendar_name": "night", "year": "", "week": "mon-fri=20-6=on"}}},
+			{"op": "replace", "path": "/cluster_queues/all.q/extra_fields/custom", "value": "b"},
+			{"op": "remove", "path": "/cluster_queues/all.q/extra_fields/gone"},
+			{"op": "replace", "path": "/cluster_queues/all.q/pe_list", "value": []any{"make", "smp", "mpi"}},
+			{"op": "replace", "path": "/cluster_queues/all.q/slots", "value": []any{"4", "[@gpu=16]", "[node2=1]"}},
+			{"op": "remove", "path": "/cluster_queues/old.q"},
+			{"op": "replace", "path": "/scheduler_config/maxujobs", "value": 10.0},
+		}))
+	})
+})
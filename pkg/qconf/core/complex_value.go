@@ -0,0 +1,269 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper"
+)
+
+// ComplexValue is the value of a complex, parsed according to the type of
+// its complex entry. Numeric types keep their value in Number: INT and
+// DOUBLE as they are, MEMORY in bytes, TIME in seconds, BOOL as 0 or 1
+// and RSMAP as the number of ids. String types, STRING, CSTRING,
+// RESTRING and HOST, keep it in String.
+type ComplexValue struct {
+	Type   string  `json:"type"`
+	Number float64 `json:"number,omitempty"`
+	String string  `json:"string,omitempty"`
+}
+
+// ParseComplexValue parses s, a value of the complex entry, as found in
+// complex_values, in qhost -F output or in a resource request. Numeric
+// values may be INFINITY; an RSMAP value may list its ids like
+// "2(gpu0 gpu1)".
+func ParseComplexValue(entry ComplexEntryConfig, s string) (ComplexValue, error) {
+	s = strings.TrimSpace(s)
+	v := ComplexValue{Type: strings.ToUpper(entry.Type)}
+	if v.IsString() {
+		v.String = s
+		return v, nil
+	}
+	n, err := parseComplexNumber(v.Type, s)
+	if err != nil {
+		return ComplexValue{}, fmt.Errorf("invalid %s value %q for %s: %w", v.Type, s, entry.Name, err)
+	}
+	v.Number = n
+	return v, nil
+}
+
+func parseComplexNumber(typ, s string) (float64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty value")
+	}
+	if strings.EqualFold(s, "INFINITY") && typ != ResourceTypeBool {
+		return math.Inf(1), nil
+	}
+	switch typ {
+	case ResourceTypeInt:
+		n, err := strconv.ParseFloat(s, 64)
+		if err != nil || n != math.Trunc(n) {
+			return 0, fmt.Errorf("not an integer")
+		}
+		return n, nil
+	case ResourceTypeDouble:
+		return strconv.ParseFloat(s, 64)
+	case ResourceTypeMemory:
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n, nil
+		}
+		n, err := helper.ParseMemoryFromString(s)
+		return float64(n), err
+	case ResourceTypeTime:
+		if n, err := strconv.ParseFloat(s, 64); err == nil {
+			return n, nil
+		}
+		n, err := helper.ParseTimeResourceValueToSeconds(s)
+		return float64(n), err
+	case ResourceTypeBool:
+		switch strings.ToLower(s) {
+		case "true", "1":
+			return 1, nil
+		case "false", "0":
+			return 0, nil
+		}
+		return 0, fmt.Errorf("not a boolean")
+	case ResourceTypeRSMAP:
+		count, _, _ := strings.Cut(s, "(")
+		n, err := strconv.ParseUint(strings.TrimSpace(count), 10, 32)
+		return float64(n), err
+	}
+	return 0, fmt.Errorf("unknown type %q", typ)
+}
+
+// IsString reports whether v is of a string type.
+func (v ComplexValue) IsString() bool {
+	switch v.Type {
+	case ResourceTypeString, ResourceTypeCString, ResourceTypeRestring, ResourceTypeHost:
+		return true
+	}
+	return false
+}
+
+// Format returns v in the text form of its type, like "4G" or
+// "01:00:00". Memory is written with the largest binary unit dividing it.
+func (v ComplexValue) Format() string {
+	if v.IsString() {
+		return v.String
+	}
+	if math.IsInf(v.Number, 1) {
+		return "INFINITY"
+	}
+	switch v.Type {
+	case ResourceTypeMemory:
+		n := int64(v.Number)
+		for _, u := range []struct {
+			suffix string
+			size   int64
+		}{{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}} {
+			if n != 0 && n%u.size == 0 {
+				return strconv.FormatInt(n/u.size, 10) + u.suffix
+			}
+		}
+		return strconv.FormatInt(n, 10)
+	case ResourceTypeTime:
+		return helper.FormatSecondsToTimeResourceValue(int64(v.Number))
+	case ResourceTypeBool:
+		if v.Number != 0 {
+			return "TRUE"
+		}
+		return "FALSE"
+	}
+	return strconv.FormatFloat(v.Number, 'f', -1, 64)
+}
+
+// Add returns v plus o, for example the capacity of a consumable after
+// a job released o. Both must be of the same numeric type.
+func (v ComplexValue) Add(o ComplexValue) (ComplexValue, error) {
+	if err := v.checkArithmetic(o); err != nil {
+		return ComplexValue{}, err
+	}
+	v.Number += o.Number
+	return v, nil
+}
+
+// Sub returns v minus o, for example what is left of a consumable after a
+// job took o. Both must be of the same numeric type. What is left of
+// INFINITY stays INFINITY.
+func (v ComplexValue) Sub(o ComplexValue) (ComplexValue, error) {
+	if err := v.checkArithmetic(o); err != nil {
+		return ComplexValue{}, err
+	}
+	if !math.IsInf(v.Number, 1) {
+		v.Number -= o.Number
+	}
+	return v, nil
+}
+
+func (v ComplexValue) checkArithmetic(o ComplexValue) error {
+	if v.Type != o.Type {
+		return fmt.Errorf("cannot combine %s and %s values", v.Type, o.Type)
+	}
+	if v.IsString() || v.Type == ResourceTypeBool {
+		return fmt.Errorf("no arithmetic on %s values", v.Type)
+	}
+	return nil
+}
+
+// Satisfies reports whether the requested value v is granted by the
+// available value avail under relop, evaluating "v relop avail" like the
+// scheduler does. For h_vmem with relop "<=" a request of 2G is satisfied
+// by 4G available.
+//
+// String values compare case-insensitively, except CSTRING. With "=="
+// and "!=" the request is a pattern: alternatives separated by "|", each
+// optionally negated with "!" and with shell wildcards, like
+// "lx-*|!sol-sparc64". EXCL, the relop of exclusive complexes, compares
+// like "<=".
+func (v ComplexValue) Satisfies(relop string, avail ComplexValue) (bool, error) {
+	if v.Type != avail.Type {
+		return false, fmt.Errorf("cannot compare %s and %s values", v.Type, avail.Type)
+	}
+	if !v.IsString() {
+		return compareRelop(relop, compareFloat(v.Number, avail.Number))
+	}
+	request, available := v.String, avail.String
+	if v.Type != ResourceTypeCString {
+		request, available = strings.ToLower(request), strings.ToLower(available)
+	}
+	switch relop {
+	case "==":
+		return matchComplexPattern(request, available), nil
+	case "!=":
+		return !matchComplexPattern(request, available), nil
+	}
+	return compareRelop(relop, strings.Compare(request, available))
+}
+
+func compareFloat(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// compareRelop returns whether relop holds for two values which compare
+// as cmp.
+func compareRelop(relop string, cmp int) (bool, error) {
+	switch relop {
+	case "==":
+		return cmp == 0, nil
+	case "!=":
+		return cmp != 0, nil
+	case "<":
+		return cmp < 0, nil
+	case "<=", "EXCL":
+		return cmp <= 0, nil
+	case ">":
+		return cmp > 0, nil
+	case ">=":
+		return cmp >= 0, nil
+	}
+	return false, fmt.Errorf("unknown relop %q", relop)
+}
+
+// matchComplexPattern reports whether s matches one of the "|"
+// separated alternatives of pattern.
+func matchComplexPattern(pattern, s string) bool {
+	for _, alt := range strings.Split(pattern, "|") {
+		negated := strings.HasPrefix(alt, "!")
+		alt = strings.TrimPrefix(alt, "!")
+		matched, err := path.Match(alt, s)
+		if err != nil {
+			matched = alt == s
+		}
+		if matched != negated {
+			return true
+		}
+	}
+	return false
+}
+
+// CompareComplexValues parses request and available as values of entry
+// and reports whether the request is satisfied under the relop of entry.
+func CompareComplexValues(entry ComplexEntryConfig, request, available string) (bool, error) {
+	r, err := ParseComplexValue(entry, request)
+	if err != nil {
+		return false, err
+	}
+	a, err := ParseComplexValue(entry, available)
+	if err != nil {
+		return false, err
+	}
+	return r.Satisfies(entry.Relop, a)
+}
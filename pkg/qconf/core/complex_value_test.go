@@ -0,0 +1,116 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("Complex values", func() {
+
+	entry := func(typ, relop string) core.ComplexEntryConfig {
+		return core.ComplexEntryConfig{Name: "c", Type: typ, Relop: relop}
+	}
+
+	DescribeTable("parses and formats values by type",
+		func(typ, s string, number float64, formatted string) {
+			v, err := core.ParseComplexValue(entry(typ, "=="), s)
+			Expect(err).NotTo(HaveOccurred())
+			if !v.IsString() {
+				Expect(v.Number).To(Equal(number))
+			}
+			Expect(v.Format()).To(Equal(formatted))
+		},
+		Entry("int", "INT", "42", 42.0, "42"),
+		Entry("double", "DOUBLE", "0.5", 0.5, "0.5"),
+		Entry("memory", "MEMORY", "4G", float64(4<<30), "4G"),
+		Entry("fractional memory", "MEMORY", "1.5K", 1536.0, "1536"),
+		Entry("infinite memory", "MEMORY", "infinity", math.Inf(1), "INFINITY"),
+		Entry("time", "TIME", "1:30:00", 5400.0, "01:30:00"),
+		Entry("time in seconds", "TIME", "60", 60.0, "00:01:00"),
+		Entry("bool", "BOOL", "true", 1.0, "TRUE"),
+		Entry("rsmap", "RSMAP", "2(gpu0 gpu1)", 2.0, "2"),
+		Entry("string", "STRING", "lx-amd64", 0.0, "lx-amd64"),
+	)
+
+	DescribeTable("rejects invalid values",
+		func(typ, s string) {
+			_, err := core.ParseComplexValue(entry(typ, "=="), s)
+			Expect(err).To(HaveOccurred())
+		},
+		Entry("fractional int", "INT", "1.5"),
+		Entry("memory unit", "MEMORY", "4X"),
+		Entry("bool", "BOOL", "maybe"),
+		Entry("unknown type", "FOO", "1"),
+	)
+
+	It("adds and subtracts consumables", func() {
+		total, _ := core.ParseComplexValue(entry("MEMORY", "<="), "8G")
+		used, _ := core.ParseComplexValue(entry("MEMORY", "<="), "3G")
+		left, err := total.Sub(used)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(left.Format()).To(Equal("5G"))
+		back, err := left.Add(used)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(back).To(Equal(total))
+
+		infinite, _ := core.ParseComplexValue(entry("INT", "<="), "INFINITY")
+		one, _ := core.ParseComplexValue(entry("INT", "<="), "1")
+		left, err = infinite.Sub(one)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(left.Format()).To(Equal("INFINITY"))
+
+		_, err = total.Sub(one)
+		Expect(err).To(MatchError(ContainSubstring("cannot combine")))
+		arch, _ := core.ParseComplexValue(entry("STRING", "=="), "lx-amd64")
+		_, err = arch.Add(arch)
+		Expect(err).To(MatchError(ContainSubstring("no arithmetic")))
+	})
+
+	DescribeTable("compares request relop available",
+		func(typ, relop, request, available string, expected bool) {
+			ok, err := core.CompareComplexValues(entry(typ, relop), request, available)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(Equal(expected))
+		},
+		Entry("memory fits", "MEMORY", "<=", "2G", "4G", true),
+		Entry("memory exceeds", "MEMORY", "<=", "6G", "4G", false),
+		Entry("memory from qhost", "MEMORY", "<=", "2G", "3.812G", true),
+		Entry("load at least", "DOUBLE", ">=", "0.5", "0.25", true),
+		Entry("bool", "BOOL", "==", "TRUE", "1", true),
+		Entry("exclusive", "BOOL", "EXCL", "TRUE", "0", false),
+		Entry("string ignores case", "STRING", "==", "LX-AMD64", "lx-amd64", true),
+		Entry("cstring is case sensitive", "CSTRING", "==", "Foo", "foo", false),
+		Entry("wildcard", "STRING", "==", "lx-*", "lx-arm64", true),
+		Entry("alternatives", "STRING", "==", "sol-*|lx-amd64", "lx-amd64", true),
+		Entry("negation", "STRING", "==", "!lx-amd64", "lx-amd64", false),
+		Entry("not equal", "HOST", "!=", "node1", "NODE1", false),
+	)
+
+	It("rejects unknown relops", func() {
+		_, err := core.CompareComplexValues(entry("INT", "=<"), "1", "2")
+		Expect(err).To(MatchError(ContainSubstring("unknown relop")))
+	})
+})
@@ -0,0 +1,176 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Formula is a weighted sum of complex values and constants, the syntax
+// of dynamic resource quota limits like "$num_proc*2" and of the
+// scheduler's load_formula:
+//
+//	{w|$complex[*w]}[{+|-}{w|$complex[*w]}...]
+//
+// The "$" in front of a complex name is optional.
+type Formula []FormulaTerm
+
+// FormulaTerm is a single summand of a Formula: Weight times the value of
+// Complex or, without Complex, the constant Weight.
+type FormulaTerm struct {
+	Complex string  `json:"complex,omitempty"`
+	Weight  float64 `json:"weight"`
+}
+
+// ParseFormula parses a Formula from its text form.
+func ParseFormula(s string) (Formula, error) {
+	s = strings.Join(strings.Fields(s), "")
+	if s == "" {
+		return nil, fmt.Errorf("empty formula")
+	}
+	var f Formula
+	start := 0
+	for i := 1; i <= len(s); i++ {
+		if i < len(s) && !isFormulaSign(s, i) {
+			continue
+		}
+		term, err := parseFormulaTerm(s[start:i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid formula %q: %w", s, err)
+		}
+		f = append(f, term)
+		start = i
+	}
+	return f, nil
+}
+
+// isFormulaSign reports whether s[i] is a "+" or "-" separating two
+// terms rather than the sign of a factor or of an exponent.
+func isFormulaSign(s string, i int) bool {
+	if s[i] != '+' && s[i] != '-' {
+		return false
+	}
+	prev := s[i-1]
+	if prev == '*' || prev == '+' || prev == '-' {
+		return false
+	}
+	if prev == 'e' || prev == 'E' {
+		// the exponent of a number like 1e-3
+		mantissa := s[strings.LastIndexAny(s[:i-1], "+-*")+1 : i-1]
+		if _, err := strconv.ParseFloat(mantissa, 64); err == nil {
+			return false
+		}
+	}
+	return true
+}
+
+func parseFormulaTerm(s string) (FormulaTerm, error) {
+	term := FormulaTerm{Weight: 1}
+	switch {
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	case strings.HasPrefix(s, "-"):
+		term.Weight, s = -1, s[1:]
+	}
+	for _, factor := range strings.Split(s, "*") {
+		if w, err := strconv.ParseFloat(factor, 64); err == nil {
+			term.Weight *= w
+			continue
+		}
+		name := strings.TrimPrefix(factor, "$")
+		if !isComplexName(name) {
+			return FormulaTerm{}, fmt.Errorf("invalid factor %q", factor)
+		}
+		if term.Complex != "" {
+			return FormulaTerm{}, fmt.Errorf("term %q multiplies two complex values", s)
+		}
+		term.Complex = name
+	}
+	return term, nil
+}
+
+// isComplexName reports whether s can be the name of a complex.
+func isComplexName(s string) bool {
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || r == '.' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the text form of f, with complex names without "$".
+func (f Formula) String() string {
+	var sb strings.Builder
+	for i, term := range f {
+		w := term.Weight
+		switch {
+		case w < 0:
+			sb.WriteString("-")
+			w = -w
+		case i > 0:
+			sb.WriteString("+")
+		}
+		weight := strconv.FormatFloat(w, 'g', -1, 64)
+		switch {
+		case term.Complex == "":
+			sb.WriteString(weight)
+		case w == 1:
+			sb.WriteString(term.Complex)
+		default:
+			sb.WriteString(term.Complex + "*" + weight)
+		}
+	}
+	return sb.String()
+}
+
+// Complexes returns the names of the complexes f refers to.
+func (f Formula) Complexes() []string {
+	var names []string
+	for _, term := range f {
+		if term.Complex != "" {
+			names = append(names, term.Complex)
+		}
+	}
+	return names
+}
+
+// Eval computes f with the complex values returned by value. It fails
+// when f refers to a complex without a value.
+func (f Formula) Eval(value func(complex string) (float64, bool)) (float64, error) {
+	var sum float64
+	for _, term := range f {
+		if term.Complex == "" {
+			sum += term.Weight
+			continue
+		}
+		v, ok := value(term.Complex)
+		if !ok {
+			return 0, fmt.Errorf("no value for %s", term.Complex)
+		}
+		sum += term.Weight * v
+	}
+	return sum, nil
+}
@@ -0,0 +1,299 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	qhost "github.com/hpc-gridware/go-clusterscheduler/pkg/qhost/core"
+	qsub "github.com/hpc-gridware/go-clusterscheduler/pkg/qsub/core"
+)
+
+// globalExecHost is the name of the execution host configuration whose
+// complex values apply to all hosts.
+const globalExecHost = "global"
+
+// HostMatchRequest is the hard resource request of a job as seen by
+// MatchHosts.
+type HostMatchRequest struct {
+	// Resources are the requested values by complex name or shortcut,
+	// like "h_vmem": "4G" or "arch": "lx-*".
+	Resources map[string]string `json:"resources"`
+	// Slots is the number of slots the job needs in a queue instance.
+	// Per slot consumables are requested Slots times. Zero means one.
+	Slots int `json:"slots,omitempty"`
+}
+
+// ResourcesFromJobOptions returns the hard resource requests of the
+// global and master scopes of qsub.JobOptions.ScopedResources, which
+// are the ones a queue instance of the master task must satisfy.
+func ResourcesFromJobOptions(scoped map[string]map[string]qsub.ResourceRequest) map[string]string {
+	resources := make(map[string]string)
+	for _, scope := range []string{qsub.ResourceRequestScopeGlobal, qsub.ResourceRequestScopeMaster} {
+		for name, value := range scoped[scope][qsub.ResourceRequestTypeHard].Resources {
+			resources[name] = value
+		}
+	}
+	return resources
+}
+
+// ParseResourceList parses a resource list like the hard_resource_list
+// of qstat -j, "h_vmem=4G,arch=lx-amd64".
+func ParseResourceList(s string) (map[string]string, error) {
+	resources := make(map[string]string)
+	err := forEachLoadEntry(strings.Fields(s), func(name, value string) error {
+		resources[name] = value
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid resource list %q: %w", s, err)
+	}
+	return resources, nil
+}
+
+// QueueInstanceMatch is the outcome of matching a request against the
+// queue instance Queue@Host. A rejected instance names the first
+// requested Complex it does not satisfy and the Reason, like
+// "offers only hc:h_vmem=2G".
+type QueueInstanceMatch struct {
+	Queue   string `json:"queue"`
+	Host    string `json:"host"`
+	Complex string `json:"complex,omitempty"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// String returns the instance like "all.q@node1", followed by the reason
+// for rejected instances.
+func (m QueueInstanceMatch) String() string {
+	if m.Reason == "" {
+		return m.Queue + "@" + m.Host
+	}
+	return fmt.Sprintf("%s@%s: %s", m.Queue, m.Host, m.Reason)
+}
+
+// HostMatchResult lists the queue instances which satisfy a request and
+// the ones which do not, both ordered by queue and host.
+type HostMatchResult struct {
+	Matching []QueueInstanceMatch `json:"matching"`
+	Rejected []QueueInstanceMatch `json:"rejected"`
+}
+
+// MatchHosts checks every queue instance of cc against the hard resource
+// request req, with the current state of the hosts taken from hosts, as
+// returned by qhost.GetHostsFullMetrics.
+//
+// Like the scheduler it checks each requested complex on every level
+// it is defined on: the queue instance (qname, hostname, slots and
+// complex_values), the host (the values reported by qhost and the
+// complex_values of its execution host) and the global host. Values
+// reported by qhost for consumables are what is left after the running
+// jobs; queue level consumables are compared with their capacity since
+// their usage is not known offline. Requested complexes are checked in
+// name order and the first one failing rejects the instance.
+//
+// It fails when the request names an unknown or non-requestable complex
+// or a value which does not parse as its type, as qsub would.
+func MatchHosts(cc ClusterConfig, hosts []qhost.HostFullMetrics, req HostMatchRequest) (HostMatchResult, error) {
+	requests, err := resolveHostMatchRequest(cc, req)
+	if err != nil {
+		return HostMatchResult{}, err
+	}
+	metrics := make(map[string]qhost.HostFullMetrics, len(hosts))
+	for _, h := range hosts {
+		metrics[strings.ToLower(h.Name)] = h
+	}
+
+	result := HostMatchResult{Matching: []QueueInstanceMatch{}, Rejected: []QueueInstanceMatch{}}
+	for _, queue := range sortedKeys(cc.ClusterQueues) {
+		seen := make(map[string]bool)
+		for _, host := range queueHosts(cc, cc.ClusterQueues[queue]) {
+			if seen[strings.ToLower(host)] {
+				continue
+			}
+			seen[strings.ToLower(host)] = true
+			qi, err := ResolveQueueInstance(cc, queue, host)
+			if err != nil {
+				return HostMatchResult{}, err
+			}
+			m, err := matchQueueInstance(cc, qi, metrics, requests)
+			if err != nil {
+				return HostMatchResult{}, err
+			}
+			if m.Reason == "" {
+				result.Matching = append(result.Matching, m)
+			} else {
+				result.Rejected = append(result.Rejected, m)
+			}
+		}
+	}
+	return result, nil
+}
+
+// complexRequest is a requested complex with its parsed value, already
+// multiplied by the slots for per slot consumables.
+type complexRequest struct {
+	entry ComplexEntryConfig
+	value ComplexValue
+}
+
+func resolveHostMatchRequest(cc ClusterConfig, req HostMatchRequest) ([]complexRequest, error) {
+	slots := req.Slots
+	if slots <= 0 {
+		slots = 1
+	}
+	byShortcut := make(map[string]ComplexEntryConfig)
+	for _, c := range cc.ComplexEntries {
+		if c.Shortcut != "" {
+			byShortcut[c.Shortcut] = c
+		}
+	}
+	resources := make(map[string]string, len(req.Resources)+1)
+	for name, value := range req.Resources {
+		resources[name] = value
+	}
+	if _, ok := resources["slots"]; !ok {
+		if _, ok := cc.ComplexEntries["slots"]; ok {
+			resources["slots"] = "1"
+		}
+	}
+
+	var requests []complexRequest
+	for name, value := range resources {
+		entry, ok := cc.ComplexEntries[name]
+		if !ok {
+			if entry, ok = byShortcut[name]; !ok {
+				return nil, fmt.Errorf("unknown resource %q", name)
+			}
+		}
+		if strings.EqualFold(entry.Requestable, "NO") {
+			return nil, fmt.Errorf("resource %q is not requestable", entry.Name)
+		}
+		v, err := ParseComplexValue(entry, value)
+		if err != nil {
+			return nil, err
+		}
+		if strings.EqualFold(entry.Consumable, ConsumableYES) && !v.IsString() {
+			v.Number *= float64(slots)
+		}
+		requests = append(requests, complexRequest{entry: entry, value: v})
+	}
+	sort.Slice(requests, func(i, j int) bool { return requests[i].entry.Name < requests[j].entry.Name })
+	return requests, nil
+}
+
+// complexOffer is a value a level of a queue instance offers for a
+// complex, with the qstat like prefix of its origin, for example "hc"
+// for a host consumable or "ql" for a queue load value.
+type complexOffer struct {
+	origin string
+	value  string
+}
+
+func matchQueueInstance(cc ClusterConfig, qi QueueInstanceConfig, metrics map[string]qhost.HostFullMetrics,
+	requests []complexRequest) (QueueInstanceMatch, error) {
+	m := QueueInstanceMatch{Queue: qi.Name, Host: qi.Host}
+	queueValues := make(map[string]string)
+	if err := forEachLoadEntry(qi.ComplexValues, func(name, value string) error {
+		queueValues[name] = value
+		return nil
+	}); err != nil {
+		return QueueInstanceMatch{}, fmt.Errorf("complex_values of %s@%s: %w", qi.Name, qi.Host, err)
+	}
+	h, reported := metrics[strings.ToLower(qi.Host)]
+	var loads map[string]float64
+	if reported {
+		loads = HostLoadValues(h, cc.ExecHosts[qi.Host].LoadScaling)
+	}
+
+	for _, r := range requests {
+		name := r.entry.Name
+		consumable := r.entry.Consumable != "" && !strings.EqualFold(r.entry.Consumable, ConsumableNO)
+		kind := "f"
+		if consumable {
+			kind = "c"
+		}
+		var offers []complexOffer
+		switch name {
+		case "qname":
+			offers = append(offers, complexOffer{"qf", qi.Name})
+		case "hostname":
+			offers = append(offers, complexOffer{"qf", qi.Host})
+		case "slots":
+			if len(qi.Slots) > 0 {
+				offers = append(offers, complexOffer{"q" + kind, qi.Slots[0]})
+			}
+		}
+		if v, ok := queueValues[name]; ok {
+			offers = append(offers, complexOffer{"q" + kind, v})
+		}
+		switch {
+		case reported && h.Resources[name].StringValue != "":
+			r := h.Resources[name]
+			origin := strings.ToLower(r.Dominance)
+			if origin == "" {
+				origin = "h" + kind
+			}
+			offers = append(offers, complexOffer{origin, r.StringValue})
+		case reported && name == "arch" && h.Arch != "":
+			offers = append(offers, complexOffer{"hl", h.Arch})
+		case loads != nil && hasLoadValue(loads, name):
+			offers = append(offers, complexOffer{"hl", strconv.FormatFloat(loads[name], 'f', -1, 64)})
+		default:
+			if v, ok := cc.ExecHosts[qi.Host].ComplexValues[name]; ok {
+				offers = append(offers, complexOffer{"h" + kind, v})
+			}
+		}
+		if v, ok := cc.ExecHosts[globalExecHost].ComplexValues[name]; ok {
+			offers = append(offers, complexOffer{"g" + kind, v})
+		}
+
+		if len(offers) == 0 {
+			m.Complex, m.Reason = name, fmt.Sprintf("does not offer %s", name)
+			return m, nil
+		}
+		for _, o := range offers {
+			avail, err := ParseComplexValue(r.entry, o.value)
+			if err != nil {
+				return QueueInstanceMatch{}, fmt.Errorf("%s@%s: %w", qi.Name, qi.Host, err)
+			}
+			ok, err := r.value.Satisfies(r.entry.Relop, avail)
+			if err != nil {
+				return QueueInstanceMatch{}, fmt.Errorf("%s@%s: %w", qi.Name, qi.Host, err)
+			}
+			if !ok {
+				m.Complex = name
+				m.Reason = fmt.Sprintf("offers only %s:%s=%s", o.origin, name, avail.Format())
+				return m, nil
+			}
+		}
+	}
+	return m, nil
+}
+
+// hasLoadValue reports whether loads has a value for name which the host
+// actually reported: the standard values of HostFullMetrics are zero
+// when qhost shows "-".
+func hasLoadValue(loads map[string]float64, name string) bool {
+	v, ok := loads[name]
+	return ok && (v != 0 || loads["num_proc"] != 0)
+}
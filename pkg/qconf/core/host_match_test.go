@@ -0,0 +1,141 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+	qhost "github.com/hpc-gridware/go-clusterscheduler/pkg/qhost/core"
+	qsub "github.com/hpc-gridware/go-clusterscheduler/pkg/qsub/core"
+)
+
+var _ = Describe("MatchHosts", func() {
+
+	cc := core.ClusterConfig{
+		ComplexEntries: map[string]core.ComplexEntryConfig{
+			"arch":     {Name: "arch", Shortcut: "a", Type: "STRING", Relop: "==", Requestable: "YES", Consumable: "NO"},
+			"h_vmem":   {Name: "h_vmem", Shortcut: "h_vmem", Type: "MEMORY", Relop: "<=", Requestable: "YES", Consumable: "YES"},
+			"gpu":      {Name: "gpu", Type: "RSMAP", Relop: "<=", Requestable: "YES", Consumable: "HOST"},
+			"hostname": {Name: "hostname", Shortcut: "h", Type: "HOST", Relop: "==", Requestable: "YES", Consumable: "NO"},
+			"licenses": {Name: "licenses", Type: "INT", Relop: "<=", Requestable: "YES", Consumable: "JOB"},
+			"np_load_avg": {Name: "np_load_avg", Type: "DOUBLE", Relop: ">=", Requestable: "NO",
+				Consumable: "NO"},
+			"slots": {Name: "slots", Shortcut: "s", Type: "INT", Relop: "<=", Requestable: "YES", Consumable: "YES"},
+		},
+		ExecHosts: map[string]core.HostExecConfig{
+			"global": {Name: "global", ComplexValues: map[string]string{"licenses": "2"}},
+			"node1":  {Name: "node1", ComplexValues: map[string]string{"h_vmem": "16G"}},
+			"node2":  {Name: "node2", ComplexValues: map[string]string{"h_vmem": "8G", "gpu": "2(0 1)"}},
+		},
+		ClusterQueues: map[string]core.ClusterQueueConfig{
+			"all.q": {Name: "all.q", HostList: []string{"node1", "node2"}, Slots: []string{"4"},
+				ComplexValues: []string{"NONE"}},
+			"small.q": {Name: "small.q", HostList: []string{"node1"}, Slots: []string{"1"},
+				ComplexValues: []string{"h_vmem=2G"}},
+		},
+	}
+	hosts := []qhost.HostFullMetrics{
+		{Name: "node1", Arch: "lx-amd64", NumProc: 4, Resources: map[string]qhost.ResourceAvailability{
+			"h_vmem": {StringValue: "12.000G", Dominance: "hc"},
+		}},
+		{Name: "node2", Arch: "lx-arm64", NumProc: 2},
+	}
+
+	instances := func(matches []core.QueueInstanceMatch) []string {
+		s := make([]string, 0, len(matches))
+		for _, m := range matches {
+			s = append(s, m.String())
+		}
+		return s
+	}
+
+	It("matches all instances without requests that fit", func() {
+		result, err := core.MatchHosts(cc, hosts, core.HostMatchRequest{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances(result.Matching)).To(Equal([]string{"all.q@node1", "all.q@node2", "small.q@node1"}))
+		Expect(result.Rejected).To(BeEmpty())
+	})
+
+	It("reports the first failing complex of rejected instances", func() {
+		result, err := core.MatchHosts(cc, hosts, core.HostMatchRequest{
+			Resources: map[string]string{"h_vmem": "2G", "a": "lx-*"},
+			Slots:     2,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances(result.Matching)).To(Equal([]string{"all.q@node1", "all.q@node2"}))
+		Expect(instances(result.Rejected)).To(Equal([]string{
+			"small.q@node1: offers only qc:h_vmem=2G",
+		}))
+		Expect(result.Rejected[0].Complex).To(Equal("h_vmem"))
+
+		result, err = core.MatchHosts(cc, hosts, core.HostMatchRequest{
+			Resources: map[string]string{"arch": "lx-amd64", "gpu": "1", "h_vmem": "10G"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Matching).To(BeEmpty())
+		Expect(instances(result.Rejected)).To(Equal([]string{
+			"all.q@node1: does not offer gpu",
+			"all.q@node2: offers only hl:arch=lx-arm64",
+			"small.q@node1: does not offer gpu",
+		}))
+	})
+
+	It("checks global consumables and queue attributes", func() {
+		result, err := core.MatchHosts(cc, hosts, core.HostMatchRequest{
+			Resources: map[string]string{"licenses": "3"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Matching).To(BeEmpty())
+		Expect(result.Rejected[0].Reason).To(Equal("offers only gc:licenses=2"))
+
+		result, err = core.MatchHosts(cc, hosts, core.HostMatchRequest{
+			Resources: map[string]string{"h": "NODE2"},
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(instances(result.Matching)).To(Equal([]string{"all.q@node2"}))
+	})
+
+	It("takes requests from job options and qstat resource lists", func() {
+		resources := core.ResourcesFromJobOptions(map[string]map[string]qsub.ResourceRequest{
+			qsub.ResourceRequestScopeGlobal: {
+				qsub.ResourceRequestTypeHard: {Resources: map[string]string{"h_vmem": "4G"}},
+				qsub.ResourceRequestTypeSoft: {Resources: map[string]string{"arch": "lx-arm64"}},
+			},
+			qsub.ResourceRequestScopeMaster: {
+				qsub.ResourceRequestTypeHard: {Resources: map[string]string{"gpu": "1"}},
+			},
+		})
+		Expect(resources).To(Equal(map[string]string{"h_vmem": "4G", "gpu": "1"}))
+
+		resources, err := core.ParseResourceList("h_vmem=4G, arch=lx-amd64")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resources).To(Equal(map[string]string{"h_vmem": "4G", "arch": "lx-amd64"}))
+	})
+
+	It("rejects unknown and non-requestable resources", func() {
+		_, err := core.MatchHosts(cc, hosts, core.HostMatchRequest{Resources: map[string]string{"foo": "1"}})
+		Expect(err).To(MatchError(`unknown resource "foo"`))
+		_, err = core.MatchHosts(cc, hosts, core.HostMatchRequest{
+			Resources: map[string]string{"np_load_avg": "1"}})
+		Expect(err).To(MatchError(ContainSubstring("not requestable")))
+	})
+})
@@ -97,6 +97,62 @@ exit %d
 	return &Fake{scriptPath: scriptPath, logPath: logPath, tmpDir: dir}
 }
 
+// Response is the canned reply of a dispatching Fake to one invocation.
+type Response struct {
+	Stdout string
+	RC     int
+}
+
+// NewDispatch creates a Fake which answers every invocation with the
+// response registered for its argv, joined by single spaces as in "$*"
+// (e.g. "-sprj p1"). Invocations without a registered response get
+// fallback. Use it for specs that drive several qconf calls, like
+// GetClusterConfiguration.
+func NewDispatch(t TestingT, responses map[string]Response, fallback Response) *Fake {
+	t.Helper()
+	if !Available() {
+		t.Fatalf("fakeqconf.NewDispatch called on unsupported platform %s", runtime.GOOS)
+	}
+
+	dir, err := os.MkdirTemp("", "fake-qconf-*")
+	if err != nil {
+		t.Fatalf("fakeqconf: MkdirTemp: %v", err)
+	}
+	writeBody := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			_ = os.RemoveAll(dir)
+			t.Fatalf("fakeqconf: write stdout fixture: %v", err)
+		}
+		return path
+	}
+
+	logPath := filepath.Join(dir, "argv.log")
+	var script strings.Builder
+	fmt.Fprintf(&script, "#!/usr/bin/env bash\nprintf \"%%s\\n\" \"$*\" >> %s\ncase \"$*\" in\n",
+		shellQuote(logPath))
+	i := 0
+	for argv, resp := range responses {
+		body := writeBody(fmt.Sprintf("stdout-%d.txt", i), resp.Stdout)
+		fmt.Fprintf(&script, "%s) cat %s; exit %d;;\n", shellQuote(argv), shellQuote(body), resp.RC)
+		i++
+	}
+	body := writeBody("stdout-fallback.txt", fallback.Stdout)
+	fmt.Fprintf(&script, "*) cat %s; exit %d;;\nesac\n", shellQuote(body), fallback.RC)
+
+	scriptPath := filepath.Join(dir, "qconf")
+	if err := os.WriteFile(scriptPath, []byte(script.String()), 0o755); err != nil {
+		_ = os.RemoveAll(dir)
+		t.Fatalf("fakeqconf: write script: %v", err)
+	}
+	return &Fake{scriptPath: scriptPath, logPath: logPath, tmpDir: dir}
+}
+
+// shellQuote quotes s as a single bash word without any expansion.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 // Path returns the absolute path to the fake qconf script. Pass it as
 // Executable in core.CommandLineQConfConfig.
 func (f *Fake) Path() string {
@@ -0,0 +1,346 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	qhost "github.com/hpc-gridware/go-clusterscheduler/pkg/qhost/core"
+)
+
+// HostLoadValues returns the load values of a host as reported by qhost,
+// by complex name: the standard values like load_avg, np_load_avg and
+// mem_free, scaled with the load_scaling of the host's execution host
+// configuration, and the numeric resources reported with -F. Resources
+// are taken as they are since qhost reports them already scaled.
+// Resources which are neither numbers, memory values nor times, like
+// arch, are left out.
+func HostLoadValues(m qhost.HostFullMetrics, scaling map[string]float64) map[string]float64 {
+	values := map[string]float64{
+		"num_proc":       m.NumProc,
+		"m_socket":       float64(m.Socket),
+		"m_core":         float64(m.Core),
+		"m_thread":       float64(m.Thread),
+		"mem_total":      float64(m.MemTotal),
+		"swap_total":     float64(m.SwapTotal),
+		"virtual_total":  float64(m.VirtualTotal),
+		"mem_free":       float64(m.MemFree),
+		"swap_free":      float64(m.SwapFree),
+		"virtual_free":   float64(m.VirtualFree),
+		"mem_used":       float64(m.MemUsed),
+		"swap_used":      float64(m.SwapUsed),
+		"virtual_used":   float64(m.VirtualUsed),
+		"load_avg":       m.LoadAvg,
+		"load_short":     m.LoadShort,
+		"load_medium":    m.LoadMedium,
+		"load_long":      m.LoadLong,
+		"np_load_avg":    m.NPLoadAvg,
+		"np_load_short":  m.NPLoadShort,
+		"np_load_medium": m.NPLoadMedium,
+		"np_load_long":   m.NPLoadLong,
+		"cpu":            m.CPU,
+	}
+	for name, factor := range scaling {
+		if v, ok := values[name]; ok {
+			values[name] = v * factor
+		}
+	}
+	for name, r := range m.Resources {
+		v, err := parseAmount(r.StringValue)
+		if err != nil {
+			continue
+		}
+		values[name] = v
+	}
+	return values
+}
+
+// ParseLoadAdjustments parses the job_load_adjustments of the scheduler
+// configuration, entries like "np_load_avg=0.50", into the adjustment by
+// complex name. "NONE" has no adjustments.
+func ParseLoadAdjustments(entries []string) (map[string]float64, error) {
+	adjustments := make(map[string]float64)
+	err := forEachLoadEntry(entries, func(name, value string) error {
+		v, err := parseAmount(value)
+		if err != nil {
+			return fmt.Errorf("invalid load adjustment %s=%s", name, value)
+		}
+		adjustments[name] = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// forEachLoadEntry calls fn with the name and value of every name=value
+// pair in entries. An entry may hold several comma-separated pairs;
+// "NONE" and empty entries are skipped.
+func forEachLoadEntry(entries []string, fn func(name, value string) error) error {
+	for _, entry := range entries {
+		for _, pair := range strings.Split(entry, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" || strings.EqualFold(pair, "NONE") {
+				continue
+			}
+			name, value, ok := strings.Cut(pair, "=")
+			name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+			if !ok || !isComplexName(name) || value == "" {
+				return fmt.Errorf("invalid entry %q, expected name=value", pair)
+			}
+			if err := fn(name, value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// LoadEvaluator computes the load of hosts the way the scheduler does to
+// sort them: the load_formula of the scheduler configuration applied to
+// the load values, after adding the job_load_adjustments for jobs
+// started within the load_adjustment_decay_time.
+type LoadEvaluator struct {
+	Formula     Formula            `json:"formula"`
+	Adjustments map[string]float64 `json:"adjustments,omitempty"`
+	DecayTime   time.Duration      `json:"decay_time"`
+}
+
+// NewLoadEvaluator returns the LoadEvaluator of a scheduler
+// configuration.
+func NewLoadEvaluator(sc SchedulerConfig) (LoadEvaluator, error) {
+	formula, err := ParseFormula(sc.LoadFormula)
+	if err != nil {
+		return LoadEvaluator{}, fmt.Errorf("load_formula: %w", err)
+	}
+	adjustments, err := ParseLoadAdjustments(sc.JobLoadAdjustments)
+	if err != nil {
+		return LoadEvaluator{}, fmt.Errorf("job_load_adjustments: %w", err)
+	}
+	e := LoadEvaluator{Formula: formula, Adjustments: adjustments}
+	if decay := strings.TrimSpace(sc.LoadAdjustmentDecayTime); decay != "" {
+		seconds, err := parseAmount(decay)
+		if err != nil {
+			return LoadEvaluator{}, fmt.Errorf("invalid load_adjustment_decay_time %q", decay)
+		}
+		e.DecayTime = time.Duration(seconds) * time.Second
+	}
+	return e, nil
+}
+
+// Adjust returns values with the load adjustments added for jobs started
+// on the host the given times ago, one per slot. An adjustment decays
+// linearly to zero over DecayTime. Adjustments of the np_ load values are
+// divided by num_proc, like the values themselves. Load values the host
+// does not report are not adjusted.
+func (e LoadEvaluator) Adjust(values map[string]float64, started []time.Duration) map[string]float64 {
+	adjusted := make(map[string]float64, len(values))
+	for name, v := range values {
+		adjusted[name] = v
+	}
+	var factor float64
+	for _, age := range started {
+		if age >= 0 && age < e.DecayTime {
+			factor += 1 - float64(age)/float64(e.DecayTime)
+		}
+	}
+	if factor == 0 {
+		return adjusted
+	}
+	for name, adjustment := range e.Adjustments {
+		v, ok := adjusted[name]
+		if !ok {
+			continue
+		}
+		if n := values["num_proc"]; strings.HasPrefix(name, "np_") && n > 0 {
+			adjustment /= n
+		}
+		adjusted[name] = v + factor*adjustment
+	}
+	return adjusted
+}
+
+// Load returns the load of a host with the given load values. It fails
+// when the formula refers to a value the host does not report.
+func (e LoadEvaluator) Load(values map[string]float64) (float64, error) {
+	return e.Formula.Eval(func(complex string) (float64, bool) {
+		v, ok := values[complex]
+		return v, ok
+	})
+}
+
+// HostLoad is the input of SortHostsByLoad for a single host.
+type HostLoad struct {
+	Host   string             `json:"host"`
+	Values map[string]float64 `json:"values"`
+	// Started are the times since the start of jobs on the host which
+	// are still subject to load adjustment, one per slot.
+	Started []time.Duration `json:"started,omitempty"`
+}
+
+// HostLoadRank is the load of a host as computed by SortHostsByLoad.
+type HostLoadRank struct {
+	Host string  `json:"host"`
+	Load float64 `json:"load"`
+}
+
+// SortHostsByLoad returns the hosts in the order the scheduler prefers
+// them with queue_sort_method load: by ascending load, ties by name.
+// Hosts whose load cannot be computed, usually because they do not
+// report load values, come last with an infinite load.
+func (e LoadEvaluator) SortHostsByLoad(hosts []HostLoad) []HostLoadRank {
+	ranks := make([]HostLoadRank, 0, len(hosts))
+	for _, h := range hosts {
+		load, err := e.Load(e.Adjust(h.Values, h.Started))
+		if err != nil {
+			load = math.Inf(1)
+		}
+		ranks = append(ranks, HostLoadRank{Host: h.Host, Load: load})
+	}
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].Load != ranks[j].Load {
+			return ranks[i].Load < ranks[j].Load
+		}
+		return ranks[i].Host < ranks[j].Host
+	})
+	return ranks
+}
+
+// LoadThreshold is an entry of the load_thresholds or suspend_thresholds
+// of a queue, like "np_load_avg=1.75".
+type LoadThreshold struct {
+	Complex string  `json:"complex"`
+	Value   float64 `json:"value"`
+}
+
+// ParseLoadThresholds parses the load_thresholds or suspend_thresholds of
+// a queue instance as returned by ResolveQueueInstance. "NONE" has no
+// thresholds.
+func ParseLoadThresholds(entries []string) ([]LoadThreshold, error) {
+	var thresholds []LoadThreshold
+	err := forEachLoadEntry(entries, func(name, value string) error {
+		v, err := parseAmount(value)
+		if err != nil {
+			return fmt.Errorf("invalid threshold %s=%s", name, value)
+		}
+		thresholds = append(thresholds, LoadThreshold{Complex: name, Value: v})
+		return nil
+	})
+	return thresholds, err
+}
+
+// LoadAlarm is a threshold a host exceeds. Missing is set when the host
+// does not report the value at all, which qmaster treats as exceeded.
+type LoadAlarm struct {
+	Threshold LoadThreshold `json:"threshold"`
+	Relop     string        `json:"relop"`
+	Value     float64       `json:"value"`
+	Missing   bool          `json:"missing,omitempty"`
+}
+
+// String returns the alarm like qstat -explain a shows it, for example
+// "np_load_avg=2.100000 (>= 1.750000)".
+func (a LoadAlarm) String() string {
+	if a.Missing {
+		return fmt.Sprintf("%s missing (%s %f)", a.Threshold.Complex, a.Relop, a.Threshold.Value)
+	}
+	return fmt.Sprintf("%s=%f (%s %f)", a.Threshold.Complex, a.Value, a.Relop, a.Threshold.Value)
+}
+
+// CheckLoadThresholds returns the thresholds exceeded by values. A
+// threshold is exceeded when the load value compared with the relop of
+// its complex in complexes to the threshold holds, so np_load_avg with
+// relop ">=" exceeds at or above the threshold and mem_free with relop
+// "<=" at or below. Complexes not in complexes compare with ">=".
+func CheckLoadThresholds(thresholds []LoadThreshold, values map[string]float64,
+	complexes map[string]ComplexEntryConfig) []LoadAlarm {
+	var alarms []LoadAlarm
+	for _, t := range thresholds {
+		relop := ">="
+		if c, ok := complexes[t.Complex]; ok && c.Relop != "" {
+			relop = c.Relop
+		}
+		v, ok := values[t.Complex]
+		if !ok {
+			alarms = append(alarms, LoadAlarm{Threshold: t, Relop: relop, Missing: true})
+			continue
+		}
+		if compareLoad(v, relop, t.Value) {
+			alarms = append(alarms, LoadAlarm{Threshold: t, Relop: relop, Value: v})
+		}
+	}
+	return alarms
+}
+
+func compareLoad(v float64, relop string, threshold float64) bool {
+	switch relop {
+	case "==":
+		return v == threshold
+	case "!=":
+		return v != threshold
+	case "<":
+		return v < threshold
+	case "<=":
+		return v <= threshold
+	case ">":
+		return v > threshold
+	}
+	return v >= threshold
+}
+
+// QueueInstanceAlarms is the alarm state of a queue instance predicted
+// by CheckQueueInstanceLoad. With Load alarms the instance is in the
+// load alarm (a) state and gets no further jobs; with Suspend alarms it
+// is in the suspend alarm (A) state and suspends jobs.
+type QueueInstanceAlarms struct {
+	Queue   string      `json:"queue"`
+	Host    string      `json:"host"`
+	Load    []LoadAlarm `json:"load,omitempty"`
+	Suspend []LoadAlarm `json:"suspend,omitempty"`
+}
+
+// CheckQueueInstanceLoad checks the effective load and suspend
+// thresholds of the queue instance queue@host in cc against the load
+// values of the host, as returned by HostLoadValues.
+func CheckQueueInstanceLoad(cc ClusterConfig, queue, host string, values map[string]float64) (QueueInstanceAlarms, error) {
+	qi, err := ResolveQueueInstance(cc, queue, host)
+	if err != nil {
+		return QueueInstanceAlarms{}, err
+	}
+	load, err := ParseLoadThresholds(qi.LoadThresholds)
+	if err != nil {
+		return QueueInstanceAlarms{}, fmt.Errorf("load_thresholds of %s@%s: %w", queue, host, err)
+	}
+	suspend, err := ParseLoadThresholds(qi.SuspendThresholds)
+	if err != nil {
+		return QueueInstanceAlarms{}, fmt.Errorf("suspend_thresholds of %s@%s: %w", queue, host, err)
+	}
+	return QueueInstanceAlarms{
+		Queue:   queue,
+		Host:    host,
+		Load:    CheckLoadThresholds(load, values, cc.ComplexEntries),
+		Suspend: CheckLoadThresholds(suspend, values, cc.ComplexEntries),
+	}, nil
+}
@@ -0,0 +1,143 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+	qhost "github.com/hpc-gridware/go-clusterscheduler/pkg/qhost/core"
+)
+
+var _ = Describe("Load evaluation", func() {
+
+	Context("load values", func() {
+
+		It("scales standard values and parses resources", func() {
+			values := core.HostLoadValues(qhost.HostFullMetrics{
+				NumProc:   4,
+				LoadAvg:   2,
+				NPLoadAvg: 0.5,
+				Resources: map[string]qhost.ResourceAvailability{
+					"mem_free": {StringValue: "1.000G"},
+					"arch":     {StringValue: "lx-amd64"},
+					"gpu":      {StringValue: "2.000000"},
+				},
+			}, map[string]float64{"load_avg": 0.5})
+			Expect(values["load_avg"]).To(Equal(1.0))
+			Expect(values["np_load_avg"]).To(Equal(0.5))
+			Expect(values["mem_free"]).To(Equal(float64(1 << 30)))
+			Expect(values["gpu"]).To(Equal(2.0))
+			Expect(values).NotTo(HaveKey("arch"))
+		})
+	})
+
+	Context("host sort order", func() {
+
+		var e core.LoadEvaluator
+
+		BeforeEach(func() {
+			var err error
+			e, err = core.NewLoadEvaluator(core.SchedulerConfig{
+				LoadFormula:             "np_load_avg+gpu*-0.1",
+				JobLoadAdjustments:      []string{"np_load_avg=0.50"},
+				LoadAdjustmentDecayTime: "0:10:00",
+			})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("applies decaying load adjustments per slot", func() {
+			values := map[string]float64{"num_proc": 2, "np_load_avg": 0.1}
+			adjusted := e.Adjust(values, []time.Duration{0, 5 * time.Minute, 10 * time.Minute})
+			Expect(adjusted["np_load_avg"]).To(BeNumerically("~", 0.1+1.5*0.25))
+			Expect(values["np_load_avg"]).To(Equal(0.1))
+		})
+
+		It("sorts by ascending load with unknown hosts last", func() {
+			ranks := e.SortHostsByLoad([]core.HostLoad{
+				{Host: "down", Values: map[string]float64{}},
+				{Host: "busy", Values: map[string]float64{"np_load_avg": 0.9, "gpu": 0}},
+				{Host: "idle", Values: map[string]float64{"np_load_avg": 0.2, "gpu": 0}},
+				{Host: "gpu", Values: map[string]float64{"np_load_avg": 0.2, "gpu": 4}},
+				{Host: "fresh", Values: map[string]float64{"num_proc": 1, "np_load_avg": 0.2, "gpu": 0},
+					Started: []time.Duration{time.Minute}},
+			})
+			hosts := make([]string, 0, len(ranks))
+			for _, r := range ranks {
+				hosts = append(hosts, r.Host)
+			}
+			Expect(hosts).To(Equal([]string{"gpu", "idle", "fresh", "busy", "down"}))
+			Expect(math.IsInf(ranks[4].Load, 1)).To(BeTrue())
+		})
+
+		It("rejects invalid scheduler settings", func() {
+			_, err := core.NewLoadEvaluator(core.SchedulerConfig{LoadFormula: "np_load_avg",
+				JobLoadAdjustments: []string{"np_load_avg"}})
+			Expect(err).To(MatchError(ContainSubstring("job_load_adjustments")))
+			_, err = core.NewLoadEvaluator(core.SchedulerConfig{LoadFormula: "np_load_avg*x*y"})
+			Expect(err).To(MatchError(ContainSubstring("load_formula")))
+		})
+	})
+
+	Context("thresholds", func() {
+
+		cc := core.ClusterConfig{
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {
+					Name:              "all.q",
+					HostList:          []string{"node1", "node2"},
+					LoadThresholds:    []string{"np_load_avg=1.75", "[node2=np_load_avg=3,mem_free=1G]"},
+					SuspendThresholds: []string{"NONE"},
+				},
+			},
+			ComplexEntries: map[string]core.ComplexEntryConfig{
+				"np_load_avg": {Name: "np_load_avg", Relop: ">="},
+				"mem_free":    {Name: "mem_free", Relop: "<="},
+			},
+		}
+
+		It("predicts load alarms of queue instances", func() {
+			values := map[string]float64{"np_load_avg": 2, "mem_free": 512 << 20}
+			alarms, err := core.CheckQueueInstanceLoad(cc, "all.q", "node1", values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(alarms.Load).To(Equal([]core.LoadAlarm{{
+				Threshold: core.LoadThreshold{Complex: "np_load_avg", Value: 1.75},
+				Relop:     ">=", Value: 2}}))
+			Expect(alarms.Load[0].String()).To(Equal("np_load_avg=2.000000 (>= 1.750000)"))
+			Expect(alarms.Suspend).To(BeEmpty())
+
+			alarms, err = core.CheckQueueInstanceLoad(cc, "all.q", "node2", values)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(alarms.Load).To(HaveLen(1))
+			Expect(alarms.Load[0].Threshold.Complex).To(Equal("mem_free"))
+		})
+
+		It("treats missing load values as exceeded", func() {
+			alarms, err := core.CheckQueueInstanceLoad(cc, "all.q", "node1", map[string]float64{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(alarms.Load).To(HaveLen(1))
+			Expect(alarms.Load[0].Missing).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,346 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// attrObjectNames maps the object kinds which qconf can change attribute
+// by attribute to their object name for -mattr and friends.
+var attrObjectNames = map[ObjectKind]string{
+	KindClusterQueue:        "queue",
+	KindExecHost:            "exechost",
+	KindHostGroup:           "hostgroup",
+	KindParallelEnvironment: "pe",
+	KindCkptInterface:       "ckpt",
+}
+
+// AttributeChange is a single qconf attribute operation changing one
+// attribute of an object.
+type AttributeChange struct {
+	// Op is the qconf option: -mattr, -aattr, -dattr, -rattr or -purge.
+	Op     string `json:"op"`
+	Object string `json:"object"`
+	Attr   string `json:"attr"`
+	// Value is empty for -purge.
+	Value string `json:"value,omitempty"`
+	// Instance is the object name or, for host specific values of a
+	// queue, the queue instance like "all.q@@gpu".
+	Instance string `json:"instance"`
+}
+
+// Args returns the qconf arguments of c.
+func (c AttributeChange) Args() []string {
+	if c.Op == "-purge" {
+		return []string{c.Op, c.Object, c.Attr, c.Instance}
+	}
+	return []string{c.Op, c.Object, c.Attr, c.Value, c.Instance}
+}
+
+func (c AttributeChange) run(qc QConf) error {
+	switch c.Op {
+	case "-mattr":
+		return qc.ModifyAttribute(c.Object, c.Attr, c.Value, c.Instance)
+	case "-aattr":
+		return qc.AddAttribute(c.Object, c.Attr, c.Value, c.Instance)
+	case "-dattr":
+		return qc.DeleteAttribute(c.Object, c.Attr, c.Value, c.Instance)
+	case "-rattr":
+		return replaceAttribute(qc, c.Object, c.Attr, c.Value, c.Instance)
+	case "-purge":
+		return purgeAttribute(qc, c.Object, c.Attr, c.Instance)
+	}
+	return fmt.Errorf("unknown attribute operation %q", c.Op)
+}
+
+// supportedBy reports whether qc can run c: -rattr needs an
+// AttributeReplacer and -purge an AttributePurger. Decorators like
+// CachingQConf, which have these methods whatever they wrap, decide
+// themselves.
+func (c AttributeChange) supportedBy(qc QConf) bool {
+	if d, ok := qc.(interface{ supports(AttributeChange) bool }); ok {
+		return d.supports(c)
+	}
+	switch c.Op {
+	case "-rattr":
+		_, ok := qc.(AttributeReplacer)
+		return ok
+	case "-purge":
+		_, ok := qc.(AttributePurger)
+		return ok
+	}
+	return true
+}
+
+// AttributeChanges returns the attribute operations which turn the
+// object current of the given kind into desired. It reports false when
+// the change cannot be expressed with attribute operations: for kinds
+// qconf has no attribute operations for, for renamed objects, changed
+// ExtraFields and values qconf cannot take on its command line, like
+// empty strings. The object must then be replaced as a whole.
+//
+// Lists are changed element by element with -aattr and -dattr, or
+// replaced with -rattr when only their order changed. Host specific
+// values of queue attributes are set on their queue instance and
+// removed with -purge.
+func AttributeChanges(kind ObjectKind, current, desired any) ([]AttributeChange, bool) {
+	object, ok := attrObjectNames[kind]
+	if !ok {
+		return nil, false
+	}
+	cv, dv := reflect.ValueOf(current), reflect.ValueOf(desired)
+	if cv.Type() != dv.Type() || cv.Kind() != reflect.Struct {
+		return nil, false
+	}
+	name := dv.Field(0).String()
+	if cv.Field(0).String() != name {
+		return nil, false
+	}
+
+	var changes []AttributeChange
+	add := func(op, attr, value, instance string) {
+		changes = append(changes, AttributeChange{Op: op, Object: object, Attr: attr, Value: value,
+			Instance: instance})
+	}
+	t := dv.Type()
+	for i := 1; i < t.NumField(); i++ {
+		old, new := cv.Field(i), dv.Field(i)
+		if reflect.DeepEqual(old.Interface(), new.Interface()) {
+			continue
+		}
+		attr, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if t.Field(i).Name == "ExtraFields" || attr == "" {
+			return nil, false
+		}
+		switch new.Kind() {
+		case reflect.String:
+			if !attrValueOK(new.String()) {
+				return nil, false
+			}
+			add("-mattr", attr, new.String(), name)
+		case reflect.Int:
+			add("-mattr", attr, strconv.FormatInt(new.Int(), 10), name)
+		case reflect.Float64:
+			add("-mattr", attr, strconv.FormatFloat(new.Float(), 'f', -1, 64), name)
+		case reflect.Bool:
+			add("-mattr", attr, MakeBoolCfg(new.Bool()), name)
+		case reflect.Map:
+			ops, ok := mapAttrChanges(old, new)
+			if !ok {
+				return nil, false
+			}
+			for _, op := range ops {
+				add(op[0], attr, op[1], name)
+			}
+		case reflect.Slice:
+			oldList, ok1 := old.Interface().([]string)
+			newList, ok2 := new.Interface().([]string)
+			if !ok1 || !ok2 {
+				return nil, false
+			}
+			var ops [][3]string
+			if kind == KindClusterQueue && attr != "hostlist" {
+				ops, ok = queueAttrChanges(attr, name, oldList, newList)
+			} else {
+				ops, ok = listAttrChanges(name, oldList, newList)
+			}
+			if !ok {
+				return nil, false
+			}
+			for _, op := range ops {
+				add(op[0], attr, op[1], op[2])
+			}
+		default:
+			return nil, false
+		}
+	}
+	return changes, true
+}
+
+// attrValueOK reports whether s can be passed as an attribute value on
+// the qconf command line.
+func attrValueOK(s string) bool {
+	return s != "" && !strings.HasPrefix(s, "-") && !strings.ContainsAny(s, "\n\r")
+}
+
+// mapAttrChanges returns the operations and values changing a key=value
+// map attribute like complex_values from old to new.
+func mapAttrChanges(old, new reflect.Value) ([][2]string, bool) {
+	format := func(m reflect.Value, key string) (string, bool) {
+		v := m.MapIndex(reflect.ValueOf(key))
+		switch v.Kind() {
+		case reflect.String:
+			return key + "=" + v.String(), attrValueOK(v.String())
+		case reflect.Float64:
+			return key + "=" + strconv.FormatFloat(v.Float(), 'f', -1, 64), true
+		}
+		return "", false
+	}
+	if old.Type().Key().Kind() != reflect.String {
+		return nil, false
+	}
+	keys := func(m reflect.Value) map[string]bool {
+		set := make(map[string]bool)
+		for _, k := range m.MapKeys() {
+			set[k.String()] = true
+		}
+		return set
+	}
+	oldKeys, newKeys := keys(old), keys(new)
+	var ops [][2]string
+	for _, key := range unionKeys(oldKeys, newKeys) {
+		switch {
+		case !newKeys[key]:
+			value, ok := format(old, key)
+			if !ok {
+				return nil, false
+			}
+			ops = append(ops, [2]string{"-dattr", value})
+		case !oldKeys[key]:
+			value, ok := format(new, key)
+			if !ok {
+				return nil, false
+			}
+			ops = append(ops, [2]string{"-aattr", value})
+		case !reflect.DeepEqual(old.MapIndex(reflect.ValueOf(key)).Interface(),
+			new.MapIndex(reflect.ValueOf(key)).Interface()):
+			value, ok := format(new, key)
+			if !ok {
+				return nil, false
+			}
+			ops = append(ops, [2]string{"-mattr", value})
+		}
+	}
+	return ops, true
+}
+
+// listAttrChanges returns the operations, values and instances changing
+// a plain list attribute like the hostlist of a host group.
+func listAttrChanges(name string, old, new []string) ([][3]string, bool) {
+	old, new = noneToEmpty(old), noneToEmpty(new)
+	var ops [][3]string
+	for _, e := range old {
+		if !slices.Contains(new, e) {
+			ops = append(ops, [3]string{"-dattr", e, name})
+		}
+	}
+	for _, e := range new {
+		if !slices.Contains(old, e) {
+			ops = append(ops, [3]string{"-aattr", e, name})
+		}
+	}
+	if len(ops) == 0 {
+		// same elements in another order
+		return [][3]string{{"-rattr", JoinList(new, " "), name}}, true
+	}
+	for _, op := range ops {
+		if !attrValueOK(op[1]) {
+			return nil, false
+		}
+	}
+	return ops, true
+}
+
+// queueAttrChanges returns the operations, values and instances changing
+// a cluster queue attribute with host specific values. List attributes
+// are replaced with -rattr, single values set with -mattr.
+func queueAttrChanges(attr, queue string, old, new []string) ([][3]string, bool) {
+	oldValues, newValues := splitQueueAttr(attr, old), splitQueueAttr(attr, new)
+	op := "-mattr"
+	if queueSpaceListFields[attr] || queueCommaListFields[attr] {
+		op = "-rattr"
+	}
+	var ops [][3]string
+	for _, host := range unionKeys(oldValues, newValues) {
+		instance := queue
+		if host != "" {
+			instance = queueInstance(queue, host)
+		}
+		value, inNew := newValues[host]
+		switch {
+		case !inNew && host == "":
+			ops = append(ops, [3]string{op, "NONE", instance})
+		case !inNew:
+			ops = append(ops, [3]string{"-purge", "", instance})
+		case value != oldValues[host]:
+			if !attrValueOK(value) {
+				return nil, false
+			}
+			ops = append(ops, [3]string{op, value, instance})
+		}
+	}
+	return ops, true
+}
+
+// splitQueueAttr returns the values of a cluster queue attribute by host
+// or host group, with the cluster-wide value under "".
+func splitQueueAttr(attr string, entries []string) map[string]string {
+	values := make(map[string]string)
+	var defaults []string
+	for _, e := range entries {
+		if host, value, ok := parseOverride(e); ok {
+			values[host] = value
+		} else {
+			defaults = append(defaults, e)
+		}
+	}
+	if len(defaults) > 0 {
+		sep := ","
+		if queueSpaceListFields[attr] {
+			sep = " "
+		}
+		values[""] = strings.Join(defaults, sep)
+	}
+	return values
+}
+
+// ModifyFields changes the object name of the given kind from current to
+// desired with the attribute operations returned by AttributeChanges, so
+// only the changed attributes are touched in qmaster. When that is not
+// possible, or when qc cannot run all of the attribute operations, it
+// replaces the object as a whole like the Modify* calls do.
+func ModifyFields(qc QConf, kind ObjectKind, name string, current, desired any) error {
+	changes, ok := AttributeChanges(kind, current, desired)
+	if !ok || slices.ContainsFunc(changes, func(c AttributeChange) bool {
+		return !c.supportedBy(qc)
+	}) {
+		ops, known := kindOpsOf(kind)
+		if !known {
+			return fmt.Errorf("unknown object kind %q", kind)
+		}
+		return ops.modify(qc, name, desired)
+	}
+	// deletions first, so a replaced list element is never present twice
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Op == "-dattr" && changes[j].Op != "-dattr"
+	})
+	for _, c := range changes {
+		if err := c.run(qc); err != nil {
+			return fmt.Errorf("failed to change %s of %s %s: %w", c.Attr, kind, name, err)
+		}
+	}
+	return nil
+}
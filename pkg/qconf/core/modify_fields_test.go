@@ -0,0 +1,177 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"slices"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("ModifyFields", func() {
+
+	Context("AttributeChanges", func() {
+
+		It("adds and deletes host group members", func() {
+			changes, ok := core.AttributeChanges(core.KindHostGroup,
+				core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node1", "node2"}},
+				core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node1", "node3"}})
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(ConsistOf(
+				core.AttributeChange{Op: "-dattr", Object: "hostgroup", Attr: "hostlist",
+					Value: "node2", Instance: "@rack1"},
+				core.AttributeChange{Op: "-aattr", Object: "hostgroup", Attr: "hostlist",
+					Value: "node3", Instance: "@rack1"},
+			))
+			Expect(changes[0].Args()).To(Equal(
+				[]string{"-dattr", "hostgroup", "hostlist", "node2", "@rack1"}))
+		})
+
+		It("replaces a list whose order changed", func() {
+			changes, ok := core.AttributeChanges(core.KindHostGroup,
+				core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node1", "node2"}},
+				core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node2", "node1"}})
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(Equal([]core.AttributeChange{{Op: "-rattr", Object: "hostgroup",
+				Attr: "hostlist", Value: "node2 node1", Instance: "@rack1"}}))
+		})
+
+		It("changes queue defaults and host specific values", func() {
+			current := core.ClusterQueueConfig{
+				Name:   "all.q",
+				Slots:  []string{"1", "[@gpu=4]", "[node1=2]"},
+				PeList: []string{"make"},
+			}
+			desired := current
+			desired.Slots = []string{"2", "[@gpu=8]"}
+			desired.PeList = []string{"make smp"}
+
+			changes, ok := core.AttributeChanges(core.KindClusterQueue, current, desired)
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(ConsistOf(
+				core.AttributeChange{Op: "-mattr", Object: "queue", Attr: "slots",
+					Value: "2", Instance: "all.q"},
+				core.AttributeChange{Op: "-mattr", Object: "queue", Attr: "slots",
+					Value: "8", Instance: "all.q@@gpu"},
+				core.AttributeChange{Op: "-purge", Object: "queue", Attr: "slots",
+					Instance: "all.q@node1"},
+				core.AttributeChange{Op: "-rattr", Object: "queue", Attr: "pe_list",
+					Value: "make smp", Instance: "all.q"},
+			))
+		})
+
+		It("changes map entries of an exec host", func() {
+			changes, ok := core.AttributeChanges(core.KindExecHost,
+				core.HostExecConfig{Name: "node1",
+					ComplexValues: map[string]string{"gpu": "2", "scratch": "100G"}},
+				core.HostExecConfig{Name: "node1",
+					ComplexValues: map[string]string{"gpu": "4", "ssd": "1"}})
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(Equal([]core.AttributeChange{
+				{Op: "-mattr", Object: "exechost", Attr: "complex_values", Value: "gpu=4", Instance: "node1"},
+				{Op: "-dattr", Object: "exechost", Attr: "complex_values", Value: "scratch=100G", Instance: "node1"},
+				{Op: "-aattr", Object: "exechost", Attr: "complex_values", Value: "ssd=1", Instance: "node1"},
+			}))
+		})
+
+		It("requires a full replacement when attributes cannot be changed", func() {
+			_, ok := core.AttributeChanges(core.KindUser,
+				core.UserConfig{Name: "alice"}, core.UserConfig{Name: "alice", FShare: 1})
+			Expect(ok).To(BeFalse())
+
+			_, ok = core.AttributeChanges(core.KindParallelEnvironment,
+				core.ParallelEnvironmentConfig{Name: "smp", StartProcArgs: "/bin/true"},
+				core.ParallelEnvironmentConfig{Name: "smp"})
+			Expect(ok).To(BeFalse())
+
+			_, ok = core.AttributeChanges(core.KindHostGroup,
+				core.HostGroupConfig{Name: "@rack1"},
+				core.HostGroupConfig{Name: "@rack1",
+					ExtraFields: map[string]string{"future": "1"}})
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("in-memory qconf", func() {
+
+		var qc *core.InMemoryQConf
+
+		BeforeEach(func() {
+			qc = newInMemoryQConf(core.ClusterConfig{
+				HostGroups: map[string]core.HostGroupConfig{
+					"@allhosts": {Name: "@allhosts", Hosts: []string{"node1", "node2"}},
+				},
+			})
+			Expect(qc.AddClusterQueue(core.ClusterQueueConfig{
+				Name:     "all.q",
+				HostList: []string{"@allhosts"},
+				Slots:    []string{"1", "[node1=2]"},
+			})).To(Succeed())
+		})
+
+		It("reaches the desired queue", func() {
+			current, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			desired := current
+			desired.Slots = []string{"4", "[@allhosts=8]"}
+			desired.PeList = []string{"make", "smp"}
+
+			Expect(core.ModifyFields(qc, core.KindClusterQueue, "all.q", current, desired)).To(Succeed())
+			q, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(q.Slots).To(Equal([]string{"4", "[@allhosts=8]"}))
+			Expect(q.PeList).To(Equal([]string{"make", "smp"}))
+		})
+
+		It("replaces the object when qconf -purge is not supported", func() {
+			current, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			desired := current
+			desired.Slots = []string{"4"}
+			changes, ok := core.AttributeChanges(core.KindClusterQueue, current, desired)
+			Expect(ok).To(BeTrue())
+			Expect(changes).To(ContainElement(HaveField("Op", "-purge")))
+
+			plain := struct{ core.QConf }{qc}
+			Expect(core.ModifyFields(plain, core.KindClusterQueue, "all.q", current, desired)).To(Succeed())
+			q, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(q.Slots).To(Equal([]string{"4"}))
+		})
+
+		It("modifies all entries attribute by attribute", func() {
+			desired := core.ClusterConfig{
+				HostGroups: map[string]core.HostGroupConfig{
+					"@allhosts": {Name: "@allhosts", Hosts: []string{"node2", "node3"}},
+				},
+			}
+			_, err := core.ModifyAllEntriesWithOptions(qc, desired,
+				core.ModifyOptions{MinimalChanges: true})
+			Expect(err).NotTo(HaveOccurred())
+
+			hg, err := qc.ShowHostGroup("@allhosts")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slices.Sorted(slices.Values(hg.Hosts))).To(Equal([]string{"node2", "node3"}))
+		})
+	})
+})
@@ -19,6 +19,11 @@
 
 package core
 
+import (
+	"errors"
+	"fmt"
+)
+
 // QConf defines the methods for interacting with the Open Cluster Scheduler
 // configuration. The methods are named after the qconf command line tool
 // which is used to interact with the Open Cluster Scheduler configuration.
@@ -183,3 +188,39 @@ type QConf interface {
 	ModifySchedulerConfig(cfg SchedulerConfig) error
 	ShowSchedulerConfiguration() (*SchedulerConfig, error)
 }
+
+// AttributeReplacer is implemented by the QConf implementations which
+// can replace the complete value of a list-valued attribute, like qconf
+// -rattr. CommandLineQConf, InMemoryQConf and CachingQConf implement it.
+type AttributeReplacer interface {
+	ReplaceAttribute(objName, attrName, val, objIDList string) error
+}
+
+// replaceAttribute calls ReplaceAttribute of qc. QConf implementations
+// which are no AttributeReplacer return an error wrapping
+// errors.ErrUnsupported.
+func replaceAttribute(qc QConf, objName, attrName, val, objIDList string) error {
+	r, ok := qc.(AttributeReplacer)
+	if !ok {
+		return fmt.Errorf("%T cannot replace attributes: %w", qc, errors.ErrUnsupported)
+	}
+	return r.ReplaceAttribute(objName, attrName, val, objIDList)
+}
+
+// AttributePurger is implemented by the QConf implementations which can
+// remove the host or host group specific values of queue attributes,
+// like qconf -purge. CommandLineQConf, InMemoryQConf and CachingQConf
+// implement it.
+type AttributePurger interface {
+	PurgeAttribute(objName, attrName, objInstance string) error
+}
+
+// purgeAttribute calls PurgeAttribute of qc. QConf implementations which
+// are no AttributePurger return an error wrapping errors.ErrUnsupported.
+func purgeAttribute(qc QConf, objName, attrName, objInstance string) error {
+	p, ok := qc.(AttributePurger)
+	if !ok {
+		return fmt.Errorf("%T cannot purge attributes: %w", qc, errors.ErrUnsupported)
+	}
+	return p.PurgeAttribute(objName, attrName, objInstance)
+}
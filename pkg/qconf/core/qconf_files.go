@@ -0,0 +1,298 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
+)
+
+// ExportQconfFiles writes all objects of cc into dir in the text format
+// of qconf -A* and -M*, following the layout of a save_sge_config.sh
+// backup:
+//
+//	configurations/global      global configuration
+//	configurations/<host>      host configurations
+//	schedconf                  scheduler configuration
+//	execution/<host>           exec hosts
+//	admin_hosts/<host>         admin hosts (empty files)
+//	submit_hosts/<host>        submit hosts (empty files)
+//	managers, operators        one name per line
+//	calendars/, centry/, ckpt/, cqueues/, hostgroups/, pe/,
+//	projects/, resource_quotas/, users/, usersets/
+//
+// Every file is named like its object, so each can be fed to qconf on
+// its own, e.g. "qconf -Aq cqueues/all.q". Defaults are applied like in
+// the Add* and Modify* calls, so empty fields are written as NONE.
+//
+// dir must not exist, be empty or hold an earlier export, which is
+// recognized by its marker file; any other directory is refused, so an
+// export never wipes e.g. a spool directory. The files are written into
+// a temporary directory next to dir, which replaces dir as a whole only
+// when all files were written: a failed export leaves dir untouched and
+// objects of an earlier export which are not in cc anymore do not
+// survive.
+func ExportQconfFiles(cc ClusterConfig, dir string) error {
+	dir = filepath.Clean(dir)
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read export directory: %w", err)
+	}
+	existing := err == nil
+	if len(entries) > 0 {
+		if _, err := os.Stat(filepath.Join(dir, qconfFilesMarker)); err != nil {
+			return fmt.Errorf("%s is not empty and holds no earlier export", dir)
+		}
+	}
+
+	parent := filepath.Dir(dir)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(parent, "."+filepath.Base(dir)+".export-*")
+	if err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	defer os.RemoveAll(tmp)
+	if err := os.Chmod(tmp, 0755); err != nil {
+		return err
+	}
+	if err := writeQconfFiles(cc, tmp); err != nil {
+		return err
+	}
+
+	old := tmp + ".old"
+	if existing {
+		if err := os.Rename(dir, old); err != nil {
+			return fmt.Errorf("failed to replace %s: %w", dir, err)
+		}
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		if existing {
+			os.Rename(old, dir)
+		}
+		return fmt.Errorf("failed to replace %s: %w", dir, err)
+	}
+	return os.RemoveAll(old)
+}
+
+// qconfFilesMarker is the file which marks a directory written by
+// ExportQconfFiles.
+const qconfFilesMarker = ".qconf-export"
+
+// writeQconfFiles writes cc into the empty directory dir, see
+// ExportQconfFiles.
+func writeQconfFiles(cc ClusterConfig, dir string) error {
+	w := objectFileWriter{dir: dir}
+	w.write("", qconfFilesMarker, nil)
+	if cc.GlobalConfig != nil {
+		w.write("configurations", "global", func(f *os.File) error {
+			return writeGlobalConfig(f, *cc.GlobalConfig)
+		})
+	}
+	for name, hc := range cc.HostConfigurations {
+		w.write("configurations", name, func(f *os.File) error {
+			return writeHostConfiguration(f, hc)
+		})
+	}
+	if cc.SchedulerConfig != nil {
+		w.write("", "schedconf", func(f *os.File) error {
+			return writeSchedulerConfig(f, *cc.SchedulerConfig)
+		})
+	}
+	for name, cal := range cc.Calendars {
+		cal.Name = name
+		w.write("calendars", name, func(f *os.File) error {
+			return writeCalendar(f, cal)
+		})
+	}
+	for name, ce := range cc.ComplexEntries {
+		ce.Name = name
+		SetDefaultComplexEntryValues(&ce)
+		w.write("centry", name, func(f *os.File) error {
+			return writeComplexEntry(f, ce)
+		})
+	}
+	for name, ckpt := range cc.CkptInterfaces {
+		ckpt.Name = name
+		w.write("ckpt", name, func(f *os.File) error {
+			return writeCkptInterface(f, ckpt)
+		})
+	}
+	for name, q := range cc.ClusterQueues {
+		q.Name = name
+		SetDefaultQueueValues(&q)
+		w.write("cqueues", name, func(f *os.File) error {
+			return writeClusterQueue(f, q)
+		})
+	}
+	for name, eh := range cc.ExecHosts {
+		eh.Name = name
+		SetDefaultExecHostConfig(&eh)
+		w.write("execution", name, func(f *os.File) error {
+			return writeExecHost(f, eh)
+		})
+	}
+	for name, hg := range cc.HostGroups {
+		hg.Name = name
+		w.write("hostgroups", name, func(f *os.File) error {
+			return writeHostGroup(f, hg)
+		})
+	}
+	for name, pe := range cc.ParallelEnvironments {
+		pe.Name = name
+		SetDefaultParallelEnvironmentValues(&pe)
+		w.write("pe", name, func(f *os.File) error {
+			return writePE(f, pe)
+		})
+	}
+	for name, p := range cc.Projects {
+		p.Name = name
+		SetDefaultProjectValues(&p)
+		w.write("projects", name, func(f *os.File) error {
+			return writeProject(f, p)
+		})
+	}
+	for name, rqs := range cc.ResourceQuotaSets {
+		rqs.Name = name
+		SetResourceQuotaSetDefaults(&rqs)
+		w.write("resource_quotas", name, func(f *os.File) error {
+			return writeResourceQuotaSet(f, rqs)
+		})
+	}
+	for name, u := range cc.Users {
+		u.Name = name
+		SetDefaultUserValues(&u)
+		w.write("users", name, func(f *os.File) error {
+			return writeUser(f, u)
+		})
+	}
+	for name, us := range cc.UserSetLists {
+		us.Name = name
+		SetDefaultUserSetListConfig(&us)
+		w.write("usersets", name, func(f *os.File) error {
+			return writeUserSetList(f, us)
+		})
+	}
+	for _, host := range cc.AdminHosts {
+		w.write("admin_hosts", host, nil)
+	}
+	for _, host := range cc.SubmitHosts {
+		w.write("submit_hosts", host, nil)
+	}
+	if len(cc.Managers) > 0 {
+		w.write("", "managers", writeNames(cc.Managers))
+	}
+	if len(cc.Operators) > 0 {
+		w.write("", "operators", writeNames(cc.Operators))
+	}
+	return w.err
+}
+
+// ImportQconfFiles reads a directory written by ExportQconfFiles or by
+// save_sge_config.sh back into a ClusterConfig. Missing directories
+// are skipped; all maps of the returned configuration are allocated.
+// Pass the result to Apply to restore the backup or to
+// CompareTo to diff it against the live cluster.
+func ImportQconfFiles(dir string) (ClusterConfig, error) {
+	var cc ClusterConfig
+	if info, err := os.Stat(dir); err != nil {
+		return cc, fmt.Errorf("failed to read qconf files: %w", err)
+	} else if !info.IsDir() {
+		return cc, fmt.Errorf("%s is not a directory", dir)
+	}
+	initClusterConfigMaps(&cc)
+	r := spoolReader{dirs: ClassicSpoolDirs{SpoolDir: dir}}
+
+	r.readObjectDirs(&cc, "execution", false)
+	r.objects("configurations", func(name string, lines []string) error {
+		if name == "global" {
+			global := ParseGlobalConfigFromLines(lines)
+			cc.GlobalConfig = &global
+			return nil
+		}
+		hc := ParseHostConfigurationFromLines(lines)
+		hc.Name = name
+		cc.HostConfigurations[name] = hc
+		return nil
+	})
+	r.file([]string{"schedconf"}, func(lines []string) error {
+		sched := ParseSchedulerConfigFromLines(lines)
+		cc.SchedulerConfig = &sched
+		return nil
+	})
+	if r.err != nil {
+		return ClusterConfig{}, r.err
+	}
+	return cc, nil
+}
+
+// objectFileWriter writes object files below dir and remembers the
+// first error.
+type objectFileWriter struct {
+	dir string
+	err error
+}
+
+// write creates the file name in the sub directory sub of the export
+// directory and fills it with fn. A nil fn leaves the file empty.
+func (w *objectFileWriter) write(sub, name string, fn func(f *os.File) error) {
+	if w.err != nil {
+		return
+	}
+	// object names become file names, so they must not leave dir
+	if err := validate.LocalFileName(name); err != nil {
+		w.err = fmt.Errorf("invalid object name %q: %w", name, err)
+		return
+	}
+	dir := filepath.Join(w.dir, sub)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		w.err = err
+		return
+	}
+	path := filepath.Join(dir, name)
+	f, err := os.Create(path)
+	if err != nil {
+		w.err = err
+		return
+	}
+	if fn != nil {
+		err = fn(f)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		w.err = fmt.Errorf("failed to write %s: %w", path, err)
+	}
+}
+
+// writeNames returns a writer for a list file with one name per line.
+func writeNames(names []string) func(f *os.File) error {
+	return func(f *os.File) error {
+		_, err := f.WriteString(strings.Join(names, "\n") + "\n")
+		return err
+	}
+}
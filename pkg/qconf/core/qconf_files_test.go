@@ -0,0 +1,191 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("ExportQconfFiles", func() {
+
+	var cc core.ClusterConfig
+
+	BeforeEach(func() {
+		mailer := "/usr/bin/mail"
+		cc = core.ClusterConfig{
+			GlobalConfig: &core.GlobalConfig{
+				ExecdSpoolDir: "/opt/cs/default/spool",
+				Mailer:        "/bin/mail",
+			},
+			SchedulerConfig: &core.SchedulerConfig{
+				Algorithm:      "default",
+				MaxReservation: 10,
+			},
+			HostConfigurations: map[string]core.HostConfiguration{
+				"node1": {Name: "node1", Mailer: &mailer},
+			},
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {
+					Name:     "all.q",
+					HostList: []string{"@allhosts"},
+					Slots:    []string{"1", "[node1=4]"},
+				},
+			},
+			ExecHosts: map[string]core.HostExecConfig{
+				"node1": {
+					Name:          "node1",
+					ComplexValues: map[string]string{"mem_free": "1G"},
+				},
+			},
+			HostGroups: map[string]core.HostGroupConfig{
+				"@allhosts": {Name: "@allhosts", Hosts: []string{"node1", "node2"}},
+			},
+			ComplexEntries: map[string]core.ComplexEntryConfig{
+				"gpu": {Name: "gpu", Shortcut: "gpu", Type: "INT", Relop: "<=",
+					Requestable: "YES", Consumable: "YES", Default: "0", Urgency: 10},
+			},
+			ParallelEnvironments: map[string]core.ParallelEnvironmentConfig{
+				"mpi": {Name: "mpi", Slots: 100, AllocationRule: "$round_robin"},
+			},
+			ResourceQuotaSets: map[string]core.ResourceQuotaSetConfig{
+				"max_slots": {Name: "max_slots", Enabled: true,
+					Limits: []string{"users {*} to slots=10"}},
+			},
+			Projects: map[string]core.ProjectConfig{
+				"p1": {Name: "p1", FShare: 50},
+			},
+			UserSetLists: map[string]core.UserSetListConfig{
+				"staff": {Name: "staff", Type: "ACL", Entries: []string{"alice", "bob"}},
+			},
+			AdminHosts: []string{"master"},
+			Managers:   []string{"root", "sgeadmin"},
+		}
+	})
+
+	It("writes one qconf file per object in the backup layout", func() {
+		dir := GinkgoT().TempDir()
+		Expect(core.ExportQconfFiles(cc, dir)).To(Succeed())
+
+		for _, file := range []string{
+			"configurations/global", "configurations/node1", "schedconf",
+			"cqueues/all.q", "execution/node1", "hostgroups/@allhosts",
+			"centry/gpu", "pe/mpi", "resource_quotas/max_slots",
+			"projects/p1", "usersets/staff", "admin_hosts/master", "managers",
+		} {
+			Expect(filepath.Join(dir, file)).To(BeAnExistingFile())
+		}
+		queue, err := os.ReadFile(filepath.Join(dir, "cqueues", "all.q"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(queue)).To(MatchRegexp(`(?m)^qname\s+all.q$`))
+		Expect(string(queue)).To(MatchRegexp(`(?m)^hostlist\s+@allhosts$`))
+	})
+
+	It("reads the exported files back into the same configuration", func() {
+		dir := GinkgoT().TempDir()
+		Expect(core.ExportQconfFiles(cc, dir)).To(Succeed())
+
+		imported, err := core.ImportQconfFiles(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(imported.GlobalConfig.Mailer).To(Equal("/bin/mail"))
+		Expect(imported.SchedulerConfig.MaxReservation).To(Equal(10))
+		Expect(*imported.HostConfigurations["node1"].Mailer).To(Equal("/usr/bin/mail"))
+		Expect(imported.ClusterQueues["all.q"].Slots).To(Equal([]string{"1", "[node1=4]"}))
+		Expect(imported.ExecHosts["node1"].ComplexValues).To(HaveKeyWithValue("mem_free", "1G"))
+		Expect(imported.HostGroups["@allhosts"].Hosts).To(Equal([]string{"node1", "node2"}))
+		Expect(imported.ComplexEntries["gpu"]).To(Equal(cc.ComplexEntries["gpu"]))
+		Expect(imported.ParallelEnvironments["mpi"].AllocationRule).To(Equal("$round_robin"))
+		Expect(imported.ResourceQuotaSets["max_slots"].Limits).To(Equal([]string{"users {*} to slots=10"}))
+		Expect(imported.Projects["p1"].FShare).To(Equal(50))
+		Expect(imported.UserSetLists["staff"].Entries).To(Equal([]string{"alice", "bob"}))
+		Expect(imported.AdminHosts).To(Equal([]string{"master"}))
+		Expect(imported.Managers).To(Equal([]string{"root", "sgeadmin"}))
+
+		// a second round trip is stable
+		again := GinkgoT().TempDir()
+		Expect(core.ExportQconfFiles(imported, again)).To(Succeed())
+		reimported, err := core.ImportQconfFiles(again)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reimported).To(Equal(imported))
+	})
+
+	It("removes the files of objects which are gone when exporting again", func() {
+		dir := filepath.Join(GinkgoT().TempDir(), "backup")
+		Expect(core.ExportQconfFiles(cc, dir)).To(Succeed())
+		Expect(filepath.Join(dir, "pe", "mpi")).To(BeAnExistingFile())
+
+		delete(cc.ParallelEnvironments, "mpi")
+		cc.Managers = nil
+		Expect(core.ExportQconfFiles(cc, dir)).To(Succeed())
+		Expect(filepath.Join(dir, "pe", "mpi")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dir, "managers")).NotTo(BeAnExistingFile())
+		Expect(filepath.Join(dir, "cqueues", "all.q")).To(BeAnExistingFile())
+
+		imported, err := core.ImportQconfFiles(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(imported.ParallelEnvironments).To(BeEmpty())
+		Expect(imported.Managers).To(BeEmpty())
+	})
+
+	It("refuses a directory which holds no earlier export", func() {
+		dir := GinkgoT().TempDir()
+		Expect(os.MkdirAll(filepath.Join(dir, "users"), 0755)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(dir, "users", "alice"), []byte("name alice\n"), 0644)).To(Succeed())
+
+		Expect(core.ExportQconfFiles(cc, dir)).NotTo(Succeed())
+		Expect(filepath.Join(dir, "users", "alice")).To(BeAnExistingFile())
+		Expect(filepath.Join(dir, "cqueues")).NotTo(BeADirectory())
+	})
+
+	It("leaves an earlier export alone when the export fails", func() {
+		root := GinkgoT().TempDir()
+		dir := filepath.Join(root, "backup")
+		Expect(core.ExportQconfFiles(cc, dir)).To(Succeed())
+
+		delete(cc.ParallelEnvironments, "mpi")
+		cc.HostGroups["../escape"] = core.HostGroupConfig{Name: "../escape"}
+		Expect(core.ExportQconfFiles(cc, dir)).NotTo(Succeed())
+		Expect(filepath.Join(dir, "pe", "mpi")).To(BeAnExistingFile())
+		Expect(filepath.Join(dir, "cqueues", "all.q")).To(BeAnExistingFile())
+		entries, err := os.ReadDir(root)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(entries).To(HaveLen(1))
+	})
+
+	It("rejects object names which are no file names", func() {
+		cc.HostGroups["../escape"] = core.HostGroupConfig{Name: "../escape"}
+		err := core.ExportQconfFiles(cc, GinkgoT().TempDir())
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ImportQconfFiles", func() {
+
+	It("fails for a missing directory", func() {
+		_, err := core.ImportQconfFiles("/nonexistent")
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -34,15 +34,29 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
 )
 
 type CommandLineQConf struct {
 	config CommandLineQConfConfig
+	// ctx is the parent of every command's timeout context. It is nil
+	// unless the instance was derived through WithContext.
+	ctx context.Context
 	// shareMonRunner produces a single sge_share_mon snapshot. It is an
 	// instance field (not a package global) so parallel tests can each
 	// substitute their own stub without racing on shared state.
-	shareMonRunner func() (io.Reader, error)
+	shareMonRunner func(ctx context.Context) (io.Reader, error)
+	// limiter enforces Concurrency and RateLimit; nil when neither is set.
+	limiter *commandLimiter
+	// recorder, when set, receives the argv of every command instead of
+	// running it. ApplyPlan.ShellScript uses it to capture the commands
+	// and the object files they read.
+	recorder func(args []string) (string, error)
+	// objectFiles holds the paths of the object files written for the
+	// recorded commands, so the recorder can tell them from other file
+	// arguments.
+	objectFiles map[string]bool
 }
 
 type CommandLineQConfConfig struct {
@@ -57,6 +71,14 @@ type CommandLineQConfConfig struct {
 	// default of 120 seconds; qconf against a busy qmaster with
 	// large configs can legitimately take tens of seconds.
 	Timeout time.Duration
+	// Concurrency is the maximum number of qconf processes the instance
+	// runs at the same time. GetClusterConfiguration uses that many
+	// workers to read objects in parallel. Zero means no cap and a
+	// sequential GetClusterConfiguration.
+	Concurrency int
+	// RateLimit is the maximum number of qconf processes started per
+	// second. Zero means no limit.
+	RateLimit float64
 }
 
 // defaultCommandTimeout is applied when CommandLineQConfConfig.Timeout
@@ -72,15 +94,80 @@ func NewCommandLineQConf(config CommandLineQConfConfig) (*CommandLineQConf, erro
 	if config.Timeout == 0 {
 		config.Timeout = defaultCommandTimeout
 	}
-	c := &CommandLineQConf{config: config}
+	if config.Concurrency < 0 {
+		return nil, fmt.Errorf("concurrency must not be negative")
+	}
+	if config.RateLimit < 0 {
+		return nil, fmt.Errorf("rate limit must not be negative")
+	}
+	c := &CommandLineQConf{
+		config:  config,
+		limiter: newCommandLimiter(config.Concurrency, config.RateLimit),
+	}
 	c.shareMonRunner = c.defaultShareMonRunner
 	return c, nil
 }
 
+// WithContext returns a shallow copy of c whose commands run under ctx.
+// Cancelling ctx, or reaching its deadline, kills a running qconf and
+// makes all further commands of the copy fail without spawning a
+// process, so a long walk like GetClusterConfiguration stops early.
+// The configured Timeout still bounds each single invocation.
+//
+// The original instance is not modified. The copy is cheap; derive one
+// per request.
+func (c *CommandLineQConf) WithContext(ctx context.Context) *CommandLineQConf {
+	if ctx == nil {
+		panic("nil context")
+	}
+	c2 := *c
+	c2.ctx = ctx
+	return &c2
+}
+
+var contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+
+// WithContext returns v bound to ctx when v has a
+// WithContext(context.Context) method returning a single value of type
+// T, like CommandLineQConf and CachingQConf. Other values are returned
+// unchanged:
+//
+//	qc = WithContext(qc, r.Context())
+func WithContext[T any](v T, ctx context.Context) T {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() {
+		return v
+	}
+	m := rv.MethodByName("WithContext")
+	if !m.IsValid() {
+		return v
+	}
+	t := m.Type()
+	if t.NumIn() != 1 || t.In(0) != contextType || t.NumOut() != 1 {
+		return v
+	}
+	if bound, ok := m.Call([]reflect.Value{reflect.ValueOf(ctx)})[0].Interface().(T); ok {
+		return bound
+	}
+	return v
+}
+
+// Context returns the context set by WithContext, or
+// context.Background() when none was set.
+func (c *CommandLineQConf) Context() context.Context {
+	if c.ctx != nil {
+		return c.ctx
+	}
+	return context.Background()
+}
+
 // RunCommand executes the qconf command with the specified arguments.
 //
 // A hung binary (NFS stall, unresponsive qmaster) is killed after
 // config.Timeout so callers never block forever on a single invocation.
+// A failing command returns a *qerror.QconfError classifying the qconf
+// output, so callers can test for qerror.ErrNotExist and the other
+// sentinel errors with errors.Is.
 func (c *CommandLineQConf) RunCommand(args ...string) (string, error) {
 	// Layer 1 guard: reject control characters and invalid UTF-8 in any argv
 	// token before the process is spawned (or the dry-run line is printed).
@@ -90,11 +177,25 @@ func (c *CommandLineQConf) RunCommand(args ...string) (string, error) {
 	if err := validate.Enforce(validate.Args(args...)); err != nil {
 		return "", err
 	}
+	if c.recorder != nil {
+		return c.recorder(args)
+	}
 	if c.config.DryRun {
 		fmt.Printf("Executing: %s, %v", c.config.Executable, args)
 		return "", nil
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+	parent := c.Context()
+	if err := parent.Err(); err != nil {
+		return "", fmt.Errorf("failed to run command: %w", err)
+	}
+	if c.limiter != nil {
+		release, err := c.limiter.acquire(parent)
+		if err != nil {
+			return "", fmt.Errorf("failed to run command: %w", err)
+		}
+		defer release()
+	}
+	ctx, cancel := context.WithTimeout(parent, c.config.Timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, c.config.Executable, args...)
 	var out bytes.Buffer
@@ -117,11 +218,19 @@ func (c *CommandLineQConf) RunCommand(args ...string) (string, error) {
 	cmd.Env = append(cmd.Environ(), "SGE_SINGLE_LINE=true")
 	err := cmd.Run()
 	if c.config.DelayAfter != 0 {
-		<-time.After(c.config.DelayAfter)
+		select {
+		case <-time.After(c.config.DelayAfter):
+		case <-parent.Done():
+		}
 	}
 	if err != nil {
-		return out.String(), fmt.Errorf("failed to run command (%s): %v",
-			out.String(), err)
+		// A cancelled caller is reported as such (errors.Is works with
+		// context.Canceled / DeadlineExceeded) rather than as the
+		// "signal: killed" of the process.
+		if ctxErr := parent.Err(); ctxErr != nil {
+			return out.String(), qerror.New(out.String(), ctxErr)
+		}
+		return out.String(), qerror.New(out.String(), err)
 	}
 	return out.String(), err
 }
@@ -222,7 +331,7 @@ func (c *CommandLineQConf) versionFrom(path string) (ClusterSchedulerVersion, er
 	if timeout <= 0 {
 		timeout = defaultCommandTimeout
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(c.Context(), timeout)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, path, "-help")
@@ -243,6 +352,9 @@ func (c *CommandLineQConf) versionFrom(path string) (ClusterSchedulerVersion, er
 	// consulted -- only whether anything was produced.
 	_ = cmd.Run()
 
+	if err := c.Context().Err(); err != nil {
+		return ClusterSchedulerVersion{}, fmt.Errorf("%s -help: %w", path, err)
+	}
 	if ctx.Err() != nil {
 		return ClusterSchedulerVersion{}, fmt.Errorf(
 			"%s -help timed out after %s: %w", path, timeout, ctx.Err())
@@ -420,72 +532,73 @@ func ReadBootstrapFile(path string) (BootstrapFile, error) {
 	return bootstrapFile, nil
 }
 
+// GetClusterConfiguration reads the complete cluster configuration.
+//
+// Queues, exec hosts, projects, users, user set lists and resource quota
+// sets are read with bulk calls (see ShowClusterQueuesBulk), all other
+// objects with up to Concurrency parallel qconf calls. The result does
+// not depend on the concurrency. When single objects cannot
+// be read, all other objects are still returned together with a
+// *ClusterConfigError listing every failed object. Host configurations
+// and exec hosts which cannot be read are skipped with a warning, as the
+// host might be unreachable.
 func (c *CommandLineQConf) GetClusterConfiguration() (ClusterConfig, error) {
 	cc := ClusterConfig{}
+	var objErrs []*ObjectError
 
 	// general settings which defines the environment
 	var err error
 	cc.ClusterEnvironment, err = GetEnvironment()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read cluster environment: %v", err)
+		return cc, fmt.Errorf("failed to read cluster environment: %w", err)
 	}
 
 	cc.GlobalConfig, err = c.ShowGlobalConfiguration()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read global config: %v", err)
+		return cc, fmt.Errorf("failed to read global config: %w", err)
 	}
 
 	cc.SchedulerConfig, err = c.ShowSchedulerConfiguration()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read scheduler config: %v", err)
+		return cc, fmt.Errorf("failed to read scheduler config: %w", err)
 	}
 
 	hostConfigs, err := c.ShowHostConfigurations()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read host configs: %v", err)
+		return cc, fmt.Errorf("failed to read host configs: %w", err)
 	}
-	cc.HostConfigurations = make(map[string]HostConfiguration)
-	for _, host := range hostConfigs {
-		hc, err := c.ShowHostConfiguration(host)
-		if err != nil {
-			// host is might be unreachable
-			fmt.Printf("warning: host %s is unreachable\n", host)
-			continue
-		}
-		cc.HostConfigurations[host] = hc
+	var unreachable []*ObjectError
+	cc.HostConfigurations, unreachable = fetchObjects(c, "host configuration",
+		hostConfigs, c.ShowHostConfiguration)
+	if err := c.Context().Err(); err != nil {
+		return cc, err
+	}
+	for _, e := range unreachable {
+		fmt.Printf("warning: host %s is unreachable\n", e.Name)
 	}
 
 	projectNames, err := c.ShowProjects()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read projects: %v", err)
-	}
-	cc.Projects = make(map[string]ProjectConfig)
-	for _, projectName := range projectNames {
-		pc, err := c.ShowProject(projectName)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read project: %v", err)
-		}
-		cc.Projects[projectName] = pc
+		return cc, fmt.Errorf("failed to read projects: %w", err)
 	}
+	var errs []*ObjectError
+	cc.Projects, errs = fetchObjectsBulk(c, "project", projectNames,
+		c.ShowProjectsBulk, func(p ProjectConfig) string { return p.Name },
+		c.ShowProject)
+	objErrs = append(objErrs, errs...)
 
 	// Read Calendars
 	calendars, err := c.ShowCalendars()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read calendars: %v", err)
-	}
-	cc.Calendars = make(map[string]CalendarConfig)
-	for _, calendar := range calendars {
-		ccal, err := c.ShowCalendar(calendar)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read calendar: %v", err)
-		}
-		cc.Calendars[calendar] = ccal
+		return cc, fmt.Errorf("failed to read calendars: %w", err)
 	}
+	cc.Calendars, errs = fetchObjects(c, "calendar", calendars, c.ShowCalendar)
+	objErrs = append(objErrs, errs...)
 
 	// Read Complex Entries
 	complexes, err := c.ShowAllComplexes()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read complex entries: %v", err)
+		return cc, fmt.Errorf("failed to read complex entries: %w", err)
 	}
 	cc.ComplexEntries = make(map[string]ComplexEntryConfig)
 	for _, complex := range complexes {
@@ -495,137 +608,114 @@ func (c *CommandLineQConf) GetClusterConfiguration() (ClusterConfig, error) {
 	// Read Ckpt Interfaces
 	ckptInterfaces, err := c.ShowCkptInterfaces()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read ckpt interfaces: %v", err)
-	}
-	cc.CkptInterfaces = make(map[string]CkptInterfaceConfig, 0)
-	for _, ckptInterface := range ckptInterfaces {
-		ci, err := c.ShowCkptInterface(ckptInterface)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read ckpt interface: %v", err)
-		}
-		cc.CkptInterfaces[ckptInterface] = ci
+		return cc, fmt.Errorf("failed to read ckpt interfaces: %w", err)
 	}
+	cc.CkptInterfaces, errs = fetchObjects(c, "ckpt interface",
+		ckptInterfaces, c.ShowCkptInterface)
+	objErrs = append(objErrs, errs...)
 
 	// Read Exec Hosts
 	execHosts, err := c.ShowExecHosts()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read exec hosts: %v", err)
+		return cc, fmt.Errorf("failed to read exec hosts: %w", err)
 	}
-	cc.ExecHosts = make(map[string]HostExecConfig, 0)
-	for _, execHost := range execHosts {
-		eh, err := c.ShowExecHost(execHost)
-		if err != nil {
-			fmt.Printf("warning: exec host %s is unreachable\n", execHost)
-			continue
-		}
-		cc.ExecHosts[execHost] = eh
+	cc.ExecHosts, unreachable = fetchObjectsBulk(c, "exec host", execHosts,
+		c.ShowExecHostsBulk, func(h HostExecConfig) string { return h.Name },
+		c.ShowExecHost)
+	if err := c.Context().Err(); err != nil {
+		return cc, err
+	}
+	for _, e := range unreachable {
+		fmt.Printf("warning: exec host %s is unreachable\n", e.Name)
 	}
 
 	// Read Admin Hosts
 	adminHosts, err := c.ShowAdminHosts()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read admin hosts: %v", err)
+		return cc, fmt.Errorf("failed to read admin hosts: %w", err)
 	}
 	cc.AdminHosts = adminHosts
 
 	// Read Host Groups
 	hostGroups, err := c.ShowHostGroups()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read host groups: %v", err)
-	}
-	cc.HostGroups = make(map[string]HostGroupConfig, 0)
-	for _, hostGroup := range hostGroups {
-		hg, err := c.ShowHostGroup(hostGroup)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read host group: %v", err)
-		}
-		cc.HostGroups[hostGroup] = hg
+		return cc, fmt.Errorf("failed to read host groups: %w", err)
 	}
+	cc.HostGroups, errs = fetchObjects(c, "host group", hostGroups, c.ShowHostGroup)
+	objErrs = append(objErrs, errs...)
 
 	// Read Resource Quota Sets
 	resourceQuotaSets, err := c.ShowResourceQuotaSets()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read resource quota sets: %v", err)
-	}
-	cc.ResourceQuotaSets = make(map[string]ResourceQuotaSetConfig, 0)
-	for _, resourceQuotaSet := range resourceQuotaSets {
-		rqs, err := c.ShowResourceQuotaSet(resourceQuotaSet)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read resource quota set: %v", err)
-		}
-		cc.ResourceQuotaSets[resourceQuotaSet] = rqs
+		return cc, fmt.Errorf("failed to read resource quota sets: %w", err)
 	}
+	cc.ResourceQuotaSets, errs = fetchObjectsBulk(c, "resource quota set",
+		resourceQuotaSets, c.ShowResourceQuotaSetsBulk,
+		func(r ResourceQuotaSetConfig) string { return r.Name },
+		c.ShowResourceQuotaSet)
+	objErrs = append(objErrs, errs...)
 
 	// Read Managers
 	managers, err := c.ShowManagers()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read managers: %v", err)
+		return cc, fmt.Errorf("failed to read managers: %w", err)
 	}
 	cc.Managers = managers
 
 	// Read Operators
 	operators, err := c.ShowOperators()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read operators: %v", err)
+		return cc, fmt.Errorf("failed to read operators: %w", err)
 	}
 	cc.Operators = operators
 
 	// Read Parallel Environments
 	parallelEnvironments, err := c.ShowParallelEnvironments()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read parallel environments: %v", err)
-	}
-	cc.ParallelEnvironments = make(map[string]ParallelEnvironmentConfig, 0)
-	for _, parallelEnvironment := range parallelEnvironments {
-		pe, err := c.ShowParallelEnvironment(parallelEnvironment)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read parallel environment: %v", err)
-		}
-		cc.ParallelEnvironments[parallelEnvironment] = pe
+		return cc, fmt.Errorf("failed to read parallel environments: %w", err)
 	}
+	cc.ParallelEnvironments, errs = fetchObjects(c, "parallel environment",
+		parallelEnvironments, c.ShowParallelEnvironment)
+	objErrs = append(objErrs, errs...)
 
 	// Read Users
 	users, err := c.ShowUsers()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read users: %v", err)
-	}
-	cc.Users = make(map[string]UserConfig, 0)
-	for _, user := range users {
-		u, err := c.ShowUser(user)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read user: %v", err)
-		}
-		cc.Users[user] = u
+		return cc, fmt.Errorf("failed to read users: %w", err)
 	}
+	cc.Users, errs = fetchObjectsBulk(c, "user", users,
+		c.ShowUsersBulk, func(u UserConfig) string { return u.Name },
+		c.ShowUser)
+	objErrs = append(objErrs, errs...)
 
 	// Read Cluster Queues
 	clusterQueues, err := c.ShowClusterQueues()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read cluster queues: %v", err)
-	}
-	cc.ClusterQueues = make(map[string]ClusterQueueConfig, 0)
-	for _, clusterQueue := range clusterQueues {
-		cq, err := c.ShowClusterQueue(clusterQueue)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read cluster queue: %v", err)
-		}
-		cc.ClusterQueues[clusterQueue] = cq
+		return cc, fmt.Errorf("failed to read cluster queues: %w", err)
 	}
+	cc.ClusterQueues, errs = fetchObjectsBulk(c, "cluster queue",
+		clusterQueues, c.ShowClusterQueuesBulk,
+		func(q ClusterQueueConfig) string { return q.Name },
+		c.ShowClusterQueue)
+	objErrs = append(objErrs, errs...)
 
 	// Read User Set Lists
 	userSetLists, err := c.ShowUserSetLists()
 	if err != nil {
-		return cc, fmt.Errorf("failed to read user set lists: %v", err)
-	}
-	cc.UserSetLists = make(map[string]UserSetListConfig, 0)
-	for _, userSetList := range userSetLists {
-		usl, err := c.ShowUserSetList(userSetList)
-		if err != nil {
-			return cc, fmt.Errorf("failed to read user set list: %v", err)
-		}
-		cc.UserSetLists[userSetList] = usl
+		return cc, fmt.Errorf("failed to read user set lists: %w", err)
 	}
+	cc.UserSetLists, errs = fetchObjectsBulk(c, "user set list",
+		userSetLists, c.ShowUserSetListsBulk,
+		func(u UserSetListConfig) string { return u.Name },
+		c.ShowUserSetList)
+	objErrs = append(objErrs, errs...)
 
+	if err := c.Context().Err(); err != nil {
+		return cc, err
+	}
+	if len(objErrs) > 0 {
+		return cc, &ClusterConfigError{Errors: objErrs}
+	}
 	return cc, nil
 }
 
@@ -649,6 +739,7 @@ func (c *CommandLineQConf) AddCalendar(cfg CalendarConfig) error {
 	if err != nil {
 		return err
 	}
+	c.recordObjectFile(file.Name())
 	defer os.Remove(file.Name())
 
 	err = writeCalendar(file, cfg)
@@ -659,7 +750,7 @@ func (c *CommandLineQConf) AddCalendar(cfg CalendarConfig) error {
 	file.Close()
 	_, err = c.RunCommand("-Acal", file.Name())
 	if err != nil {
-		return fmt.Errorf("failed to add calendar: %v", err)
+		return fmt.Errorf("failed to add calendar: %w", err)
 	}
 	return nil
 }
@@ -875,7 +966,7 @@ func (c *CommandLineQConf) AddComplexEntry(e ComplexEntryConfig) error {
 		return fmt.Errorf("complex does not have a type")
 	}
 	SetDefaultComplexEntryValues(&e)
-	file, err := CreateTempDirWithFileName(e.Name)
+	file, err := c.createObjectFile(e.Name)
 	if err != nil {
 		return err
 	}
@@ -923,8 +1014,7 @@ func (c *CommandLineQConf) AddComplexEntry(e ComplexEntryConfig) error {
 			// ignore exit code 1
 			return nil
 		}
-		return fmt.Errorf("failed to add complex entry (%s): %v",
-			out, err)
+		return fmt.Errorf("failed to add complex entry: %w", err)
 	}
 	return nil
 }
@@ -941,8 +1031,20 @@ func (c *CommandLineQConf) ShowComplexEntry(entryName string) (ComplexEntryConfi
 	if err != nil {
 		return ComplexEntryConfig{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := ComplexEntryConfig{Name: entryName}
+	cfg, err := ParseComplexEntryConfigFromLines(strings.Split(out, "\n"))
+	if err != nil {
+		return ComplexEntryConfig{}, err
+	}
+	if cfg.Name == "" {
+		cfg.Name = entryName
+	}
+	return cfg, nil
+}
+
+// ParseComplexEntryConfigFromLines parses the output lines of "qconf -sce"
+// into a ComplexEntryConfig.
+func ParseComplexEntryConfigFromLines(lines []string) (ComplexEntryConfig, error) {
+	cfg := ComplexEntryConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -1036,7 +1138,7 @@ func (c *CommandLineQConf) ShowAllComplexes() ([]ComplexEntryConfig, error) {
 // AddCkptInterface adds a new checkpointing interface.
 func (c *CommandLineQConf) AddCkptInterface(cfg CkptInterfaceConfig) error {
 	// Create a temporary file with the checkpointing interface configuration
-	file, err := CreateTempDirWithFileName(cfg.Name)
+	file, err := c.createObjectFile(cfg.Name)
 	if err != nil {
 		return err
 	}
@@ -1085,7 +1187,7 @@ func (c *CommandLineQConf) AddCkptInterface(cfg CkptInterfaceConfig) error {
 	// Execute the qconf command
 	_, err = c.RunCommand("-Ackpt", file.Name())
 	if err != nil {
-		return fmt.Errorf("failed to add checkpointing interface: %v", err)
+		return fmt.Errorf("failed to add checkpointing interface: %w", err)
 	}
 	return nil
 }
@@ -1102,8 +1204,17 @@ func (c *CommandLineQConf) ShowCkptInterface(interfaceName string) (CkptInterfac
 	if err != nil {
 		return CkptInterfaceConfig{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := CkptInterfaceConfig{Name: interfaceName}
+	cfg := ParseCkptInterfaceConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = interfaceName
+	}
+	return cfg, nil
+}
+
+// ParseCkptInterfaceConfigFromLines parses the output lines of "qconf -sckpt"
+// into a CkptInterfaceConfig.
+func ParseCkptInterfaceConfigFromLines(lines []string) CkptInterfaceConfig {
+	cfg := CkptInterfaceConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -1132,7 +1243,7 @@ func (c *CommandLineQConf) ShowCkptInterface(interfaceName string) (CkptInterfac
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 // ShowCkptInterfaces shows all checkpointing interfaces.
@@ -1155,7 +1266,7 @@ func (c *CommandLineQConf) AddHostConfiguration(config HostConfiguration) error
 		return fmt.Errorf("hostname not set in host configuration")
 	}
 
-	file, err := CreateTempDirWithFileName(config.Name)
+	file, err := c.createObjectFile(config.Name)
 	if err != nil {
 		return err
 	}
@@ -1303,10 +1414,9 @@ func (c *CommandLineQConf) AddHostConfiguration(config HostConfiguration) error
 
 	file.Close()
 
-	out, err := c.RunCommand("-Aconf", file.Name())
+	_, err = c.RunCommand("-Aconf", file.Name())
 	if err != nil {
-		return fmt.Errorf("failed to add host configuration (%s): %v",
-			out, err)
+		return fmt.Errorf("failed to add host configuration: %w", err)
 	}
 	return nil
 }
@@ -1323,10 +1433,15 @@ func (c *CommandLineQConf) ShowHostConfiguration(hostName string) (HostConfigura
 	if err != nil {
 		return HostConfiguration{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := HostConfiguration{
-		Name: hostName,
-	}
+	cfg := ParseHostConfigurationFromLines(strings.Split(out, "\n"))
+	cfg.Name = hostName
+	return cfg, nil
+}
+
+// ParseHostConfigurationFromLines parses the output lines of
+// "qconf -sconf <host>" into a HostConfiguration without its Name.
+func ParseHostConfigurationFromLines(lines []string) HostConfiguration {
+	cfg := HostConfiguration{}
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		if line == "" || strings.HasPrefix(line, "#") {
@@ -1396,7 +1511,7 @@ func (c *CommandLineQConf) ShowHostConfiguration(hostName string) (HostConfigura
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 // ParseGlobalConfigFromLines parses the output lines of "qconf -sconf global"
@@ -1542,7 +1657,7 @@ func (c *CommandLineQConf) ShowHostConfigurations() ([]string, error) {
 
 // AddExecHost adds a new execution host.
 func (c *CommandLineQConf) AddExecHost(hostExecConfig HostExecConfig) error {
-	file, err := CreateTempDirWithFileName(hostExecConfig.Name)
+	file, err := c.createObjectFile(hostExecConfig.Name)
 	if err != nil {
 		return err
 	}
@@ -1595,9 +1710,9 @@ func (c *CommandLineQConf) AddExecHost(hostExecConfig HostExecConfig) error {
 	}
 	file.Close()
 
-	out, err := c.RunCommand("-Ae", file.Name())
+	_, err = c.RunCommand("-Ae", file.Name())
 	if err != nil {
-		return fmt.Errorf("failed to add exechost (%s): %v", out, err)
+		return fmt.Errorf("failed to add exechost: %w", err)
 	}
 	return nil
 }
@@ -1796,7 +1911,7 @@ func (c *CommandLineQConf) AddAdminHost(hosts []string) error {
 	hostList := strings.Join(hosts, ",")
 	_, err := c.RunCommand("-ah", hostList)
 	if err != nil {
-		return fmt.Errorf("failed to add adminhost: %v", err)
+		return fmt.Errorf("failed to add adminhost: %w", err)
 	}
 	return nil
 }
@@ -1812,7 +1927,7 @@ func (c *CommandLineQConf) DeleteAdminHost(hosts []string) error {
 	hostList := strings.Join(hosts, ",")
 	_, err := c.RunCommand("-dh", hostList)
 	if err != nil {
-		return fmt.Errorf("failed to delete adminhost: %v", err)
+		return fmt.Errorf("failed to delete adminhost: %w", err)
 	}
 	return nil
 }
@@ -1821,14 +1936,14 @@ func (c *CommandLineQConf) DeleteAdminHost(hosts []string) error {
 func (c *CommandLineQConf) ShowAdminHosts() ([]string, error) {
 	output, err := c.RunCommand("-sh")
 	if err != nil {
-		return nil, fmt.Errorf("failed to show adminhosts: %v", err)
+		return nil, fmt.Errorf("failed to show adminhosts: %w", err)
 	}
 	return splitWithoutEmptyLines(output, "\n"), nil
 }
 
 // AddHostGroup adds a new host group.
 func (c *CommandLineQConf) AddHostGroup(hostGroup HostGroupConfig) error {
-	file, err := CreateTempDirWithFileName(hostGroup.Name)
+	file, err := c.createObjectFile(hostGroup.Name)
 	if err != nil {
 		return err
 	}
@@ -1867,8 +1982,17 @@ func (c *CommandLineQConf) ShowHostGroup(groupName string) (HostGroupConfig, err
 	if err != nil {
 		return HostGroupConfig{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := HostGroupConfig{Name: groupName}
+	cfg := ParseHostGroupConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = groupName
+	}
+	return cfg, nil
+}
+
+// ParseHostGroupConfigFromLines parses the output lines of "qconf -shgrp"
+// into a HostGroupConfig.
+func ParseHostGroupConfigFromLines(lines []string) HostGroupConfig {
+	cfg := HostGroupConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -1884,7 +2008,7 @@ func (c *CommandLineQConf) ShowHostGroup(groupName string) (HostGroupConfig, err
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 // ShowHostGroupResolved shows all hosts in a host group and all sub-groups.
@@ -1915,7 +2039,7 @@ func (c *CommandLineQConf) ShowHostGroups() ([]string, error) {
 // AddResourceQuotaSet adds a new resource quota set.
 func (c *CommandLineQConf) AddResourceQuotaSet(rqs ResourceQuotaSetConfig) error {
 	SetResourceQuotaSetDefaults(&rqs)
-	file, err := CreateTempDirWithFileName(rqs.Name)
+	file, err := c.createObjectFile(rqs.Name)
 	if err != nil {
 		return err
 	}
@@ -1964,8 +2088,17 @@ func (c *CommandLineQConf) ShowResourceQuotaSet(rqsList string) (ResourceQuotaSe
 	if err != nil {
 		return ResourceQuotaSetConfig{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := ResourceQuotaSetConfig{Name: rqsList}
+	cfg := ParseResourceQuotaSetConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = rqsList
+	}
+	return cfg, nil
+}
+
+// ParseResourceQuotaSetConfigFromLines parses a single rule set of
+// qconf -srqs output into a ResourceQuotaSetConfig.
+func ParseResourceQuotaSetConfigFromLines(lines []string) ResourceQuotaSetConfig {
+	cfg := ResourceQuotaSetConfig{}
 	for i, line := range lines {
 		line = strings.TrimSpace(line)
 		fields := strings.Fields(line)
@@ -1987,7 +2120,7 @@ func (c *CommandLineQConf) ShowResourceQuotaSet(rqsList string) (ResourceQuotaSe
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 // ShowResourceQuotaSets shows all resource quota sets.
@@ -2101,7 +2234,7 @@ func MakeBoolCfg(v bool) string {
 // AddParallelEnvironment adds a new parallel environment.
 func (c *CommandLineQConf) AddParallelEnvironment(pe ParallelEnvironmentConfig) error {
 	SetDefaultParallelEnvironmentValues(&pe)
-	file, err := CreateTempDirWithFileName(pe.Name)
+	file, err := c.createObjectFile(pe.Name)
 	if err != nil {
 		return err
 	}
@@ -2291,7 +2424,7 @@ func SetDefaultProjectValues(project *ProjectConfig) {
 // AddProject adds a project.
 func (c *CommandLineQConf) AddProject(project ProjectConfig) error {
 	SetDefaultProjectValues(&project)
-	file, err := CreateTempDirWithFileName(project.Name)
+	file, err := c.createObjectFile(project.Name)
 	if err != nil {
 		return err
 	}
@@ -2341,8 +2474,16 @@ func (c *CommandLineQConf) ShowProject(projectName string) (ProjectConfig, error
 	if err != nil {
 		return ProjectConfig{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := ProjectConfig{Name: projectName}
+	cfg := ParseProjectConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = projectName
+	}
+	return cfg, nil
+}
+
+// ParseProjectConfigFromLines parses qconf -sprj output into a ProjectConfig.
+func ParseProjectConfigFromLines(lines []string) ProjectConfig {
+	cfg := ProjectConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -2363,7 +2504,7 @@ func (c *CommandLineQConf) ShowProject(projectName string) (ProjectConfig, error
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 // ShowProjects shows all projects.
@@ -2544,7 +2685,7 @@ func SetDefaultQueueValues(queue *ClusterQueueConfig) {
 func (c *CommandLineQConf) AddClusterQueue(queue ClusterQueueConfig) error {
 	SetDefaultQueueValues(&queue)
 
-	file, err := CreateTempDirWithFileName(queue.Name)
+	file, err := c.createObjectFile(queue.Name)
 	if err != nil {
 		return err
 	}
@@ -2818,12 +2959,22 @@ func (c *CommandLineQConf) ShowClusterQueue(queueName string) (ClusterQueueConfi
 	if err != nil {
 		return ClusterQueueConfig{}, err
 	}
+	cfg := ParseClusterQueueConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = queueName
+	}
+	return cfg, nil
+}
+
+// ParseClusterQueueConfigFromLines parses qconf -sq output into a
+// ClusterQueueConfig with all unset attributes defaulted.
+func ParseClusterQueueConfigFromLines(lines []string) ClusterQueueConfig {
 	// The *WithOverrides helpers below read one line at a time, so the
 	// input must be free of backslash continuations. RunCommand's
 	// SGE_SINGLE_LINE=true already guarantees that for qconf output;
 	// normalising keeps it true if this ever parses a file.
-	lines := normalizeConfigLines(strings.Split(out, "\n"))
-	cfg := ClusterQueueConfig{Name: queueName}
+	lines = normalizeConfigLines(lines)
+	cfg := ClusterQueueConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -2939,7 +3090,7 @@ func (c *CommandLineQConf) ShowClusterQueue(queueName string) (ClusterQueueConfi
 	// Make sure all nil fields are properly converted to ["NONE"]
 	SetDefaultQueueValues(&cfg)
 
-	return cfg, nil
+	return cfg
 }
 
 // ShowClusterQueues shows all cluster queues.
@@ -2987,7 +3138,7 @@ func (c *CommandLineQConf) ShowSubmitHosts() ([]string, error) {
 			strings.Contains(output, "defined") {
 			return []string{}, nil
 		}
-		return nil, fmt.Errorf("error showing submit hosts: %v", err)
+		return nil, fmt.Errorf("error showing submit hosts: %w", err)
 	}
 	return splitWithoutEmptyLines(output, "\n"), nil
 }
@@ -3028,8 +3179,17 @@ func (c *CommandLineQConf) ShowUserSetList(listnameList string) (UserSetListConf
 	if err != nil {
 		return UserSetListConfig{}, err
 	}
-	lines := strings.Split(out, "\n")
-	cfg := UserSetListConfig{Name: listnameList}
+	cfg := ParseUserSetListConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = listnameList
+	}
+	return cfg, nil
+}
+
+// ParseUserSetListConfigFromLines parses qconf -su output into a
+// UserSetListConfig.
+func ParseUserSetListConfigFromLines(lines []string) UserSetListConfig {
+	cfg := UserSetListConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -3050,7 +3210,7 @@ func (c *CommandLineQConf) ShowUserSetList(listnameList string) (UserSetListConf
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 func SetDefaultUserSetListConfig(u *UserSetListConfig) {
@@ -3062,7 +3222,7 @@ func SetDefaultUserSetListConfig(u *UserSetListConfig) {
 func (c *CommandLineQConf) AddUserSetList(userSetListName string, u UserSetListConfig) error {
 	SetDefaultUserSetListConfig(&u)
 
-	file, err := CreateTempDirWithFileName(userSetListName)
+	file, err := c.createObjectFile(userSetListName)
 	if err != nil {
 		return err
 	}
@@ -3100,7 +3260,7 @@ func (c *CommandLineQConf) AddUser(userConfig UserConfig) error {
 	if userConfig.DefaultProject == "" {
 		userConfig.DefaultProject = "NONE"
 	}
-	file, err := CreateTempDirWithFileName(userConfig.Name)
+	file, err := c.createObjectFile(userConfig.Name)
 	if err != nil {
 		return err
 	}
@@ -3153,11 +3313,19 @@ func (c *CommandLineQConf) ShowUser(userName string) (UserConfig, error) {
 	// CS-464 - Inconsistent exist code. Is 0 but should be 1
 	// if a user is not defined.
 	if strings.Contains(out, "is not known as user") {
-		return UserConfig{}, fmt.Errorf("user %s is not defined", userName)
+		return UserConfig{}, fmt.Errorf("user %s is not defined: %w",
+			userName, qerror.ErrNotExist)
 	}
+	cfg := ParseUserConfigFromLines(strings.Split(out, "\n"))
+	if cfg.Name == "" {
+		cfg.Name = userName
+	}
+	return cfg, nil
+}
 
-	lines := strings.Split(out, "\n")
-	cfg := UserConfig{Name: userName}
+// ParseUserConfigFromLines parses qconf -suser output into a UserConfig.
+func ParseUserConfigFromLines(lines []string) UserConfig {
+	cfg := UserConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
 		if len(fields) < 2 {
@@ -3178,7 +3346,7 @@ func (c *CommandLineQConf) ShowUser(userName string) (UserConfig, error) {
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return cfg, nil
+	return cfg
 }
 
 func (c *CommandLineQConf) ShowUsers() ([]string, error) {
@@ -3352,6 +3520,20 @@ func (c *CommandLineQConf) AddAttribute(objName, attrName, val, objIDList string
 	return checkAttrModification(out)
 }
 
+// ReplaceAttribute replaces the complete value of a list-valued attribute
+// of an object with val, like qconf -rattr. With a queue instance like
+// "all.q@node1" the host specific value is replaced.
+func (c *CommandLineQConf) ReplaceAttribute(objName, attrName, val, objIDList string) error {
+	if err := validate.Enforce(validateAttrArgs(objName, attrName, val, objIDList)); err != nil {
+		return err
+	}
+	out, err := c.RunCommand("-rattr", objName, attrName, val, objIDList)
+	if err != nil {
+		return err
+	}
+	return checkAttrModification(out)
+}
+
 // ModifyAllComplexes modifies complex attributes.
 func (c *CommandLineQConf) ModifyAllComplexes(centries []ComplexEntryConfig) error {
 	if centries == nil {
@@ -3362,6 +3544,7 @@ func (c *CommandLineQConf) ModifyAllComplexes(centries []ComplexEntryConfig) err
 	if err != nil {
 		return err
 	}
+	c.recordObjectFile(file.Name())
 	defer file.Name()
 
 	for _, resource := range centries {
@@ -3388,13 +3571,26 @@ func (c *CommandLineQConf) ModifyComplexEntry(complexName string, cfg ComplexEnt
 	}
 	SetDefaultComplexEntryValues(&cfg)
 
-	file, err := CreateTempDirWithFileName(complexName)
+	file, err := c.createObjectFile(complexName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("name           %s\n", cfg.Name))
+	err = writeComplexEntry(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mce", file.Name())
+	return err
+}
+
+// writeComplexEntry writes the complex entry file read by qconf.
+func writeComplexEntry(file *os.File, cfg ComplexEntryConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("name           %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -3429,15 +3625,12 @@ func (c *CommandLineQConf) ModifyComplexEntry(complexName string, cfg ComplexEnt
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Mce", file.Name())
-	return err
+	return nil
 }
 
 // ModifyCalendar modifies a calendar.
 func (c *CommandLineQConf) ModifyCalendar(calendarName string, cfg CalendarConfig) error {
-	file, err := CreateTempDirWithFileName(calendarName)
+	file, err := c.createObjectFile(calendarName)
 	if err != nil {
 		return err
 	}
@@ -3458,13 +3651,27 @@ func (c *CommandLineQConf) ModifyCalendar(calendarName string, cfg CalendarConfi
 
 // ModifyCkptInterface modifies a checkpointing interface.
 func (c *CommandLineQConf) ModifyCkptInterface(ckptName string, cfg CkptInterfaceConfig) error {
-	file, err := CreateTempDirWithFileName(ckptName)
+	file, err := c.createObjectFile(ckptName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("ckpt_name           %s\n", ckptName))
+	cfg.Name = ckptName
+	err = writeCkptInterface(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mckpt", file.Name())
+	return err
+}
+
+// writeCkptInterface writes the checkpointing interface file read by qconf.
+func writeCkptInterface(file *os.File, cfg CkptInterfaceConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("ckpt_name           %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -3503,152 +3710,163 @@ func (c *CommandLineQConf) ModifyCkptInterface(ckptName string, cfg CkptInterfac
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Mckpt", file.Name())
-	return err
+	return nil
 }
 
 // ModifyHostConfiguration modifies a host configuration.
 func (c *CommandLineQConf) ModifyHostConfiguration(configName string, cfg HostConfiguration) error {
-	file, err := CreateTempDirWithFileName(configName)
+	file, err := c.createObjectFile(configName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
+	err = writeHostConfiguration(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mconf", file.Name())
+	return err
+}
+
+// writeHostConfiguration writes the host configuration file read by
+// qconf. The file carries no host name; qconf takes it from the file name.
+func writeHostConfiguration(file *os.File, cfg HostConfiguration) error {
 	// Write pointer fields if they're not nil
 	if cfg.ExecdSpoolDir != nil {
-		if _, err = file.WriteString(fmt.Sprintf("execd_spool_dir %s\n", *cfg.ExecdSpoolDir)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("execd_spool_dir %s\n", *cfg.ExecdSpoolDir)); err != nil {
 			return err
 		}
 	}
 	if cfg.Mailer != nil {
-		if _, err = file.WriteString(fmt.Sprintf("mailer %s\n", *cfg.Mailer)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("mailer %s\n", *cfg.Mailer)); err != nil {
 			return err
 		}
 	}
 	if cfg.Xterm != nil {
-		if _, err = file.WriteString(fmt.Sprintf("xterm %s\n", *cfg.Xterm)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("xterm %s\n", *cfg.Xterm)); err != nil {
 			return err
 		}
 	}
 
 	// Handle slice fields
 	for _, sensor := range cfg.LoadSensors {
-		if _, err = file.WriteString(fmt.Sprintf("load_sensor %s\n", sensor)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("load_sensor %s\n", sensor)); err != nil {
 			return err
 		}
 	}
 
 	if cfg.Prolog != nil {
-		if _, err = file.WriteString(fmt.Sprintf("prolog %s\n", *cfg.Prolog)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("prolog %s\n", *cfg.Prolog)); err != nil {
 			return err
 		}
 	}
 	if cfg.Epilog != nil {
-		if _, err = file.WriteString(fmt.Sprintf("epilog %s\n", *cfg.Epilog)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("epilog %s\n", *cfg.Epilog)); err != nil {
 			return err
 		}
 	}
 	if cfg.ShellStartMode != nil {
-		if _, err = file.WriteString(fmt.Sprintf("shell_start_mode %s\n", *cfg.ShellStartMode)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("shell_start_mode %s\n", *cfg.ShellStartMode)); err != nil {
 			return err
 		}
 	}
 
 	for _, shell := range cfg.LoginShells {
-		if _, err = file.WriteString(fmt.Sprintf("login_shells %s\n", shell)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("login_shells %s\n", shell)); err != nil {
 			return err
 		}
 	}
 
 	if cfg.LoadReportTime != nil {
-		if _, err = file.WriteString(fmt.Sprintf("load_report_time %s\n", *cfg.LoadReportTime)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("load_report_time %s\n", *cfg.LoadReportTime)); err != nil {
 			return err
 		}
 	}
 	if cfg.SetTokenCmd != nil {
-		if _, err = file.WriteString(fmt.Sprintf("set_token_cmd %s\n", *cfg.SetTokenCmd)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("set_token_cmd %s\n", *cfg.SetTokenCmd)); err != nil {
 			return err
 		}
 	}
 	if cfg.PagCmd != nil {
-		if _, err = file.WriteString(fmt.Sprintf("pag_cmd %s\n", *cfg.PagCmd)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("pag_cmd %s\n", *cfg.PagCmd)); err != nil {
 			return err
 		}
 	}
 	if cfg.TokenExtendTime != nil {
-		if _, err = file.WriteString(fmt.Sprintf("token_extend_time %s\n", *cfg.TokenExtendTime)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("token_extend_time %s\n", *cfg.TokenExtendTime)); err != nil {
 			return err
 		}
 	}
 	if cfg.ShepherdCmd != nil {
-		if _, err = file.WriteString(fmt.Sprintf("shepherd_cmd %s\n", *cfg.ShepherdCmd)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("shepherd_cmd %s\n", *cfg.ShepherdCmd)); err != nil {
 			return err
 		}
 	}
 
 	for _, param := range cfg.ExecdParams {
-		if _, err = file.WriteString(fmt.Sprintf("execd_params %s\n", param)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("execd_params %s\n", param)); err != nil {
 			return err
 		}
 	}
 
 	for _, param := range cfg.ReportingParams {
-		if _, err = file.WriteString(fmt.Sprintf("reporting_params %s\n", param)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("reporting_params %s\n", param)); err != nil {
 			return err
 		}
 	}
 
 	for _, gid := range cfg.GidRange {
-		if _, err = file.WriteString(fmt.Sprintf("gid_range %s\n", gid)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("gid_range %s\n", gid)); err != nil {
 			return err
 		}
 	}
 
 	if cfg.QloginDaemon != nil {
-		if _, err = file.WriteString(fmt.Sprintf("qlogin_daemon %s\n", *cfg.QloginDaemon)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("qlogin_daemon %s\n", *cfg.QloginDaemon)); err != nil {
 			return err
 		}
 	}
 	if cfg.QloginCommand != nil {
-		if _, err = file.WriteString(fmt.Sprintf("qlogin_command %s\n", *cfg.QloginCommand)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("qlogin_command %s\n", *cfg.QloginCommand)); err != nil {
 			return err
 		}
 	}
 	if cfg.RshDaemon != nil {
-		if _, err = file.WriteString(fmt.Sprintf("rsh_daemon %s\n", *cfg.RshDaemon)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("rsh_daemon %s\n", *cfg.RshDaemon)); err != nil {
 			return err
 		}
 	}
 	if cfg.RshCommand != nil {
-		if _, err = file.WriteString(fmt.Sprintf("rsh_command %s\n", *cfg.RshCommand)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("rsh_command %s\n", *cfg.RshCommand)); err != nil {
 			return err
 		}
 	}
 	if cfg.RloginDaemon != nil {
-		if _, err = file.WriteString(fmt.Sprintf("rlogin_daemon %s\n", *cfg.RloginDaemon)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("rlogin_daemon %s\n", *cfg.RloginDaemon)); err != nil {
 			return err
 		}
 	}
 	if cfg.RloginCommand != nil {
-		if _, err = file.WriteString(fmt.Sprintf("rlogin_command %s\n", *cfg.RloginCommand)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("rlogin_command %s\n", *cfg.RloginCommand)); err != nil {
 			return err
 		}
 	}
 	if cfg.RescheduleUnknown != nil {
-		if _, err = file.WriteString(fmt.Sprintf("reschedule_unknown %s\n", *cfg.RescheduleUnknown)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("reschedule_unknown %s\n", *cfg.RescheduleUnknown)); err != nil {
 			return err
 		}
 	}
 	if cfg.LibJvmPath != nil {
-		if _, err = file.WriteString(fmt.Sprintf("libjvm_path %s\n", *cfg.LibJvmPath)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("libjvm_path %s\n", *cfg.LibJvmPath)); err != nil {
 			return err
 		}
 	}
 	if cfg.AdditionalJvmArgs != nil {
-		if _, err = file.WriteString(fmt.Sprintf("additional_jvm_args %s\n", *cfg.AdditionalJvmArgs)); err != nil {
+		if _, err := file.WriteString(fmt.Sprintf("additional_jvm_args %s\n", *cfg.AdditionalJvmArgs)); err != nil {
 			return err
 		}
 	}
@@ -3656,11 +3874,7 @@ func (c *CommandLineQConf) ModifyHostConfiguration(configName string, cfg HostCo
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-
-	file.Close()
-
-	_, err = c.RunCommand("-Mconf", file.Name())
-	return err
+	return nil
 }
 
 // CreateTempDirWithFileName creates a temporary directory with a file of the given name.
@@ -3687,6 +3901,29 @@ func CreateTempDirWithFileName(name string) (*os.File, error) {
 	return file, nil
 }
 
+// createObjectFile creates the file an object is written to for a qconf
+// command, like CreateTempDirWithFileName.
+func (c *CommandLineQConf) createObjectFile(name string) (*os.File, error) {
+	file, err := CreateTempDirWithFileName(name)
+	if err != nil {
+		return nil, err
+	}
+	c.recordObjectFile(file.Name())
+	return file, nil
+}
+
+// recordObjectFile remembers path as an object file read by the next
+// recorded command.
+func (c *CommandLineQConf) recordObjectFile(path string) {
+	if c.recorder == nil {
+		return
+	}
+	if c.objectFiles == nil {
+		c.objectFiles = make(map[string]bool)
+	}
+	c.objectFiles[path] = true
+}
+
 // writeGlobalConfig emits the typed fields of cfg as qconf attribute
 // lines followed by its ExtraFields. Split out of ModifyGlobalConfig so
 // the emitted file can be asserted without a cluster; the caller keeps
@@ -3744,7 +3981,7 @@ func writeGlobalConfig(w io.Writer, cfg GlobalConfig) error {
 
 // ModifyGlobalConfig modifies the global configuration.
 func (c *CommandLineQConf) ModifyGlobalConfig(cfg GlobalConfig) error {
-	file, err := CreateTempDirWithFileName("global")
+	file, err := c.createObjectFile("global")
 	if err != nil {
 		return err
 	}
@@ -3766,13 +4003,27 @@ func SetDefaultExecHostConfig(cfg *HostExecConfig) {
 // ModifyExecHost modifies an execution host.
 func (c *CommandLineQConf) ModifyExecHost(execHostName string, cfg HostExecConfig) error {
 	SetDefaultExecHostConfig(&cfg)
-	file, err := CreateTempDirWithFileName(execHostName)
+	file, err := c.createObjectFile(execHostName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("hostname         %s\n", execHostName))
+	cfg.Name = execHostName
+	err = writeExecHost(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Me", file.Name())
+	return err
+}
+
+// writeExecHost writes the execution host file read by qconf.
+func writeExecHost(file *os.File, cfg HostExecConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("hostname         %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -3814,21 +4065,32 @@ func (c *CommandLineQConf) ModifyExecHost(execHostName string, cfg HostExecConfi
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Me", file.Name())
-	return err
+	return nil
 }
 
 // ModifyHostGroup modifies a host group.
 func (c *CommandLineQConf) ModifyHostGroup(hostGroupName string, cfg HostGroupConfig) error {
-	file, err := CreateTempDirWithFileName(hostGroupName)
+	file, err := c.createObjectFile(hostGroupName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("group_name %s\n", hostGroupName))
+	cfg.Name = hostGroupName
+	err = writeHostGroup(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mhgrp", file.Name())
+	return err
+}
+
+// writeHostGroup writes the host group file read by qconf.
+func writeHostGroup(file *os.File, cfg HostGroupConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("group_name %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -3839,10 +4101,7 @@ func (c *CommandLineQConf) ModifyHostGroup(hostGroupName string, cfg HostGroupCo
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Mhgrp", file.Name())
-	return err
+	return nil
 }
 
 func SetResourceQuotaSetDefaults(cfg *ResourceQuotaSetConfig) {
@@ -3863,18 +4122,32 @@ func (c *CommandLineQConf) ModifyResourceQuotaSet(rqsName string, cfg ResourceQu
 		return err
 	}
 	SetResourceQuotaSetDefaults(&cfg)
-	file, err := CreateTempDirWithFileName(rqsName)
+	file, err := c.createObjectFile(rqsName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString("{\n")
+	cfg.Name = rqsName
+	err = writeResourceQuotaSet(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mrqs", file.Name(), rqsName)
+	return err
+}
+
+// writeResourceQuotaSet writes the resource quota set file read by qconf.
+func writeResourceQuotaSet(file *os.File, cfg ResourceQuotaSetConfig) error {
+	_, err := file.WriteString("{\n")
 	if err != nil {
 		return err
 	}
 
-	_, err = file.WriteString(fmt.Sprintf("name         %s\n", rqsName))
+	_, err = file.WriteString(fmt.Sprintf("name         %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -3899,16 +4172,13 @@ func (c *CommandLineQConf) ModifyResourceQuotaSet(rqsName string, cfg ResourceQu
 	if err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Mrqs", file.Name(), rqsName)
-	return err
+	return nil
 }
 
 // ModifyParallelEnvironment modifies a parallel environment.
 func (c *CommandLineQConf) ModifyParallelEnvironment(peName string, cfg ParallelEnvironmentConfig) error {
 	SetDefaultParallelEnvironmentValues(&cfg)
-	file, err := CreateTempDirWithFileName(peName)
+	file, err := c.createObjectFile(peName)
 	if err != nil {
 		return err
 	}
@@ -3931,13 +4201,27 @@ func (c *CommandLineQConf) ModifyParallelEnvironment(peName string, cfg Parallel
 // ModifyProject modifies a project.
 func (c *CommandLineQConf) ModifyProject(projectName string, cfg ProjectConfig) error {
 	SetDefaultProjectValues(&cfg)
-	file, err := CreateTempDirWithFileName(projectName)
+	file, err := c.createObjectFile(projectName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("name    %s\n", projectName))
+	cfg.Name = projectName
+	err = writeProject(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mprj", file.Name())
+	return err
+}
+
+// writeProject writes the project file read by qconf.
+func writeProject(file *os.File, cfg ProjectConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("name    %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -3960,22 +4244,33 @@ func (c *CommandLineQConf) ModifyProject(projectName string, cfg ProjectConfig)
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Mprj", file.Name())
-	return err
+	return nil
 }
 
 // ModifyClusterQueue modifies a cluster queue.
 func (c *CommandLineQConf) ModifyClusterQueue(queueName string, cfg ClusterQueueConfig) error {
 	SetDefaultQueueValues(&cfg)
-	file, err := CreateTempDirWithFileName(queueName)
+	file, err := c.createObjectFile(queueName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("qname             %s\n", queueName))
+	cfg.Name = queueName
+	err = writeClusterQueue(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mq", file.Name())
+	return err
+}
+
+// writeClusterQueue writes the cluster queue file read by qconf.
+func writeClusterQueue(file *os.File, cfg ClusterQueueConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("qname             %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -4206,22 +4501,32 @@ func (c *CommandLineQConf) ModifyClusterQueue(queueName string, cfg ClusterQueue
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-
-	file.Close()
-
-	_, err = c.RunCommand("-Mq", file.Name())
-	return err
+	return nil
 }
 
 // ModifyUserset modifies a user set list.
 func (c *CommandLineQConf) ModifyUserset(listnameList string, cfg UserSetListConfig) error {
-	file, err := CreateTempDirWithFileName(listnameList)
+	file, err := c.createObjectFile(listnameList)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("name    %s\n", listnameList))
+	cfg.Name = listnameList
+	err = writeUserSetList(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Mu", file.Name())
+	return err
+}
+
+// writeUserSetList writes the user set list file read by qconf.
+func writeUserSetList(file *os.File, cfg UserSetListConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("name    %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -4246,10 +4551,7 @@ func (c *CommandLineQConf) ModifyUserset(listnameList string, cfg UserSetListCon
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Mu", file.Name())
-	return err
+	return nil
 }
 
 func SetDefaultUserValues(u *UserConfig) {
@@ -4261,13 +4563,27 @@ func SetDefaultUserValues(u *UserConfig) {
 // ModifyUser modifies a user.
 func (c *CommandLineQConf) ModifyUser(userName string, cfg UserConfig) error {
 	SetDefaultUserValues(&cfg)
-	file, err := CreateTempDirWithFileName(userName)
+	file, err := c.createObjectFile(userName)
 	if err != nil {
 		return err
 	}
 	defer os.RemoveAll(filepath.Dir(file.Name()))
 
-	_, err = file.WriteString(fmt.Sprintf("name           %s\n", userName))
+	cfg.Name = userName
+	err = writeUser(file, cfg)
+	if err != nil {
+		file.Close()
+		return err
+	}
+	file.Close()
+
+	_, err = c.RunCommand("-Muser", file.Name())
+	return err
+}
+
+// writeUser writes the user file read by qconf.
+func writeUser(file *os.File, cfg UserConfig) error {
+	_, err := file.WriteString(fmt.Sprintf("name           %s\n", cfg.Name))
 	if err != nil {
 		return err
 	}
@@ -4290,10 +4606,7 @@ func (c *CommandLineQConf) ModifyUser(userName string, cfg UserConfig) error {
 	if err := WriteExtraFields(file, cfg.ExtraFields, TypedKeysOf(cfg)); err != nil {
 		return err
 	}
-	file.Close()
-
-	_, err = c.RunCommand("-Muser", file.Name())
-	return err
+	return nil
 }
 
 // DeleteAttribute deletes a value from a list-valued attribute of an
@@ -4311,13 +4624,40 @@ func (c *CommandLineQConf) DeleteAttribute(objName, attrName, val, objIDList str
 	return checkAttrModification(out)
 }
 
+// PurgeAttribute removes the host or host group specific values of the
+// comma separated attributes attrName from a queue instance like
+// "all.q@node1" or "all.q@@gpu", so the cluster queue's default applies
+// there again. objName must be "queue", the only object qconf -purge
+// supports. It returns an error wrapping ErrNoModification when qconf
+// reports that nothing was removed.
+func (c *CommandLineQConf) PurgeAttribute(objName, attrName, objInstance string) error {
+	if err := validate.Enforce(
+		validate.Operand(objName),
+		validate.SplitAndValidateList(attrName),
+		validate.SplitAndValidateList(objInstance),
+	); err != nil {
+		return err
+	}
+	out, err := c.RunCommand("-purge", objName, attrName, objInstance)
+	if err != nil {
+		return err
+	}
+	return checkAttrModification(out)
+}
+
 // ShowSchedulerConfiguration shows the scheduler configuration.
 func (c *CommandLineQConf) ShowSchedulerConfiguration() (*SchedulerConfig, error) {
 	out, err := c.RunCommand("-ssconf")
 	if err != nil {
 		return nil, err
 	}
-	lines := strings.Split(out, "\n")
+	cfg := ParseSchedulerConfigFromLines(strings.Split(out, "\n"))
+	return &cfg, nil
+}
+
+// ParseSchedulerConfigFromLines parses the output lines of "qconf -ssconf"
+// into a SchedulerConfig.
+func ParseSchedulerConfigFromLines(lines []string) SchedulerConfig {
 	cfg := SchedulerConfig{}
 	for i, line := range lines {
 		fields := strings.Fields(line)
@@ -4401,7 +4741,7 @@ func (c *CommandLineQConf) ShowSchedulerConfiguration() (*SchedulerConfig, error
 			CaptureExtraField(&cfg.ExtraFields, lines, i)
 		}
 	}
-	return &cfg, nil
+	return cfg
 }
 
 // writeSchedulerConfig emits the typed fields of cfg as qconf attribute
@@ -4508,7 +4848,7 @@ func (c *CommandLineQConf) ModifySchedulerConfig(cfg SchedulerConfig) error {
 		cfg.LoadFormula = "np_load_avg"
 	}
 
-	file, err := CreateTempDirWithFileName("scheduler")
+	file, err := c.createObjectFile("scheduler")
 	if err != nil {
 		return err
 	}
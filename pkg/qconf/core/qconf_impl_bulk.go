@@ -0,0 +1,175 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"slices"
+	"strings"
+)
+
+// bulkChunkSize is the maximum number of objects requested by a single
+// bulk qconf call. It keeps the command line well below ARG_MAX even for
+// long fully qualified host names.
+const bulkChunkSize = 200
+
+// SplitObjectBlocks splits the concatenated output of a qconf show
+// command for several objects, like qconf -sq a.q,b.q, into one block of
+// lines per object. A block starts at each line whose first field is
+// key ("qname" for queues, "hostname" for exec hosts, "{" for resource
+// quota sets). Lines before the first key line are dropped.
+func SplitObjectBlocks(out string, key string) [][]string {
+	var blocks [][]string
+	var current []string
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == key {
+			if current != nil {
+				blocks = append(blocks, current)
+			}
+			current = []string{}
+		}
+		if current != nil {
+			current = append(current, line)
+		}
+	}
+	if current != nil {
+		blocks = append(blocks, current)
+	}
+	return blocks
+}
+
+// showBulk runs flag with comma separated chunks of names and parses
+// every object block of the output.
+func showBulk[T any](c *CommandLineQConf, flag, key string, names []string,
+	parse func(lines []string) (T, error)) ([]T, error) {
+
+	var objects []T
+	for chunk := range slices.Chunk(names, bulkChunkSize) {
+		out, err := c.runNamedList(flag, strings.Join(chunk, ","))
+		if err != nil {
+			return objects, err
+		}
+		for _, block := range SplitObjectBlocks(out, key) {
+			obj, err := parse(block)
+			if err != nil {
+				return objects, err
+			}
+			objects = append(objects, obj)
+		}
+	}
+	return objects, nil
+}
+
+// ShowClusterQueuesBulk shows the given cluster queues with one qconf
+// -sq call per chunk of queues.
+func (c *CommandLineQConf) ShowClusterQueuesBulk(queueNames []string) ([]ClusterQueueConfig, error) {
+	return showBulk(c, "-sq", "qname", queueNames,
+		func(lines []string) (ClusterQueueConfig, error) {
+			return ParseClusterQueueConfigFromLines(lines), nil
+		})
+}
+
+// ShowExecHostsBulk shows the given execution hosts with one qconf -se
+// call per chunk of hosts.
+func (c *CommandLineQConf) ShowExecHostsBulk(hostNames []string) ([]HostExecConfig, error) {
+	return showBulk(c, "-se", "hostname", hostNames, ParseExecHostConfigFromLines)
+}
+
+// ShowProjectsBulk shows the given projects with one qconf -sprj call
+// per chunk of projects.
+func (c *CommandLineQConf) ShowProjectsBulk(projectNames []string) ([]ProjectConfig, error) {
+	return showBulk(c, "-sprj", "name", projectNames,
+		func(lines []string) (ProjectConfig, error) {
+			return ParseProjectConfigFromLines(lines), nil
+		})
+}
+
+// ShowUserSetListsBulk shows the given user set lists with one qconf -su
+// call per chunk of lists.
+func (c *CommandLineQConf) ShowUserSetListsBulk(listNames []string) ([]UserSetListConfig, error) {
+	return showBulk(c, "-su", "name", listNames,
+		func(lines []string) (UserSetListConfig, error) {
+			return ParseUserSetListConfigFromLines(lines), nil
+		})
+}
+
+// ShowUsersBulk shows the given users with one qconf -suser call per
+// chunk of users. Users which are not defined are not part of the result;
+// qconf reports them with exit code 0.
+func (c *CommandLineQConf) ShowUsersBulk(userNames []string) ([]UserConfig, error) {
+	return showBulk(c, "-suser", "name", userNames,
+		func(lines []string) (UserConfig, error) {
+			lines = slices.DeleteFunc(lines, func(line string) bool {
+				return strings.Contains(line, "is not known as user")
+			})
+			return ParseUserConfigFromLines(lines), nil
+		})
+}
+
+// ShowResourceQuotaSetsBulk shows the given resource quota sets with one
+// qconf -srqs call per chunk of rule sets.
+func (c *CommandLineQConf) ShowResourceQuotaSetsBulk(rqsNames []string) ([]ResourceQuotaSetConfig, error) {
+	return showBulk(c, "-srqs", "{", rqsNames,
+		func(lines []string) (ResourceQuotaSetConfig, error) {
+			return ParseResourceQuotaSetConfigFromLines(lines), nil
+		})
+}
+
+// fetchObjectsBulk reads names with bulk calls and falls back to
+// fetchObjects for chunks whose bulk call failed and for names missing
+// from the bulk output. A single broken object therefore costs a few
+// extra processes, but failures are still reported per object.
+func fetchObjectsBulk[T any](c *CommandLineQConf, kind string, names []string,
+	bulk func(names []string) ([]T, error), nameOf func(T) string,
+	show func(name string) (T, error)) (map[string]T, []*ObjectError) {
+
+	objects := make(map[string]T, len(names))
+	var retry []string
+	for chunk := range slices.Chunk(names, bulkChunkSize) {
+		if c.Context().Err() != nil {
+			retry = append(retry, chunk...)
+			continue
+		}
+		found, err := bulk(chunk)
+		if err != nil {
+			retry = append(retry, chunk...)
+			continue
+		}
+		byName := make(map[string]T, len(found))
+		for _, obj := range found {
+			byName[nameOf(obj)] = obj
+		}
+		for _, name := range chunk {
+			if obj, ok := byName[name]; ok {
+				objects[name] = obj
+			} else {
+				retry = append(retry, name)
+			}
+		}
+	}
+	if len(retry) == 0 {
+		return objects, nil
+	}
+	retried, errs := fetchObjects(c, kind, retry, show)
+	for name, obj := range retried {
+		objects[name] = obj
+	}
+	return objects, errs
+}
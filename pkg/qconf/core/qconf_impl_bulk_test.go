@@ -0,0 +1,133 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core/internal/fakeqconf"
+)
+
+var _ = Describe("CommandLineQConf bulk show calls", func() {
+
+	It("splits concatenated queue output", func() {
+		f := newFakeQConf("qname a.q\nhostlist @allhosts\nslots 1\n"+
+			"qname b.q\nhostlist NONE\nslots 2,[host1=4]\n", 0)
+		defer f.Cleanup()
+
+		queues, err := newQConfWith(f).ShowClusterQueuesBulk([]string{"a.q", "b.q"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.Argv()).To(Equal([]string{"-sq", "a.q,b.q"}))
+		Expect(queues).To(HaveLen(2))
+		Expect(queues[0].Name).To(Equal("a.q"))
+		Expect(queues[0].HostList).To(Equal([]string{"@allhosts"}))
+		Expect(queues[1].Name).To(Equal("b.q"))
+		Expect(queues[1].Slots).To(Equal([]string{"2", "[host1=4]"}))
+	})
+
+	It("splits resource quota sets at the opening brace", func() {
+		f := newFakeQConf("{\n   name rqs1\n   enabled TRUE\n   limit to slots=1\n}\n"+
+			"{\n   name rqs2\n   enabled FALSE\n   limit to slots=2\n}\n", 0)
+		defer f.Cleanup()
+
+		sets, err := newQConfWith(f).ShowResourceQuotaSetsBulk([]string{"rqs1", "rqs2"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sets).To(HaveLen(2))
+		Expect(sets[0].Name).To(Equal("rqs1"))
+		Expect(sets[0].Enabled).To(BeTrue())
+		Expect(sets[1].Limits).To(Equal([]string{"to slots=2"}))
+	})
+
+	It("leaves out users which are not defined", func() {
+		f := newFakeQConf("name alice\noticket 0\nfshare 10\ndelete_time 0\ndefault_project NONE\n"+
+			"bob is not known as user\n", 0)
+		defer f.Cleanup()
+
+		users, err := newQConfWith(f).ShowUsersBulk([]string{"alice", "bob"})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(users).To(HaveLen(1))
+		Expect(users[0].Name).To(Equal("alice"))
+		Expect(users[0].ExtraFields).To(BeEmpty())
+	})
+
+	It("requests large lists in chunks", func() {
+		f := newFakeQConf("", 0)
+		defer f.Cleanup()
+
+		var names []string
+		for i := range 450 {
+			names = append(names, fmt.Sprintf("p%d", i))
+		}
+		_, err := newQConfWith(f).ShowProjectsBulk(names)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.AllArgvLines()).To(HaveLen(3))
+	})
+
+	It("is used by GetClusterConfiguration", func() {
+		if !fakeqconf.Available() {
+			Skip("fakeqconf uses a bash script; skip on this platform")
+		}
+		responses := clusterResponses()
+		var bulk strings.Builder
+		var names []string
+		for i := 1; i <= 12; i++ {
+			name := fmt.Sprintf("p%d", i)
+			names = append(names, name)
+			fmt.Fprintf(&bulk, "name %s\noticket %d\nfshare %d\nacl NONE\nxacl NONE\n", name, i, i*10)
+		}
+		responses["-sprj "+strings.Join(names, ",")] = fakeqconf.Response{Stdout: bulk.String()}
+
+		f := fakeqconf.NewDispatch(GinkgoT(), responses,
+			fakeqconf.Response{Stdout: "no object defined\n", RC: 1})
+		defer f.Cleanup()
+		qc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{Executable: f.Path()})
+		Expect(err).NotTo(HaveOccurred())
+
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.Projects).To(HaveLen(12))
+		Expect(cc.Projects["p7"].FShare).To(Equal(70))
+
+		var projectCalls []string
+		for _, line := range f.AllArgvLines() {
+			if strings.HasPrefix(line, "-sprj ") {
+				projectCalls = append(projectCalls, line)
+			}
+		}
+		Expect(projectCalls).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("SplitObjectBlocks", func() {
+
+	It("drops lines before the first key line", func() {
+		blocks := core.SplitObjectBlocks("warning: something\nname a\nx 1\nname b\n", "name")
+		Expect(blocks).To(Equal([][]string{{"name a", "x 1"}, {"name b", ""}}))
+	})
+
+	It("returns nothing for output without key lines", func() {
+		Expect(core.SplitObjectBlocks("error\n", "qname")).To(BeEmpty())
+	})
+})
@@ -0,0 +1,883 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// CachingQConf is a QConf decorator which caches the results of the
+// Show* methods and of GetClusterConfiguration, so that programs serving
+// many requests, like the MCP server or the REST adapter, do not run a
+// qconf process for every read.
+//
+// Every Add*, Modify* and Delete* call made through a CachingQConf drops
+// the cached results of the object kind it changes, and with them the
+// cached cluster configuration; ApplyClusterConfiguration and attribute
+// changes of unknown objects drop everything. Changes made by other
+// clients are only seen when the cached result expires or after Refresh.
+// The share tree, the version and the daemon control calls are not
+// cached. Errors are never cached.
+//
+// Cached values are deep copies; callers may modify what they get.
+// CachingQConf is safe for concurrent use when the wrapped QConf is.
+type CachingQConf struct {
+	qc     QConf
+	config CachingQConfConfig
+	// cache is shared with the copies made by WithContext.
+	cache *resultCache
+}
+
+// resultCache holds the cached results of a CachingQConf.
+type resultCache struct {
+	mu      sync.Mutex
+	entries map[cacheKey]cacheEntry
+	// generation is increased on every invalidation. A result loaded
+	// while an invalidation happened may be stale and is not stored.
+	generation uint64
+}
+
+var _ QConf = (*CachingQConf)(nil)
+
+// CachingQConfConfig configures a CachingQConf.
+type CachingQConfConfig struct {
+	// TTL is how long a result stays cached. Zero disables caching for
+	// all kinds which have no entry in KindTTL.
+	TTL time.Duration
+	// KindTTL overrides TTL per object kind; zero disables caching of
+	// the kind. The cluster configuration is cached for the shortest
+	// TTL of all kinds and not at all when caching of a kind is disabled.
+	KindTTL map[ObjectKind]time.Duration
+}
+
+// kindClusterConfig is the cache kind of GetClusterConfiguration.
+const kindClusterConfig ObjectKind = ""
+
+type cacheKey struct {
+	kind ObjectKind
+	call string
+	name string
+}
+
+type cacheEntry struct {
+	value   any
+	expires time.Time
+}
+
+// NewCachingQConf returns a CachingQConf wrapping qc.
+func NewCachingQConf(qc QConf, config CachingQConfConfig) *CachingQConf {
+	return &CachingQConf{
+		qc:     qc,
+		config: config,
+		cache:  &resultCache{entries: make(map[cacheKey]cacheEntry)},
+	}
+}
+
+// WithContext returns a copy of q which shares the cache of q and whose
+// calls go to the wrapped QConf bound to ctx, like
+// CommandLineQConf.WithContext. When the wrapped QConf has no
+// WithContext method, the copy uses it unchanged.
+func (q *CachingQConf) WithContext(ctx context.Context) *CachingQConf {
+	if ctx == nil {
+		panic("nil context")
+	}
+	q2 := *q
+	q2.qc = WithContext(q.qc, ctx)
+	return &q2
+}
+
+// supports reports whether the wrapped QConf can run c, so ModifyFields
+// replaces the object as a whole when it cannot.
+func (q *CachingQConf) supports(c AttributeChange) bool {
+	return c.supportedBy(q.qc)
+}
+
+// executable returns the qconf executable of the wrapped QConf, so
+// plans of a CachingQConf name the same binary.
+func (q *CachingQConf) executable() string {
+	return executableOf(q.qc)
+}
+
+// Refresh drops the cached results of the given object kind and the
+// cached cluster configuration, so the next call reads them again.
+func (q *CachingQConf) Refresh(kind ObjectKind) {
+	q.cache.mu.Lock()
+	defer q.cache.mu.Unlock()
+	q.cache.generation++
+	for key := range q.cache.entries {
+		if key.kind == kind || key.kind == kindClusterConfig {
+			delete(q.cache.entries, key)
+		}
+	}
+}
+
+// RefreshAll drops all cached results.
+func (q *CachingQConf) RefreshAll() {
+	q.cache.mu.Lock()
+	defer q.cache.mu.Unlock()
+	q.cache.generation++
+	clear(q.cache.entries)
+}
+
+// ttl returns how long results of kind are cached.
+func (q *CachingQConf) ttl(kind ObjectKind) time.Duration {
+	if kind == kindClusterConfig {
+		ttl := q.config.TTL
+		for _, k := range ObjectKinds() {
+			if t := q.ttl(k); t < ttl {
+				ttl = t
+			}
+		}
+		return ttl
+	}
+	if ttl, ok := q.config.KindTTL[kind]; ok {
+		return ttl
+	}
+	return q.config.TTL
+}
+
+// cached returns the cached result of call for the object name, or
+// loads and caches it.
+func cached[T any](q *CachingQConf, kind ObjectKind, call, name string, load func() (T, error)) (T, error) {
+	ttl := q.ttl(kind)
+	if ttl <= 0 {
+		return load()
+	}
+	key := cacheKey{kind: kind, call: call, name: name}
+	q.cache.mu.Lock()
+	entry, ok := q.cache.entries[key]
+	generation := q.cache.generation
+	q.cache.mu.Unlock()
+	if ok && time.Now().Before(entry.expires) {
+		return deepCopy(entry.value.(T)), nil
+	}
+
+	value, err := load()
+	if err != nil {
+		return value, err
+	}
+	q.cache.mu.Lock()
+	if q.cache.generation == generation {
+		q.cache.entries[key] = cacheEntry{value: deepCopy(value), expires: time.Now().Add(ttl)}
+	}
+	q.cache.mu.Unlock()
+	return value, nil
+}
+
+// GetClusterConfiguration returns the cluster configuration.
+func (q *CachingQConf) GetClusterConfiguration() (ClusterConfig, error) {
+	return cached(q, kindClusterConfig, "GetClusterConfiguration", "", q.qc.GetClusterConfiguration)
+}
+
+// ApplyClusterConfiguration applies the cluster configuration and drops
+// all cached results.
+func (q *CachingQConf) ApplyClusterConfiguration(cc ClusterConfig) error {
+	defer q.RefreshAll()
+	return q.qc.ApplyClusterConfiguration(cc)
+}
+
+// ModifyAttribute modifies an attribute of an object, like qconf -mattr.
+func (q *CachingQConf) ModifyAttribute(objName, attrName, val, objIDList string) error {
+	defer q.refreshAttrObject(objName)
+	return q.qc.ModifyAttribute(objName, attrName, val, objIDList)
+}
+
+// AddAttribute adds a value to a list-valued attribute of an object,
+// like qconf -aattr.
+func (q *CachingQConf) AddAttribute(objName, attrName, val, objIDList string) error {
+	defer q.refreshAttrObject(objName)
+	return q.qc.AddAttribute(objName, attrName, val, objIDList)
+}
+
+// DeleteAttribute deletes a value from a list-valued attribute of an
+// object, like qconf -dattr.
+func (q *CachingQConf) DeleteAttribute(objName, attrName, val, objIDList string) error {
+	defer q.refreshAttrObject(objName)
+	return q.qc.DeleteAttribute(objName, attrName, val, objIDList)
+}
+
+// ReplaceAttribute replaces the complete value of a list-valued attribute
+// of an object, like qconf -rattr.
+func (q *CachingQConf) ReplaceAttribute(objName, attrName, val, objIDList string) error {
+	defer q.refreshAttrObject(objName)
+	return replaceAttribute(q.qc, objName, attrName, val, objIDList)
+}
+
+// PurgeAttribute removes host or host group specific values from a
+// queue instance, like qconf -purge.
+func (q *CachingQConf) PurgeAttribute(objName, attrName, objInstance string) error {
+	defer q.refreshAttrObject(objName)
+	return purgeAttribute(q.qc, objName, attrName, objInstance)
+}
+
+// refreshAttrObject drops the cached results of the kind of the qconf
+// attribute object name, or all results for an unknown object.
+func (q *CachingQConf) refreshAttrObject(objName string) {
+	for kind, name := range attrObjectNames {
+		if name == objName {
+			q.Refresh(kind)
+			return
+		}
+	}
+	q.RefreshAll()
+}
+
+// GetVersion calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) GetVersion() (ClusterSchedulerVersion, error) {
+	return q.qc.GetVersion()
+}
+
+// AddCalendar adds a new calendar.
+func (q *CachingQConf) AddCalendar(cfg CalendarConfig) error {
+	defer q.Refresh(KindCalendar)
+	return q.qc.AddCalendar(cfg)
+}
+
+// DeleteCalendar deletes a calendar.
+func (q *CachingQConf) DeleteCalendar(calendarName string) error {
+	defer q.Refresh(KindCalendar)
+	return q.qc.DeleteCalendar(calendarName)
+}
+
+// ShowCalendar shows the specified calendar.
+func (q *CachingQConf) ShowCalendar(calendarName string) (CalendarConfig, error) {
+	return cached(q, KindCalendar, "ShowCalendar", calendarName, func() (CalendarConfig, error) {
+		return q.qc.ShowCalendar(calendarName)
+	})
+}
+
+// ShowCalendars shows all calendars.
+func (q *CachingQConf) ShowCalendars() ([]string, error) {
+	return cached(q, KindCalendar, "ShowCalendars", "", func() ([]string, error) {
+		return q.qc.ShowCalendars()
+	})
+}
+
+// ModifyCalendar modifies a calendar.
+func (q *CachingQConf) ModifyCalendar(calendarName string, cfg CalendarConfig) error {
+	defer q.Refresh(KindCalendar)
+	return q.qc.ModifyCalendar(calendarName, cfg)
+}
+
+// AddComplexEntry adds a new complex entry.
+func (q *CachingQConf) AddComplexEntry(e ComplexEntryConfig) error {
+	defer q.Refresh(KindComplexEntry)
+	return q.qc.AddComplexEntry(e)
+}
+
+// DeleteComplexEntry deletes a complex entry.
+func (q *CachingQConf) DeleteComplexEntry(entryName string) error {
+	defer q.Refresh(KindComplexEntry)
+	return q.qc.DeleteComplexEntry(entryName)
+}
+
+// ShowComplexEntry shows the specified complex entry.
+func (q *CachingQConf) ShowComplexEntry(entryName string) (ComplexEntryConfig, error) {
+	return cached(q, KindComplexEntry, "ShowComplexEntry", entryName, func() (ComplexEntryConfig, error) {
+		return q.qc.ShowComplexEntry(entryName)
+	})
+}
+
+// ShowComplexEntries shows the names of all complex entries.
+func (q *CachingQConf) ShowComplexEntries() ([]string, error) {
+	return cached(q, KindComplexEntry, "ShowComplexEntries", "", func() ([]string, error) {
+		return q.qc.ShowComplexEntries()
+	})
+}
+
+// ShowAllComplexes shows all complex entries sorted by name.
+func (q *CachingQConf) ShowAllComplexes() ([]ComplexEntryConfig, error) {
+	return cached(q, KindComplexEntry, "ShowAllComplexes", "", func() ([]ComplexEntryConfig, error) {
+		return q.qc.ShowAllComplexes()
+	})
+}
+
+// ModifyAllComplexes replaces the complete complex configuration, like
+// qconf -Mc: entries which are not in centries are removed.
+func (q *CachingQConf) ModifyAllComplexes(centries []ComplexEntryConfig) error {
+	defer q.Refresh(KindComplexEntry)
+	return q.qc.ModifyAllComplexes(centries)
+}
+
+// ModifyComplexEntry modifies a complex entry.
+func (q *CachingQConf) ModifyComplexEntry(complexName string, cfg ComplexEntryConfig) error {
+	defer q.Refresh(KindComplexEntry)
+	return q.qc.ModifyComplexEntry(complexName, cfg)
+}
+
+// AddCkptInterface adds a new checkpointing interface.
+func (q *CachingQConf) AddCkptInterface(cfg CkptInterfaceConfig) error {
+	defer q.Refresh(KindCkptInterface)
+	return q.qc.AddCkptInterface(cfg)
+}
+
+// DeleteCkptInterface deletes a checkpointing interface.
+func (q *CachingQConf) DeleteCkptInterface(interfaceName string) error {
+	defer q.Refresh(KindCkptInterface)
+	return q.qc.DeleteCkptInterface(interfaceName)
+}
+
+// ShowCkptInterface shows the specified checkpointing interface.
+func (q *CachingQConf) ShowCkptInterface(interfaceName string) (CkptInterfaceConfig, error) {
+	return cached(q, KindCkptInterface, "ShowCkptInterface", interfaceName, func() (CkptInterfaceConfig, error) {
+		return q.qc.ShowCkptInterface(interfaceName)
+	})
+}
+
+// ShowCkptInterfaces shows all checkpointing interfaces.
+func (q *CachingQConf) ShowCkptInterfaces() ([]string, error) {
+	return cached(q, KindCkptInterface, "ShowCkptInterfaces", "", func() ([]string, error) {
+		return q.qc.ShowCkptInterfaces()
+	})
+}
+
+// ModifyCkptInterface modifies a checkpointing interface.
+func (q *CachingQConf) ModifyCkptInterface(ckptName string, cfg CkptInterfaceConfig) error {
+	defer q.Refresh(KindCkptInterface)
+	return q.qc.ModifyCkptInterface(ckptName, cfg)
+}
+
+// AddHostConfiguration adds a new host configuration.
+func (q *CachingQConf) AddHostConfiguration(config HostConfiguration) error {
+	defer q.Refresh(KindHostConfiguration)
+	return q.qc.AddHostConfiguration(config)
+}
+
+// DeleteHostConfiguration deletes a host configuration.
+func (q *CachingQConf) DeleteHostConfiguration(configName string) error {
+	defer q.Refresh(KindHostConfiguration)
+	return q.qc.DeleteHostConfiguration(configName)
+}
+
+// ShowHostConfiguration shows the specified host configuration.
+func (q *CachingQConf) ShowHostConfiguration(hostName string) (HostConfiguration, error) {
+	return cached(q, KindHostConfiguration, "ShowHostConfiguration", hostName, func() (HostConfiguration, error) {
+		return q.qc.ShowHostConfiguration(hostName)
+	})
+}
+
+// ShowHostConfigurations shows the names of all host configurations.
+func (q *CachingQConf) ShowHostConfigurations() ([]string, error) {
+	return cached(q, KindHostConfiguration, "ShowHostConfigurations", "", func() ([]string, error) {
+		return q.qc.ShowHostConfigurations()
+	})
+}
+
+// ModifyHostConfiguration modifies a host configuration.
+func (q *CachingQConf) ModifyHostConfiguration(configName string, cfg HostConfiguration) error {
+	defer q.Refresh(KindHostConfiguration)
+	return q.qc.ModifyHostConfiguration(configName, cfg)
+}
+
+// ShowGlobalConfiguration shows the global configuration.
+func (q *CachingQConf) ShowGlobalConfiguration() (*GlobalConfig, error) {
+	return cached(q, KindGlobalConfig, "ShowGlobalConfiguration", "", func() (*GlobalConfig, error) {
+		return q.qc.ShowGlobalConfiguration()
+	})
+}
+
+// ModifyGlobalConfig modifies the global configuration.
+func (q *CachingQConf) ModifyGlobalConfig(g GlobalConfig) error {
+	defer q.Refresh(KindGlobalConfig)
+	return q.qc.ModifyGlobalConfig(g)
+}
+
+// AddExecHost adds a new execution host.
+func (q *CachingQConf) AddExecHost(hostExecConfig HostExecConfig) error {
+	defer q.Refresh(KindExecHost)
+	return q.qc.AddExecHost(hostExecConfig)
+}
+
+// DeleteExecHost deletes a comma-separated list of execution hosts.
+func (q *CachingQConf) DeleteExecHost(hostList string) error {
+	defer q.Refresh(KindExecHost)
+	return q.qc.DeleteExecHost(hostList)
+}
+
+// ModifyExecHost modifies an execution host.
+func (q *CachingQConf) ModifyExecHost(execHostName string, h HostExecConfig) error {
+	defer q.Refresh(KindExecHost)
+	return q.qc.ModifyExecHost(execHostName, h)
+}
+
+// ShowExecHost shows the specified execution host.
+func (q *CachingQConf) ShowExecHost(hostName string) (HostExecConfig, error) {
+	return cached(q, KindExecHost, "ShowExecHost", hostName, func() (HostExecConfig, error) {
+		return q.qc.ShowExecHost(hostName)
+	})
+}
+
+// ShowExecHosts shows all execution hosts.
+func (q *CachingQConf) ShowExecHosts() ([]string, error) {
+	return cached(q, KindExecHost, "ShowExecHosts", "", func() ([]string, error) {
+		return q.qc.ShowExecHosts()
+	})
+}
+
+// AddAdminHost adds administrative hosts.
+func (q *CachingQConf) AddAdminHost(hosts []string) error {
+	defer q.Refresh(KindAdminHost)
+	return q.qc.AddAdminHost(hosts)
+}
+
+// DeleteAdminHost deletes administrative hosts.
+func (q *CachingQConf) DeleteAdminHost(hosts []string) error {
+	defer q.Refresh(KindAdminHost)
+	return q.qc.DeleteAdminHost(hosts)
+}
+
+// ShowAdminHosts shows all administrative hosts.
+func (q *CachingQConf) ShowAdminHosts() ([]string, error) {
+	return cached(q, KindAdminHost, "ShowAdminHosts", "", func() ([]string, error) {
+		return q.qc.ShowAdminHosts()
+	})
+}
+
+// AddHostGroup adds a new host group.
+func (q *CachingQConf) AddHostGroup(hostGroup HostGroupConfig) error {
+	defer q.Refresh(KindHostGroup)
+	return q.qc.AddHostGroup(hostGroup)
+}
+
+// ModifyHostGroup modifies a host group.
+func (q *CachingQConf) ModifyHostGroup(hostGroupName string, hg HostGroupConfig) error {
+	defer q.Refresh(KindHostGroup)
+	return q.qc.ModifyHostGroup(hostGroupName, hg)
+}
+
+// DeleteHostGroup deletes a host group.
+func (q *CachingQConf) DeleteHostGroup(groupName string) error {
+	defer q.Refresh(KindHostGroup)
+	return q.qc.DeleteHostGroup(groupName)
+}
+
+// ShowHostGroup shows the specified host group.
+func (q *CachingQConf) ShowHostGroup(groupName string) (HostGroupConfig, error) {
+	return cached(q, KindHostGroup, "ShowHostGroup", groupName, func() (HostGroupConfig, error) {
+		return q.qc.ShowHostGroup(groupName)
+	})
+}
+
+// ShowHostGroupResolved returns all hosts of a host group, with nested
+// host groups expanded.
+func (q *CachingQConf) ShowHostGroupResolved(groupName string) ([]string, error) {
+	return cached(q, KindHostGroup, "ShowHostGroupResolved", groupName, func() ([]string, error) {
+		return q.qc.ShowHostGroupResolved(groupName)
+	})
+}
+
+// ShowHostGroups shows all host groups.
+func (q *CachingQConf) ShowHostGroups() ([]string, error) {
+	return cached(q, KindHostGroup, "ShowHostGroups", "", func() ([]string, error) {
+		return q.qc.ShowHostGroups()
+	})
+}
+
+// AddResourceQuotaSet adds a new resource quota set.
+func (q *CachingQConf) AddResourceQuotaSet(rqs ResourceQuotaSetConfig) error {
+	defer q.Refresh(KindResourceQuotaSet)
+	return q.qc.AddResourceQuotaSet(rqs)
+}
+
+// DeleteResourceQuotaSet deletes a comma-separated list of resource
+// quota sets.
+func (q *CachingQConf) DeleteResourceQuotaSet(rqsList string) error {
+	defer q.Refresh(KindResourceQuotaSet)
+	return q.qc.DeleteResourceQuotaSet(rqsList)
+}
+
+// ShowResourceQuotaSet shows the specified resource quota set.
+func (q *CachingQConf) ShowResourceQuotaSet(rqsList string) (ResourceQuotaSetConfig, error) {
+	return cached(q, KindResourceQuotaSet, "ShowResourceQuotaSet", rqsList, func() (ResourceQuotaSetConfig, error) {
+		return q.qc.ShowResourceQuotaSet(rqsList)
+	})
+}
+
+// ShowResourceQuotaSets shows all resource quota sets.
+func (q *CachingQConf) ShowResourceQuotaSets() ([]string, error) {
+	return cached(q, KindResourceQuotaSet, "ShowResourceQuotaSets", "", func() ([]string, error) {
+		return q.qc.ShowResourceQuotaSets()
+	})
+}
+
+// ModifyResourceQuotaSet modifies a resource quota set.
+func (q *CachingQConf) ModifyResourceQuotaSet(rqsName string, rqs ResourceQuotaSetConfig) error {
+	defer q.Refresh(KindResourceQuotaSet)
+	return q.qc.ModifyResourceQuotaSet(rqsName, rqs)
+}
+
+// AddUserToManagerList adds users to the manager list.
+func (q *CachingQConf) AddUserToManagerList(users []string) error {
+	defer q.Refresh(KindManager)
+	return q.qc.AddUserToManagerList(users)
+}
+
+// DeleteUserFromManagerList deletes users from the manager list.
+func (q *CachingQConf) DeleteUserFromManagerList(users []string) error {
+	defer q.Refresh(KindManager)
+	return q.qc.DeleteUserFromManagerList(users)
+}
+
+// ShowManagers shows the manager list.
+func (q *CachingQConf) ShowManagers() ([]string, error) {
+	return cached(q, KindManager, "ShowManagers", "", func() ([]string, error) {
+		return q.qc.ShowManagers()
+	})
+}
+
+// AddUserToOperatorList adds users to the operator list.
+func (q *CachingQConf) AddUserToOperatorList(users []string) error {
+	defer q.Refresh(KindOperator)
+	return q.qc.AddUserToOperatorList(users)
+}
+
+// DeleteUserFromOperatorList deletes users from the operator list.
+func (q *CachingQConf) DeleteUserFromOperatorList(users []string) error {
+	defer q.Refresh(KindOperator)
+	return q.qc.DeleteUserFromOperatorList(users)
+}
+
+// ShowOperators shows the operator list.
+func (q *CachingQConf) ShowOperators() ([]string, error) {
+	return cached(q, KindOperator, "ShowOperators", "", func() ([]string, error) {
+		return q.qc.ShowOperators()
+	})
+}
+
+// AddParallelEnvironment adds a new parallel environment.
+func (q *CachingQConf) AddParallelEnvironment(pe ParallelEnvironmentConfig) error {
+	defer q.Refresh(KindParallelEnvironment)
+	return q.qc.AddParallelEnvironment(pe)
+}
+
+// DeleteParallelEnvironment deletes a parallel environment.
+func (q *CachingQConf) DeleteParallelEnvironment(peName string) error {
+	defer q.Refresh(KindParallelEnvironment)
+	return q.qc.DeleteParallelEnvironment(peName)
+}
+
+// ShowParallelEnvironment shows the specified parallel environment.
+func (q *CachingQConf) ShowParallelEnvironment(peName string) (ParallelEnvironmentConfig, error) {
+	return cached(q, KindParallelEnvironment, "ShowParallelEnvironment", peName, func() (ParallelEnvironmentConfig, error) {
+		return q.qc.ShowParallelEnvironment(peName)
+	})
+}
+
+// ShowParallelEnvironments shows all parallel environments.
+func (q *CachingQConf) ShowParallelEnvironments() ([]string, error) {
+	return cached(q, KindParallelEnvironment, "ShowParallelEnvironments", "", func() ([]string, error) {
+		return q.qc.ShowParallelEnvironments()
+	})
+}
+
+// ModifyParallelEnvironment modifies a parallel environment.
+func (q *CachingQConf) ModifyParallelEnvironment(peName string, pe ParallelEnvironmentConfig) error {
+	defer q.Refresh(KindParallelEnvironment)
+	return q.qc.ModifyParallelEnvironment(peName, pe)
+}
+
+// AddProject adds a new project.
+func (q *CachingQConf) AddProject(project ProjectConfig) error {
+	defer q.Refresh(KindProject)
+	return q.qc.AddProject(project)
+}
+
+// DeleteProject deletes projects.
+func (q *CachingQConf) DeleteProject(projects []string) error {
+	defer q.Refresh(KindProject)
+	return q.qc.DeleteProject(projects)
+}
+
+// ShowProject shows the specified project.
+func (q *CachingQConf) ShowProject(projectName string) (ProjectConfig, error) {
+	return cached(q, KindProject, "ShowProject", projectName, func() (ProjectConfig, error) {
+		return q.qc.ShowProject(projectName)
+	})
+}
+
+// ShowProjects shows all projects.
+func (q *CachingQConf) ShowProjects() ([]string, error) {
+	return cached(q, KindProject, "ShowProjects", "", func() ([]string, error) {
+		return q.qc.ShowProjects()
+	})
+}
+
+// ModifyProject modifies a project.
+func (q *CachingQConf) ModifyProject(projectName string, p ProjectConfig) error {
+	defer q.Refresh(KindProject)
+	return q.qc.ModifyProject(projectName, p)
+}
+
+// AddClusterQueue adds a new cluster queue.
+func (q *CachingQConf) AddClusterQueue(queue ClusterQueueConfig) error {
+	defer q.Refresh(KindClusterQueue)
+	return q.qc.AddClusterQueue(queue)
+}
+
+// ModifyClusterQueue modifies a cluster queue.
+func (q *CachingQConf) ModifyClusterQueue(queueName string, cfg ClusterQueueConfig) error {
+	defer q.Refresh(KindClusterQueue)
+	return q.qc.ModifyClusterQueue(queueName, cfg)
+}
+
+// DeleteClusterQueue deletes a cluster queue.
+func (q *CachingQConf) DeleteClusterQueue(queueName string) error {
+	defer q.Refresh(KindClusterQueue)
+	return q.qc.DeleteClusterQueue(queueName)
+}
+
+// ShowClusterQueue shows the specified cluster queue.
+func (q *CachingQConf) ShowClusterQueue(queueName string) (ClusterQueueConfig, error) {
+	return cached(q, KindClusterQueue, "ShowClusterQueue", queueName, func() (ClusterQueueConfig, error) {
+		return q.qc.ShowClusterQueue(queueName)
+	})
+}
+
+// ShowClusterQueues shows all cluster queues.
+func (q *CachingQConf) ShowClusterQueues() ([]string, error) {
+	return cached(q, KindClusterQueue, "ShowClusterQueues", "", func() ([]string, error) {
+		return q.qc.ShowClusterQueues()
+	})
+}
+
+// AddSubmitHosts adds submit hosts.
+func (q *CachingQConf) AddSubmitHosts(hostnames []string) error {
+	defer q.Refresh(KindSubmitHost)
+	return q.qc.AddSubmitHosts(hostnames)
+}
+
+// DeleteSubmitHost deletes submit hosts.
+func (q *CachingQConf) DeleteSubmitHost(hostnames []string) error {
+	defer q.Refresh(KindSubmitHost)
+	return q.qc.DeleteSubmitHost(hostnames)
+}
+
+// ShowSubmitHosts shows all submit hosts.
+func (q *CachingQConf) ShowSubmitHosts() ([]string, error) {
+	return cached(q, KindSubmitHost, "ShowSubmitHosts", "", func() ([]string, error) {
+		return q.qc.ShowSubmitHosts()
+	})
+}
+
+// ModifyShareTreeNodes calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ModifyShareTreeNodes(nodeShareList []ShareTreeNode) error {
+	return q.qc.ModifyShareTreeNodes(nodeShareList)
+}
+
+// DeleteShareTreeNodes calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) DeleteShareTreeNodes(nodeList []string) error {
+	return q.qc.DeleteShareTreeNodes(nodeList)
+}
+
+// AddShareTreeNode calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) AddShareTreeNode(node ShareTreeNode) error {
+	return q.qc.AddShareTreeNode(node)
+}
+
+// ShowShareTreeNodes calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShowShareTreeNodes(nodeList []string) ([]ShareTreeNode, error) {
+	return q.qc.ShowShareTreeNodes(nodeList)
+}
+
+// ShowShareTree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShowShareTree() (string, error) {
+	return q.qc.ShowShareTree()
+}
+
+// ModifyShareTree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ModifyShareTree(shareTreeConfig string) error {
+	return q.qc.ModifyShareTree(shareTreeConfig)
+}
+
+// DeleteShareTree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) DeleteShareTree() error {
+	return q.qc.DeleteShareTree()
+}
+
+// ClearShareTreeUsage calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ClearShareTreeUsage() error {
+	return q.qc.ClearShareTreeUsage()
+}
+
+// ShowShareTreeStructured calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShowShareTreeStructured() (*StructuredShareTree, error) {
+	return q.qc.ShowShareTreeStructured()
+}
+
+// ModifyShareTreeStructured calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ModifyShareTreeStructured(t *StructuredShareTree) error {
+	return q.qc.ModifyShareTreeStructured(t)
+}
+
+// ShowShareTreeMonitoring calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShowShareTreeMonitoring() (*ShareTreeMonitoring, error) {
+	return q.qc.ShowShareTreeMonitoring()
+}
+
+// ShowShareTreeSubtree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShowShareTreeSubtree(path string) (*StructuredShareTreeNode, error) {
+	return q.qc.ShowShareTreeSubtree(path)
+}
+
+// ModifyShareTreeSubtree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ModifyShareTreeSubtree(path string, sub *StructuredShareTreeNode) error {
+	return q.qc.ModifyShareTreeSubtree(path, sub)
+}
+
+// AddShareTreeSubtree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) AddShareTreeSubtree(parentPath string, sub *StructuredShareTreeNode) error {
+	return q.qc.AddShareTreeSubtree(parentPath, sub)
+}
+
+// DeleteShareTreeSubtree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) DeleteShareTreeSubtree(path string) error {
+	return q.qc.DeleteShareTreeSubtree(path)
+}
+
+// MoveShareTreeSubtree calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) MoveShareTreeSubtree(srcPath, destParentPath string) error {
+	return q.qc.MoveShareTreeSubtree(srcPath, destParentPath)
+}
+
+// ApplyShareTreeBatch calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ApplyShareTreeBatch(ops []SubtreeOp) error {
+	return q.qc.ApplyShareTreeBatch(ops)
+}
+
+// AddUserSetList adds a new user set list.
+func (q *CachingQConf) AddUserSetList(listnameList string, u UserSetListConfig) error {
+	defer q.Refresh(KindUserSetList)
+	return q.qc.AddUserSetList(listnameList, u)
+}
+
+// AddUserToUserSetList adds users to user set lists.
+func (q *CachingQConf) AddUserToUserSetList(userList, listnameList string) error {
+	defer q.Refresh(KindUserSetList)
+	return q.qc.AddUserToUserSetList(userList, listnameList)
+}
+
+// DeleteUserFromUserSetList deletes users from user set lists.
+func (q *CachingQConf) DeleteUserFromUserSetList(userList, listnameList string) error {
+	defer q.Refresh(KindUserSetList)
+	return q.qc.DeleteUserFromUserSetList(userList, listnameList)
+}
+
+// DeleteUserSetList deletes a comma-separated list of user set lists.
+func (q *CachingQConf) DeleteUserSetList(userList string) error {
+	defer q.Refresh(KindUserSetList)
+	return q.qc.DeleteUserSetList(userList)
+}
+
+// ShowUserSetList shows the specified user set list.
+func (q *CachingQConf) ShowUserSetList(listnameList string) (UserSetListConfig, error) {
+	return cached(q, KindUserSetList, "ShowUserSetList", listnameList, func() (UserSetListConfig, error) {
+		return q.qc.ShowUserSetList(listnameList)
+	})
+}
+
+// ShowUserSetLists shows all user set lists.
+func (q *CachingQConf) ShowUserSetLists() ([]string, error) {
+	return cached(q, KindUserSetList, "ShowUserSetLists", "", func() ([]string, error) {
+		return q.qc.ShowUserSetLists()
+	})
+}
+
+// ModifyUserset modifies a user set list.
+func (q *CachingQConf) ModifyUserset(listnameList string, u UserSetListConfig) error {
+	defer q.Refresh(KindUserSetList)
+	return q.qc.ModifyUserset(listnameList, u)
+}
+
+// AddUser adds a new user.
+func (q *CachingQConf) AddUser(userConfig UserConfig) error {
+	defer q.Refresh(KindUser)
+	return q.qc.AddUser(userConfig)
+}
+
+// DeleteUser deletes users.
+func (q *CachingQConf) DeleteUser(users []string) error {
+	defer q.Refresh(KindUser)
+	return q.qc.DeleteUser(users)
+}
+
+// ShowUser shows the specified user.
+func (q *CachingQConf) ShowUser(userName string) (UserConfig, error) {
+	return cached(q, KindUser, "ShowUser", userName, func() (UserConfig, error) {
+		return q.qc.ShowUser(userName)
+	})
+}
+
+// ShowUsers shows all users.
+func (q *CachingQConf) ShowUsers() ([]string, error) {
+	return cached(q, KindUser, "ShowUsers", "", func() ([]string, error) {
+		return q.qc.ShowUsers()
+	})
+}
+
+// ModifyUser modifies a user.
+func (q *CachingQConf) ModifyUser(userName string, u UserConfig) error {
+	defer q.Refresh(KindUser)
+	return q.qc.ModifyUser(userName, u)
+}
+
+// CleanQueue calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) CleanQueue(destinID []string) error {
+	return q.qc.CleanQueue(destinID)
+}
+
+// ShutdownExecDaemons calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShutdownExecDaemons(hosts []string) error {
+	return q.qc.ShutdownExecDaemons(hosts)
+}
+
+// ShutdownMasterDaemon calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShutdownMasterDaemon() error {
+	return q.qc.ShutdownMasterDaemon()
+}
+
+// ShutdownSchedulingDaemon calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) ShutdownSchedulingDaemon() error {
+	return q.qc.ShutdownSchedulingDaemon()
+}
+
+// KillEventClient calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) KillEventClient(evids []string) error {
+	return q.qc.KillEventClient(evids)
+}
+
+// KillQmasterThread calls the wrapped QConf; it is not cached.
+func (q *CachingQConf) KillQmasterThread(threadName string) error {
+	return q.qc.KillQmasterThread(threadName)
+}
+
+// ModifySchedulerConfig modifies the scheduler configuration.
+func (q *CachingQConf) ModifySchedulerConfig(cfg SchedulerConfig) error {
+	defer q.Refresh(KindSchedulerConfig)
+	return q.qc.ModifySchedulerConfig(cfg)
+}
+
+// ShowSchedulerConfiguration shows the scheduler configuration.
+func (q *CachingQConf) ShowSchedulerConfiguration() (*SchedulerConfig, error) {
+	return cached(q, KindSchedulerConfig, "ShowSchedulerConfiguration", "", func() (*SchedulerConfig, error) {
+		return q.qc.ShowSchedulerConfiguration()
+	})
+}
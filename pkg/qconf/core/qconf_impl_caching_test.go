@@ -0,0 +1,227 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+// countingQConf counts the reads which reach the wrapped QConf.
+type countingQConf struct {
+	core.QConf
+	hostGroupReads     int
+	clusterConfigReads int
+	failHostGroup      bool
+}
+
+func (c *countingQConf) ShowHostGroup(groupName string) (core.HostGroupConfig, error) {
+	c.hostGroupReads++
+	if c.failHostGroup {
+		return core.HostGroupConfig{}, errors.New("qmaster not reachable")
+	}
+	return c.QConf.ShowHostGroup(groupName)
+}
+
+func (c *countingQConf) GetClusterConfiguration() (core.ClusterConfig, error) {
+	c.clusterConfigReads++
+	return c.QConf.GetClusterConfiguration()
+}
+
+var _ = Describe("CachingQConf", func() {
+
+	var (
+		counting *countingQConf
+		qc       *core.CachingQConf
+	)
+
+	newCaching := func(config core.CachingQConfConfig) *core.CachingQConf {
+		return core.NewCachingQConf(counting, config)
+	}
+
+	BeforeEach(func() {
+		counting = &countingQConf{
+			QConf: newInMemoryQConf(core.ClusterConfig{
+				HostGroups: map[string]core.HostGroupConfig{
+					"@rack1": {Name: "@rack1", Hosts: []string{"node1", "node2"}},
+				},
+			}),
+		}
+		qc = newCaching(core.CachingQConfConfig{TTL: time.Hour})
+	})
+
+	It("implements the QConf interface", func() {
+		var _ core.QConf = qc
+	})
+
+	It("serves repeated reads from the cache", func() {
+		for i := 0; i < 3; i++ {
+			hg, err := qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hg.Hosts).To(Equal([]string{"node1", "node2"}))
+		}
+		Expect(counting.hostGroupReads).To(Equal(1))
+	})
+
+	It("returns copies which callers can modify", func() {
+		hg, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		hg.Hosts[0] = "changed"
+
+		hg, err = qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(Equal([]string{"node1", "node2"}))
+	})
+
+	It("invalidates the kind on modifications made through it", func() {
+		_, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		_, err = qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+
+		err = qc.ModifyHostGroup("@rack1", core.HostGroupConfig{
+			Name: "@rack1", Hosts: []string{"node3"}})
+		Expect(err).NotTo(HaveOccurred())
+
+		hg, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(Equal([]string{"node3"}))
+		Expect(counting.hostGroupReads).To(Equal(2))
+
+		cc, err := qc.GetClusterConfiguration()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cc.HostGroups["@rack1"].Hosts).To(Equal([]string{"node3"}))
+		Expect(counting.clusterConfigReads).To(Equal(2))
+	})
+
+	It("keeps other kinds cached on modifications", func() {
+		_, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qc.AddCalendar(core.CalendarConfig{Name: "night"})).To(Succeed())
+		_, err = qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counting.hostGroupReads).To(Equal(1))
+	})
+
+	It("invalidates the kind of attribute modifications", func() {
+		_, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qc.AddAttribute("hostgroup", "hostlist", "node3", "@rack1")).To(Succeed())
+
+		hg, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(ContainElement("node3"))
+		Expect(counting.hostGroupReads).To(Equal(2))
+	})
+
+	It("reads again after an explicit refresh", func() {
+		_, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		qc.Refresh(core.KindHostGroup)
+		_, err = qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counting.hostGroupReads).To(Equal(2))
+	})
+
+	It("reads again after the TTL expired", func() {
+		qc = newCaching(core.CachingQConfConfig{TTL: 10 * time.Millisecond})
+		_, err := qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() int {
+			_, err := qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			return counting.hostGroupReads
+		}).Should(BeNumerically(">", 1))
+	})
+
+	It("does not cache kinds with a zero TTL", func() {
+		qc = newCaching(core.CachingQConfConfig{
+			TTL:     time.Hour,
+			KindTTL: map[core.ObjectKind]time.Duration{core.KindHostGroup: 0},
+		})
+		for i := 0; i < 2; i++ {
+			_, err := qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = qc.GetClusterConfiguration()
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(counting.hostGroupReads).To(Equal(2))
+		Expect(counting.clusterConfigReads).To(Equal(2))
+	})
+
+	It("shares the cache with the copies bound to a context", func() {
+		f := newFakeQConf("group_name @rack1\nhostlist node1 node2\n", 0)
+		defer f.Cleanup()
+		base := core.NewCachingQConf(newQConfWith(f), core.CachingQConfConfig{TTL: time.Hour})
+		_, err := base.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.AllArgvLines()).To(HaveLen(1))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		bound := base.WithContext(ctx)
+		hg, err := bound.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(Equal([]string{"node1", "node2"}))
+		_, err = bound.ShowHostGroup("@rack2")
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(f.AllArgvLines()).To(HaveLen(1))
+
+		bound.Refresh(core.KindHostGroup)
+		_, err = base.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(f.AllArgvLines()).To(HaveLen(2))
+	})
+
+	It("replaces objects as a whole when the wrapped QConf cannot purge", func() {
+		mem := newInMemoryQConf(core.ClusterConfig{
+			ClusterQueues: map[string]core.ClusterQueueConfig{
+				"all.q": {Name: "all.q", Slots: []string{"1", "[node1=4]"}},
+			},
+		})
+		plain := core.NewCachingQConf(struct{ core.QConf }{mem}, core.CachingQConfConfig{TTL: time.Hour})
+		current, err := plain.ShowClusterQueue("all.q")
+		Expect(err).NotTo(HaveOccurred())
+		desired := current
+		desired.Slots = []string{"2"}
+
+		Expect(core.ModifyFields(plain, core.KindClusterQueue, "all.q", current, desired)).To(Succeed())
+		q, err := plain.ShowClusterQueue("all.q")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(q.Slots).To(Equal([]string{"2"}))
+	})
+
+	It("does not cache errors", func() {
+		counting.failHostGroup = true
+		_, err := qc.ShowHostGroup("@rack1")
+		Expect(err).To(HaveOccurred())
+		counting.failHostGroup = false
+		_, err = qc.ShowHostGroup("@rack1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counting.hostGroupReads).To(Equal(2))
+	})
+
+})
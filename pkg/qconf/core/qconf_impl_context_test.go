@@ -0,0 +1,98 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("CommandLineQConf WithContext", func() {
+
+	It("does not modify the original instance", func() {
+		qc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{})
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		bound := qc.WithContext(ctx)
+		Expect(bound.Context()).To(Equal(ctx))
+		Expect(qc.Context()).To(Equal(context.Background()))
+	})
+
+	It("binds a QConf which supports contexts", func() {
+		cqc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var qc core.QConf = cqc
+		bound, ok := core.WithContext(qc, ctx).(*core.CommandLineQConf)
+		Expect(ok).To(BeTrue())
+		Expect(bound.Context()).To(Equal(ctx))
+
+		var mem core.QConf = newInMemoryQConf(core.ClusterConfig{})
+		Expect(core.WithContext(mem, ctx)).To(BeIdenticalTo(mem))
+		Expect(core.WithContext[core.QConf](nil, ctx)).To(BeNil())
+	})
+
+	It("does not spawn qconf when the context is already cancelled", func() {
+		f := newFakeQConf("", 0)
+		defer f.Cleanup()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		qc := newQConfWith(f).WithContext(ctx)
+
+		_, err := qc.ShowCalendars()
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(f.AllArgvLines()).To(BeEmpty())
+
+		_, err = qc.GetClusterConfiguration()
+		Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+		Expect(f.AllArgvLines()).To(BeEmpty())
+	})
+
+	It("kills a running command when the deadline is reached", func() {
+		sleep, err := exec.LookPath("sleep")
+		if err != nil {
+			Skip("sleep binary not available")
+		}
+		qc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{
+			Executable: sleep,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		_, err = qc.WithContext(ctx).RunCommand("10")
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+})
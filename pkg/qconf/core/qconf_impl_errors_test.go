@@ -0,0 +1,85 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("qconf errors", func() {
+
+	It("classifies the output of a failing qconf command", func() {
+		f := newFakeQConf("cluster queue \"foo.q\" does not exist\n", 1)
+		defer f.Cleanup()
+
+		_, err := newQConfWith(f).ShowClusterQueue("foo.q")
+		Expect(errors.Is(err, qerror.ErrNotExist)).To(BeTrue())
+		Expect(errors.Is(err, qerror.ErrAlreadyExists)).To(BeFalse())
+
+		var qerr *qerror.QconfError
+		Expect(errors.As(err, &qerr)).To(BeTrue())
+		Expect(qerr.Object).To(Equal("cluster queue"))
+		Expect(qerr.Name).To(Equal("foo.q"))
+		Expect(qerr.ExitCode).To(Equal(1))
+	})
+
+	It("reports a missing user although qconf exits with 0", func() {
+		f := newFakeQConf("bob is not known as user\n", 0)
+		defer f.Cleanup()
+
+		_, err := newQConfWith(f).ShowUser("bob")
+		Expect(errors.Is(err, qerror.ErrNotExist)).To(BeTrue())
+	})
+
+	It("keeps the classified error when adding an existing exec host", func() {
+		f := newFakeQConf("host \"node1\" already exists\n", 1)
+		defer f.Cleanup()
+
+		err := newQConfWith(f).AddExecHost(core.HostExecConfig{Name: "node1"})
+		Expect(errors.Is(err, qerror.ErrAlreadyExists)).To(BeTrue())
+		Expect(err.Error()).To(HavePrefix("failed to add exechost: "))
+		Expect(strings.Count(err.Error(), "already exists")).To(Equal(1))
+	})
+
+	It("returns the same errors from the in-memory implementation", func() {
+		qc := newInMemoryQConf(core.ClusterConfig{})
+
+		_, err := qc.ShowClusterQueue("foo.q")
+		Expect(errors.Is(err, qerror.ErrNotExist)).To(BeTrue())
+		var qerr *qerror.QconfError
+		Expect(errors.As(err, &qerr)).To(BeTrue())
+		Expect(qerr.Name).To(Equal("foo.q"))
+
+		hg := core.HostGroupConfig{Name: "@rack1", Hosts: []string{"node1"}}
+		Expect(qc.AddHostGroup(hg)).To(Succeed())
+		Expect(errors.Is(qc.AddHostGroup(hg), qerror.ErrAlreadyExists)).To(BeTrue())
+
+		_, err = qc.ShowUser("bob")
+		Expect(errors.Is(err, qerror.ErrNotExist)).To(BeTrue())
+	})
+
+})
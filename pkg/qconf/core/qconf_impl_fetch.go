@@ -0,0 +1,194 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ObjectError is the failure to read a single configuration object
+// while assembling a ClusterConfig.
+type ObjectError struct {
+	// Kind is the object kind, like "cluster queue" or "project".
+	Kind string
+	// Name is the name of the object which could not be read.
+	Name string
+	Err  error
+}
+
+func (e *ObjectError) Error() string {
+	return fmt.Sprintf("failed to read %s %s: %v", e.Kind, e.Name, e.Err)
+}
+
+func (e *ObjectError) Unwrap() error {
+	return e.Err
+}
+
+// ClusterConfigError is returned by GetClusterConfiguration when one or
+// more objects could not be read. All other objects are still part of
+// the returned ClusterConfig.
+type ClusterConfigError struct {
+	Errors []*ObjectError
+}
+
+func (e *ClusterConfigError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, oe := range e.Errors {
+		msgs = append(msgs, oe.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Unwrap makes errors.Is and errors.As look into every object error.
+func (e *ClusterConfigError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, oe := range e.Errors {
+		errs = append(errs, oe)
+	}
+	return errs
+}
+
+// commandLimiter bounds the qconf processes of a CommandLineQConf: at
+// most cap(slots) run at the same time and consecutive starts are at
+// least interval apart. It is shared by all copies made by WithContext,
+// so the bounds hold per qmaster rather than per request.
+type commandLimiter struct {
+	// slots is a counting semaphore; nil when concurrency is unbounded.
+	slots    chan struct{}
+	interval time.Duration
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+// newCommandLimiter returns nil when neither bound is configured.
+func newCommandLimiter(concurrency int, rateLimit float64) *commandLimiter {
+	if concurrency <= 0 && rateLimit <= 0 {
+		return nil
+	}
+	l := &commandLimiter{}
+	if concurrency > 0 {
+		l.slots = make(chan struct{}, concurrency)
+	}
+	if rateLimit > 0 {
+		l.interval = time.Duration(float64(time.Second) / rateLimit)
+	}
+	return l
+}
+
+// acquire blocks until a command may be started. The returned release
+// function must be called once the command has finished.
+func (l *commandLimiter) acquire(ctx context.Context) (func(), error) {
+	if l.slots != nil {
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	release := func() {
+		if l.slots != nil {
+			<-l.slots
+		}
+	}
+	if l.interval <= 0 {
+		return release, nil
+	}
+
+	// Reserve the next start time; waiting happens outside the lock so
+	// that concurrent callers queue up one interval apart.
+	l.mu.Lock()
+	start := time.Now()
+	if l.next.After(start) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// fetchObjects calls show for each name, using up to Concurrency workers,
+// and returns the objects keyed by name. Failures are returned as one
+// ObjectError per object, in the order of names, so the result does not
+// depend on scheduling. Names not attempted because the context was
+// cancelled are reported with the context error.
+func fetchObjects[T any](c *CommandLineQConf, kind string, names []string,
+	show func(name string) (T, error)) (map[string]T, []*ObjectError) {
+
+	results := make([]T, len(names))
+	errs := make([]error, len(names))
+
+	workers := min(c.config.Concurrency, len(names))
+	if workers < 1 {
+		workers = 1
+	}
+	ctx := c.Context()
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i], errs[i] = show(names[i])
+			}
+		}()
+	}
+	fed := 0
+feed:
+	for ; fed < len(names); fed++ {
+		select {
+		case next <- fed:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+	for i := fed; i < len(names); i++ {
+		errs[i] = ctx.Err()
+	}
+
+	objects := make(map[string]T, len(names))
+	var objErrs []*ObjectError
+	for i, name := range names {
+		if errs[i] != nil {
+			objErrs = append(objErrs, &ObjectError{Kind: kind, Name: name, Err: errs[i]})
+			continue
+		}
+		objects[name] = results[i]
+	}
+	return objects, objErrs
+}
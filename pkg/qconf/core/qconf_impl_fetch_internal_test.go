@@ -0,0 +1,156 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("fetchObjects", func() {
+
+	names := []string{"a", "b", "c", "d", "e", "f", "g", "h"}
+
+	show := func(name string) (string, error) {
+		if name == "c" || name == "f" {
+			return "", fmt.Errorf("%s is broken", name)
+		}
+		return "obj-" + name, nil
+	}
+
+	for _, concurrency := range []int{0, 1, 4, 16} {
+		It(fmt.Sprintf("returns the same result with concurrency %d", concurrency), func() {
+			c := &CommandLineQConf{config: CommandLineQConfConfig{Concurrency: concurrency}}
+			objects, errs := fetchObjects(c, "thing", names, show)
+			Expect(objects).To(HaveLen(6))
+			Expect(objects["a"]).To(Equal("obj-a"))
+			Expect(objects).NotTo(HaveKey("c"))
+			Expect(errs).To(HaveLen(2))
+			Expect(errs[0].Name).To(Equal("c"))
+			Expect(errs[1].Name).To(Equal("f"))
+			Expect(errs[1].Error()).To(Equal("failed to read thing f: f is broken"))
+		})
+	}
+
+	It("runs up to Concurrency calls at the same time", func() {
+		c := &CommandLineQConf{config: CommandLineQConfConfig{Concurrency: 3}}
+		var running, peak int32
+		_, errs := fetchObjects(c, "thing", names, func(name string) (string, error) {
+			n := atomic.AddInt32(&running, 1)
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return name, nil
+		})
+		Expect(errs).To(BeEmpty())
+		Expect(peak).To(BeEquivalentTo(3))
+	})
+
+	It("reports names not attempted after cancellation", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		c := (&CommandLineQConf{}).WithContext(ctx)
+		objects, errs := fetchObjects(c, "thing", names, func(name string) (string, error) {
+			cancel()
+			return name, nil
+		})
+		Expect(objects).To(HaveKey("a"))
+		Expect(errs).NotTo(BeEmpty())
+		Expect(errors.Is(errs[len(errs)-1], context.Canceled)).To(BeTrue())
+	})
+
+	It("aggregates object errors for errors.Is", func() {
+		err := error(&ClusterConfigError{Errors: []*ObjectError{
+			{Kind: "project", Name: "p1", Err: ErrNoModification},
+		}})
+		Expect(errors.Is(err, ErrNoModification)).To(BeTrue())
+		var oe *ObjectError
+		Expect(errors.As(err, &oe)).To(BeTrue())
+		Expect(oe.Name).To(Equal("p1"))
+	})
+})
+
+var _ = Describe("commandLimiter", func() {
+
+	It("is not created without bounds", func() {
+		Expect(newCommandLimiter(0, 0)).To(BeNil())
+	})
+
+	It("caps the number of concurrent holders", func() {
+		l := newCommandLimiter(2, 0)
+		var running, peak int32
+		var wg sync.WaitGroup
+		for range 6 {
+			wg.Add(1)
+			go func() {
+				defer GinkgoRecover()
+				defer wg.Done()
+				release, err := l.acquire(context.Background())
+				Expect(err).NotTo(HaveOccurred())
+				n := atomic.AddInt32(&running, 1)
+				for {
+					p := atomic.LoadInt32(&peak)
+					if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+						break
+					}
+				}
+				time.Sleep(10 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				release()
+			}()
+		}
+		wg.Wait()
+		Expect(peak).To(BeNumerically("<=", 2))
+	})
+
+	It("spaces out starts according to the rate limit", func() {
+		l := newCommandLimiter(0, 50) // one start every 20ms
+		start := time.Now()
+		for range 4 {
+			release, err := l.acquire(context.Background())
+			Expect(err).NotTo(HaveOccurred())
+			release()
+		}
+		Expect(time.Since(start)).To(BeNumerically(">=", 60*time.Millisecond))
+	})
+
+	It("gives up waiting when the context is cancelled", func() {
+		l := newCommandLimiter(1, 0)
+		release, err := l.acquire(context.Background())
+		Expect(err).NotTo(HaveOccurred())
+		defer release()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		_, err = l.acquire(ctx)
+		Expect(errors.Is(err, context.DeadlineExceeded)).To(BeTrue())
+	})
+})
@@ -0,0 +1,108 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core/internal/fakeqconf"
+)
+
+// clusterResponses is a small cluster: twelve projects of which p7 cannot
+// be read, three host groups, one admin host, a manager and an operator.
+func clusterResponses() map[string]fakeqconf.Response {
+	responses := map[string]fakeqconf.Response{
+		"-sconf global": {Stdout: "execd_spool_dir /var/spool\n"},
+		"-ssconf":       {Stdout: "algorithm default\n"},
+		"-sc":           {Stdout: "#name shortcut type relop requestable consumable default urgency\n"},
+		"-shgrpl":       {Stdout: "@a\n@b\n@c\n"},
+		"-sh":           {Stdout: "master\n"},
+		"-sm":           {Stdout: "root\n"},
+		"-so":           {Stdout: "root\n"},
+	}
+	var projects []string
+	for i := 1; i <= 12; i++ {
+		name := fmt.Sprintf("p%d", i)
+		projects = append(projects, name)
+		responses["-sprj "+name] = fakeqconf.Response{Stdout: fmt.Sprintf(
+			"name %s\noticket %d\nfshare %d\nacl NONE\nxacl NONE\n", name, i, i*10)}
+	}
+	responses["-sprjl"] = fakeqconf.Response{Stdout: strings.Join(projects, "\n") + "\n"}
+	responses["-sprj p7"] = fakeqconf.Response{Stdout: "error: p7 is broken\n", RC: 1}
+	for _, hg := range []string{"@a", "@b", "@c"} {
+		responses["-shgrp "+hg] = fakeqconf.Response{
+			Stdout: fmt.Sprintf("group_name %s\nhostlist host%s\n", hg, hg[1:])}
+	}
+	return responses
+}
+
+var _ = Describe("CommandLineQConf GetClusterConfiguration", func() {
+
+	getConfig := func(concurrency int) (core.ClusterConfig, error, []string) {
+		if !fakeqconf.Available() {
+			Skip("fakeqconf uses a bash script; skip on this platform")
+		}
+		f := fakeqconf.NewDispatch(GinkgoT(), clusterResponses(),
+			fakeqconf.Response{Stdout: "no object defined\n", RC: 1})
+		defer f.Cleanup()
+		qc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{
+			Executable:  f.Path(),
+			Concurrency: concurrency,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		cc, err := qc.GetClusterConfiguration()
+		return cc, err, f.AllArgvLines()
+	}
+
+	It("returns the same configuration when reading in parallel", func() {
+		sequential, seqErr, seqCalls := getConfig(0)
+		parallel, parErr, parCalls := getConfig(8)
+
+		Expect(parallel).To(Equal(sequential))
+		Expect(parErr).To(Equal(seqErr))
+		Expect(parCalls).To(ConsistOf(seqCalls))
+
+		Expect(sequential.Projects).To(HaveLen(11))
+		Expect(sequential.Projects["p12"].FShare).To(Equal(120))
+		Expect(sequential.HostGroups["@b"].Hosts).To(Equal([]string{"hostb"}))
+	})
+
+	It("reports every object which could not be read", func() {
+		_, err, _ := getConfig(4)
+		var ccErr *core.ClusterConfigError
+		Expect(errors.As(err, &ccErr)).To(BeTrue())
+		Expect(ccErr.Errors).To(HaveLen(1))
+		Expect(ccErr.Errors[0].Kind).To(Equal("project"))
+		Expect(ccErr.Errors[0].Name).To(Equal("p7"))
+	})
+
+	It("rejects negative bounds", func() {
+		_, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{Concurrency: -1})
+		Expect(err).To(HaveOccurred())
+		_, err = core.NewCommandLineQConf(core.CommandLineQConfConfig{RateLimit: -1})
+		Expect(err).To(HaveOccurred())
+	})
+})
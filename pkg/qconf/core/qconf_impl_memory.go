@@ -0,0 +1,1269 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
+)
+
+// InMemoryQConf is a QConf implementation which keeps the cluster
+// configuration and the share tree in process memory instead of talking
+// to qmaster. It is meant for tests and offline tooling: Apply,
+// AddAllEntries, ModifyAllEntries and DeleteAllEnries can be run against
+// it without a cluster.
+//
+// Errors follow CommandLineQConf. Showing, modifying or deleting an
+// object which does not exist, and adding one which already exists,
+// returns the same *qerror.QconfError RunCommand produces for a failing
+// qconf call, with qmaster's "does not exist" or "already exists"
+// message inside. The same SetDefault* functions are applied on the
+// write path, so a show after an add returns what a cluster would.
+//
+// All values passed in and handed out are deep copies; callers cannot
+// change the stored configuration except through the QConf methods.
+// InMemoryQConf is safe for concurrent use.
+type InMemoryQConf struct {
+	mu        sync.Mutex
+	cc        ClusterConfig
+	shareTree *StructuredShareTree
+	version   ClusterSchedulerVersion
+}
+
+// InMemoryQConfConfig holds the initial state of an InMemoryQConf.
+type InMemoryQConfConfig struct {
+	// ClusterConfig is the initial cluster configuration. The zero value
+	// is an empty cluster.
+	ClusterConfig ClusterConfig
+	// ShareTree is the initial share tree. Nil means no share tree is
+	// configured.
+	ShareTree *StructuredShareTree
+	// Version is returned by GetVersion. When unset, Open Cluster
+	// Scheduler 9.0.0 is reported.
+	Version ClusterSchedulerVersion
+}
+
+// NewInMemoryQConf creates a new InMemoryQConf holding a copy of the
+// given configuration.
+func NewInMemoryQConf(config InMemoryQConfConfig) (*InMemoryQConf, error) {
+	q := &InMemoryQConf{
+		cc:      deepCopy(config.ClusterConfig),
+		version: config.Version,
+	}
+	if config.ShareTree != nil {
+		if config.ShareTree.Root == nil {
+			return nil, fmt.Errorf("share tree has no root")
+		}
+		q.shareTree = &StructuredShareTree{Root: CloneShareTreeSubtree(config.ShareTree.Root)}
+	}
+	if q.version.Version == "" {
+		q.version = ClusterSchedulerVersion{
+			Product: ClusterSchedulerProductOCS,
+			Version: "9.0.0",
+			Major:   9,
+		}
+	}
+	initClusterConfigMaps(&q.cc)
+	return q, nil
+}
+
+// initClusterConfigMaps allocates the object maps which are nil, matching
+// CommandLineQConf.GetClusterConfiguration which always returns non-nil
+// maps.
+func initClusterConfigMaps(cc *ClusterConfig) {
+	if cc.Calendars == nil {
+		cc.Calendars = make(map[string]CalendarConfig)
+	}
+	if cc.ComplexEntries == nil {
+		cc.ComplexEntries = make(map[string]ComplexEntryConfig)
+	}
+	if cc.CkptInterfaces == nil {
+		cc.CkptInterfaces = make(map[string]CkptInterfaceConfig)
+	}
+	if cc.HostConfigurations == nil {
+		cc.HostConfigurations = make(map[string]HostConfiguration)
+	}
+	if cc.ExecHosts == nil {
+		cc.ExecHosts = make(map[string]HostExecConfig)
+	}
+	if cc.HostGroups == nil {
+		cc.HostGroups = make(map[string]HostGroupConfig)
+	}
+	if cc.ResourceQuotaSets == nil {
+		cc.ResourceQuotaSets = make(map[string]ResourceQuotaSetConfig)
+	}
+	if cc.ParallelEnvironments == nil {
+		cc.ParallelEnvironments = make(map[string]ParallelEnvironmentConfig)
+	}
+	if cc.Projects == nil {
+		cc.Projects = make(map[string]ProjectConfig)
+	}
+	if cc.Users == nil {
+		cc.Users = make(map[string]UserConfig)
+	}
+	if cc.ClusterQueues == nil {
+		cc.ClusterQueues = make(map[string]ClusterQueueConfig)
+	}
+	if cc.UserSetLists == nil {
+		cc.UserSetLists = make(map[string]UserSetListConfig)
+	}
+}
+
+// memoryCommandError builds the error RunCommand returns when qconf
+// exits with status 1 after printing msg.
+func memoryCommandError(msg string) error {
+	e := qerror.New(msg+"\n", errors.New("exit status 1"))
+	e.ExitCode = 1
+	return e
+}
+
+func errNotExist(kind, name string) error {
+	return memoryCommandError(fmt.Sprintf("%s \"%s\" does not exist", kind, name))
+}
+
+func errAlreadyExists(kind, name string) error {
+	return memoryCommandError(fmt.Sprintf("%s \"%s\" already exists", kind, name))
+}
+
+// deepCopy returns a copy of v which shares no pointers, slices or maps
+// with v. Nil slices and maps stay nil so that reflect.DeepEqual, which
+// CompareTo relies on, sees the copy as equal to the original.
+func deepCopy[T any](v T) T {
+	var out T
+	copyValue(reflect.ValueOf(&out).Elem(), reflect.ValueOf(&v).Elem())
+	return out
+}
+
+func copyValue(dst, src reflect.Value) {
+	switch src.Kind() {
+	case reflect.Pointer:
+		if src.IsNil() {
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		copyValue(p.Elem(), src.Elem())
+		dst.Set(p)
+	case reflect.Slice:
+		if src.IsNil() {
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		for i := 0; i < src.Len(); i++ {
+			copyValue(s.Index(i), src.Index(i))
+		}
+		dst.Set(s)
+	case reflect.Map:
+		if src.IsNil() {
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		iter := src.MapRange()
+		for iter.Next() {
+			v := reflect.New(src.Type().Elem()).Elem()
+			copyValue(v, iter.Value())
+			m.SetMapIndex(iter.Key(), v)
+		}
+		dst.Set(m)
+	case reflect.Struct:
+		for i := 0; i < src.NumField(); i++ {
+			copyValue(dst.Field(i), src.Field(i))
+		}
+	default:
+		dst.Set(src)
+	}
+}
+
+// sortedKeys returns the keys of m in ascending order. It never returns
+// nil, like the list show methods of CommandLineQConf for an empty list.
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// splitObjectList splits a comma-separated object list as accepted by
+// the qconf -d* and -s* list options.
+func splitObjectList(list string) []string {
+	var names []string
+	for _, n := range strings.Split(list, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			names = append(names, n)
+		}
+	}
+	return names
+}
+
+// addToList appends names to list. As with qconf, adding a name which
+// is already present fails; names before it have been added.
+func addToList(list []string, names []string, kind string) ([]string, error) {
+	for _, n := range names {
+		if slices.Contains(list, n) {
+			return list, errAlreadyExists(kind, n)
+		}
+		list = append(list, n)
+	}
+	return list, nil
+}
+
+// deleteFromList removes names from list. Removing a name which is not
+// present fails; names before it have been removed.
+func deleteFromList(list []string, names []string, kind string) ([]string, error) {
+	for _, n := range names {
+		i := slices.Index(list, n)
+		if i < 0 {
+			return list, errNotExist(kind, n)
+		}
+		list = slices.Delete(list, i, i+1)
+	}
+	if len(list) == 0 {
+		// Like qconf, an empty list reads back as no list at all.
+		return nil, nil
+	}
+	return list, nil
+}
+
+// GetVersion returns the configured version.
+func (q *InMemoryQConf) GetVersion() (ClusterSchedulerVersion, error) {
+	return q.version, nil
+}
+
+// GetClusterConfiguration returns a copy of the complete configuration.
+func (q *InMemoryQConf) GetClusterConfiguration() (ClusterConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return deepCopy(q.cc), nil
+}
+
+// ApplyClusterConfiguration replaces the complete configuration with cc.
+// The share tree is not part of a ClusterConfig and is left untouched.
+func (q *InMemoryQConf) ApplyClusterConfiguration(cc ClusterConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cc = deepCopy(cc)
+	initClusterConfigMaps(&q.cc)
+	return nil
+}
+
+// AddCalendar adds a new calendar.
+func (q *InMemoryQConf) AddCalendar(cfg CalendarConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("calendar name is required")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Calendars[cfg.Name]; exists {
+		return errAlreadyExists("calendar", cfg.Name)
+	}
+	q.cc.Calendars[cfg.Name] = deepCopy(cfg)
+	return nil
+}
+
+// DeleteCalendar deletes a calendar.
+func (q *InMemoryQConf) DeleteCalendar(calendarName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Calendars[calendarName]; !exists {
+		return errNotExist("calendar", calendarName)
+	}
+	delete(q.cc.Calendars, calendarName)
+	return nil
+}
+
+// ShowCalendar shows the specified calendar.
+func (q *InMemoryQConf) ShowCalendar(calendarName string) (CalendarConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cfg, exists := q.cc.Calendars[calendarName]
+	if !exists {
+		return CalendarConfig{}, errNotExist("calendar", calendarName)
+	}
+	return deepCopy(cfg), nil
+}
+
+// ShowCalendars shows all calendars.
+func (q *InMemoryQConf) ShowCalendars() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.Calendars), nil
+}
+
+// ModifyCalendar modifies a calendar.
+func (q *InMemoryQConf) ModifyCalendar(calendarName string, cfg CalendarConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Calendars[calendarName]; !exists {
+		return errNotExist("calendar", calendarName)
+	}
+	cfg.Name = calendarName
+	q.cc.Calendars[calendarName] = deepCopy(cfg)
+	return nil
+}
+
+// AddComplexEntry adds a new complex entry.
+func (q *InMemoryQConf) AddComplexEntry(e ComplexEntryConfig) error {
+	if e.Name == "" {
+		return fmt.Errorf("complex does not have a name")
+	}
+	if e.Type == "" {
+		return fmt.Errorf("complex does not have a type")
+	}
+	SetDefaultComplexEntryValues(&e)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ComplexEntries[e.Name]; exists {
+		return errAlreadyExists("complex attribute", e.Name)
+	}
+	q.cc.ComplexEntries[e.Name] = deepCopy(e)
+	return nil
+}
+
+// DeleteComplexEntry deletes a complex entry.
+func (q *InMemoryQConf) DeleteComplexEntry(entryName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ComplexEntries[entryName]; !exists {
+		return errNotExist("complex attribute", entryName)
+	}
+	delete(q.cc.ComplexEntries, entryName)
+	return nil
+}
+
+// ShowComplexEntry shows the specified complex entry.
+func (q *InMemoryQConf) ShowComplexEntry(entryName string) (ComplexEntryConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	e, exists := q.cc.ComplexEntries[entryName]
+	if !exists {
+		return ComplexEntryConfig{}, errNotExist("complex attribute", entryName)
+	}
+	return deepCopy(e), nil
+}
+
+// ShowComplexEntries shows the names of all complex entries.
+func (q *InMemoryQConf) ShowComplexEntries() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.ComplexEntries), nil
+}
+
+// ShowAllComplexes shows all complex entries sorted by name.
+func (q *InMemoryQConf) ShowAllComplexes() ([]ComplexEntryConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	entries := make([]ComplexEntryConfig, 0, len(q.cc.ComplexEntries))
+	for _, name := range sortedKeys(q.cc.ComplexEntries) {
+		entries = append(entries, deepCopy(q.cc.ComplexEntries[name]))
+	}
+	return entries, nil
+}
+
+// ModifyAllComplexes replaces the complete complex configuration, like
+// qconf -Mc: entries which are not in centries are removed.
+func (q *InMemoryQConf) ModifyAllComplexes(centries []ComplexEntryConfig) error {
+	if centries == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	complexes := make(map[string]ComplexEntryConfig, len(centries))
+	for _, e := range centries {
+		complexes[e.Name] = deepCopy(e)
+	}
+	q.cc.ComplexEntries = complexes
+	return nil
+}
+
+// ModifyComplexEntry modifies a complex entry.
+func (q *InMemoryQConf) ModifyComplexEntry(complexName string, cfg ComplexEntryConfig) error {
+	if cfg.Name == "" {
+		cfg.Name = complexName
+	}
+	if cfg.Type == "" {
+		return fmt.Errorf("complex does not have a type")
+	}
+	SetDefaultComplexEntryValues(&cfg)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ComplexEntries[complexName]; !exists {
+		return errNotExist("complex attribute", complexName)
+	}
+	q.cc.ComplexEntries[complexName] = deepCopy(cfg)
+	return nil
+}
+
+// AddCkptInterface adds a new checkpointing interface.
+func (q *InMemoryQConf) AddCkptInterface(cfg CkptInterfaceConfig) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("checkpointing interface name is required")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.CkptInterfaces[cfg.Name]; exists {
+		return errAlreadyExists("checkpoint interface", cfg.Name)
+	}
+	q.cc.CkptInterfaces[cfg.Name] = deepCopy(cfg)
+	return nil
+}
+
+// DeleteCkptInterface deletes a checkpointing interface.
+func (q *InMemoryQConf) DeleteCkptInterface(interfaceName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.CkptInterfaces[interfaceName]; !exists {
+		return errNotExist("checkpoint interface", interfaceName)
+	}
+	delete(q.cc.CkptInterfaces, interfaceName)
+	return nil
+}
+
+// ShowCkptInterface shows the specified checkpointing interface.
+func (q *InMemoryQConf) ShowCkptInterface(interfaceName string) (CkptInterfaceConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cfg, exists := q.cc.CkptInterfaces[interfaceName]
+	if !exists {
+		return CkptInterfaceConfig{}, errNotExist("checkpoint interface", interfaceName)
+	}
+	return deepCopy(cfg), nil
+}
+
+// ShowCkptInterfaces shows all checkpointing interfaces.
+func (q *InMemoryQConf) ShowCkptInterfaces() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.CkptInterfaces), nil
+}
+
+// ModifyCkptInterface modifies a checkpointing interface.
+func (q *InMemoryQConf) ModifyCkptInterface(ckptName string, cfg CkptInterfaceConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.CkptInterfaces[ckptName]; !exists {
+		return errNotExist("checkpoint interface", ckptName)
+	}
+	cfg.Name = ckptName
+	q.cc.CkptInterfaces[ckptName] = deepCopy(cfg)
+	return nil
+}
+
+// AddHostConfiguration adds a new host configuration.
+func (q *InMemoryQConf) AddHostConfiguration(config HostConfiguration) error {
+	if config.Name == "" {
+		return fmt.Errorf("hostname not set in host configuration")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostConfigurations[config.Name]; exists {
+		return errAlreadyExists("configuration", config.Name)
+	}
+	q.cc.HostConfigurations[config.Name] = deepCopy(config)
+	return nil
+}
+
+// DeleteHostConfiguration deletes a host configuration.
+func (q *InMemoryQConf) DeleteHostConfiguration(configName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostConfigurations[configName]; !exists {
+		return errNotExist("configuration", configName)
+	}
+	delete(q.cc.HostConfigurations, configName)
+	return nil
+}
+
+// ShowHostConfiguration shows the specified host configuration.
+func (q *InMemoryQConf) ShowHostConfiguration(hostName string) (HostConfiguration, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cfg, exists := q.cc.HostConfigurations[hostName]
+	if !exists {
+		return HostConfiguration{}, errNotExist("configuration", hostName)
+	}
+	return deepCopy(cfg), nil
+}
+
+// ShowHostConfigurations shows the names of all host configurations.
+func (q *InMemoryQConf) ShowHostConfigurations() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.HostConfigurations), nil
+}
+
+// ModifyHostConfiguration modifies a host configuration.
+func (q *InMemoryQConf) ModifyHostConfiguration(configName string, cfg HostConfiguration) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostConfigurations[configName]; !exists {
+		return errNotExist("configuration", configName)
+	}
+	cfg.Name = configName
+	q.cc.HostConfigurations[configName] = deepCopy(cfg)
+	return nil
+}
+
+// ShowGlobalConfiguration shows the global configuration.
+func (q *InMemoryQConf) ShowGlobalConfiguration() (*GlobalConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cc.GlobalConfig == nil {
+		return &GlobalConfig{}, nil
+	}
+	return deepCopy(q.cc.GlobalConfig), nil
+}
+
+// ModifyGlobalConfig modifies the global configuration.
+func (q *InMemoryQConf) ModifyGlobalConfig(g GlobalConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cc.GlobalConfig = deepCopy(&g)
+	return nil
+}
+
+// AddExecHost adds a new execution host.
+func (q *InMemoryQConf) AddExecHost(hostExecConfig HostExecConfig) error {
+	if hostExecConfig.Name == "" {
+		return fmt.Errorf("exec host name is required")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ExecHosts[hostExecConfig.Name]; exists {
+		return errAlreadyExists("execution host", hostExecConfig.Name)
+	}
+	q.cc.ExecHosts[hostExecConfig.Name] = deepCopy(hostExecConfig)
+	return nil
+}
+
+// DeleteExecHost deletes a comma-separated list of execution hosts.
+func (q *InMemoryQConf) DeleteExecHost(hostList string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, host := range splitObjectList(hostList) {
+		if _, exists := q.cc.ExecHosts[host]; !exists {
+			return errNotExist("execution host", host)
+		}
+		delete(q.cc.ExecHosts, host)
+	}
+	return nil
+}
+
+// ModifyExecHost modifies an execution host.
+func (q *InMemoryQConf) ModifyExecHost(execHostName string, h HostExecConfig) error {
+	SetDefaultExecHostConfig(&h)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ExecHosts[execHostName]; !exists {
+		return errNotExist("execution host", execHostName)
+	}
+	h.Name = execHostName
+	q.cc.ExecHosts[execHostName] = deepCopy(h)
+	return nil
+}
+
+// ShowExecHost shows the specified execution host.
+func (q *InMemoryQConf) ShowExecHost(hostName string) (HostExecConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	h, exists := q.cc.ExecHosts[hostName]
+	if !exists {
+		return HostExecConfig{}, errNotExist("execution host", hostName)
+	}
+	return deepCopy(h), nil
+}
+
+// ShowExecHosts shows all execution hosts.
+func (q *InMemoryQConf) ShowExecHosts() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.ExecHosts), nil
+}
+
+// AddAdminHost adds administrative hosts.
+func (q *InMemoryQConf) AddAdminHost(hosts []string) error {
+	if len(hosts) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.AdminHosts, err = addToList(q.cc.AdminHosts, hosts, "adminhost")
+	if err != nil {
+		return fmt.Errorf("failed to add adminhost: %w", err)
+	}
+	return nil
+}
+
+// DeleteAdminHost deletes administrative hosts.
+func (q *InMemoryQConf) DeleteAdminHost(hosts []string) error {
+	if hosts == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.AdminHosts, err = deleteFromList(q.cc.AdminHosts, hosts, "adminhost")
+	if err != nil {
+		return fmt.Errorf("failed to delete adminhost: %w", err)
+	}
+	return nil
+}
+
+// ShowAdminHosts shows all administrative hosts.
+func (q *InMemoryQConf) ShowAdminHosts() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.cc.AdminHosts), nil
+}
+
+// AddHostGroup adds a new host group.
+func (q *InMemoryQConf) AddHostGroup(hostGroup HostGroupConfig) error {
+	if !strings.HasPrefix(hostGroup.Name, "@") {
+		return fmt.Errorf("group name must start with '@'")
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostGroups[hostGroup.Name]; exists {
+		return errAlreadyExists("host group", hostGroup.Name)
+	}
+	q.cc.HostGroups[hostGroup.Name] = deepCopy(hostGroup)
+	return nil
+}
+
+// ModifyHostGroup modifies a host group.
+func (q *InMemoryQConf) ModifyHostGroup(hostGroupName string, hg HostGroupConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostGroups[hostGroupName]; !exists {
+		return errNotExist("host group", hostGroupName)
+	}
+	hg.Name = hostGroupName
+	q.cc.HostGroups[hostGroupName] = deepCopy(hg)
+	return nil
+}
+
+// DeleteHostGroup deletes a host group.
+func (q *InMemoryQConf) DeleteHostGroup(groupName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostGroups[groupName]; !exists {
+		return errNotExist("host group", groupName)
+	}
+	delete(q.cc.HostGroups, groupName)
+	return nil
+}
+
+// ShowHostGroup shows the specified host group.
+func (q *InMemoryQConf) ShowHostGroup(groupName string) (HostGroupConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	hg, exists := q.cc.HostGroups[groupName]
+	if !exists {
+		return HostGroupConfig{}, errNotExist("host group", groupName)
+	}
+	return deepCopy(hg), nil
+}
+
+// ShowHostGroupResolved returns all hosts of a host group, with nested
+// host groups expanded. Each host is reported once.
+func (q *InMemoryQConf) ShowHostGroupResolved(groupName string) ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.HostGroups[groupName]; !exists {
+		return nil, errNotExist("host group", groupName)
+	}
+	return resolveHostGroup(q.cc.HostGroups, groupName), nil
+}
+
+// ShowHostGroups shows all host groups.
+func (q *InMemoryQConf) ShowHostGroups() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.HostGroups), nil
+}
+
+// AddResourceQuotaSet adds a new resource quota set.
+func (q *InMemoryQConf) AddResourceQuotaSet(rqs ResourceQuotaSetConfig) error {
+	SetResourceQuotaSetDefaults(&rqs)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ResourceQuotaSets[rqs.Name]; exists {
+		return errAlreadyExists("resource quota set", rqs.Name)
+	}
+	q.cc.ResourceQuotaSets[rqs.Name] = deepCopy(rqs)
+	return nil
+}
+
+// DeleteResourceQuotaSet deletes a comma-separated list of resource
+// quota sets.
+func (q *InMemoryQConf) DeleteResourceQuotaSet(rqsList string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, name := range splitObjectList(rqsList) {
+		if _, exists := q.cc.ResourceQuotaSets[name]; !exists {
+			return errNotExist("resource quota set", name)
+		}
+		delete(q.cc.ResourceQuotaSets, name)
+	}
+	return nil
+}
+
+// ShowResourceQuotaSet shows the specified resource quota set.
+func (q *InMemoryQConf) ShowResourceQuotaSet(rqsList string) (ResourceQuotaSetConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	rqs, exists := q.cc.ResourceQuotaSets[rqsList]
+	if !exists {
+		return ResourceQuotaSetConfig{}, errNotExist("resource quota set", rqsList)
+	}
+	return deepCopy(rqs), nil
+}
+
+// ShowResourceQuotaSets shows all resource quota sets.
+func (q *InMemoryQConf) ShowResourceQuotaSets() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.ResourceQuotaSets), nil
+}
+
+// ModifyResourceQuotaSet modifies a resource quota set.
+func (q *InMemoryQConf) ModifyResourceQuotaSet(rqsName string, rqs ResourceQuotaSetConfig) error {
+	SetResourceQuotaSetDefaults(&rqs)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ResourceQuotaSets[rqsName]; !exists {
+		return errNotExist("resource quota set", rqsName)
+	}
+	rqs.Name = rqsName
+	q.cc.ResourceQuotaSets[rqsName] = deepCopy(rqs)
+	return nil
+}
+
+// AddUserToManagerList adds users to the manager list.
+func (q *InMemoryQConf) AddUserToManagerList(users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.Managers, err = addToList(q.cc.Managers, users, "manager")
+	return err
+}
+
+// DeleteUserFromManagerList deletes users from the manager list.
+func (q *InMemoryQConf) DeleteUserFromManagerList(users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.Managers, err = deleteFromList(q.cc.Managers, users, "manager")
+	return err
+}
+
+// ShowManagers shows the manager list.
+func (q *InMemoryQConf) ShowManagers() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.cc.Managers), nil
+}
+
+// AddUserToOperatorList adds users to the operator list.
+func (q *InMemoryQConf) AddUserToOperatorList(users []string) error {
+	if users == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.Operators, err = addToList(q.cc.Operators, users, "operator")
+	return err
+}
+
+// DeleteUserFromOperatorList deletes users from the operator list.
+func (q *InMemoryQConf) DeleteUserFromOperatorList(users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.Operators, err = deleteFromList(q.cc.Operators, users, "operator")
+	return err
+}
+
+// ShowOperators shows the operator list.
+func (q *InMemoryQConf) ShowOperators() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.cc.Operators), nil
+}
+
+// AddParallelEnvironment adds a new parallel environment.
+func (q *InMemoryQConf) AddParallelEnvironment(pe ParallelEnvironmentConfig) error {
+	SetDefaultParallelEnvironmentValues(&pe)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ParallelEnvironments[pe.Name]; exists {
+		return errAlreadyExists("parallel environment", pe.Name)
+	}
+	q.cc.ParallelEnvironments[pe.Name] = deepCopy(pe)
+	return nil
+}
+
+// DeleteParallelEnvironment deletes a parallel environment.
+func (q *InMemoryQConf) DeleteParallelEnvironment(peName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ParallelEnvironments[peName]; !exists {
+		return errNotExist("parallel environment", peName)
+	}
+	delete(q.cc.ParallelEnvironments, peName)
+	return nil
+}
+
+// ShowParallelEnvironment shows the specified parallel environment.
+func (q *InMemoryQConf) ShowParallelEnvironment(peName string) (ParallelEnvironmentConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	pe, exists := q.cc.ParallelEnvironments[peName]
+	if !exists {
+		return ParallelEnvironmentConfig{}, errNotExist("parallel environment", peName)
+	}
+	return deepCopy(pe), nil
+}
+
+// ShowParallelEnvironments shows all parallel environments.
+func (q *InMemoryQConf) ShowParallelEnvironments() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.ParallelEnvironments), nil
+}
+
+// ModifyParallelEnvironment modifies a parallel environment.
+func (q *InMemoryQConf) ModifyParallelEnvironment(peName string, pe ParallelEnvironmentConfig) error {
+	SetDefaultParallelEnvironmentValues(&pe)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ParallelEnvironments[peName]; !exists {
+		return errNotExist("parallel environment", peName)
+	}
+	pe.Name = peName
+	q.cc.ParallelEnvironments[peName] = deepCopy(pe)
+	return nil
+}
+
+// AddProject adds a new project.
+func (q *InMemoryQConf) AddProject(project ProjectConfig) error {
+	SetDefaultProjectValues(&project)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Projects[project.Name]; exists {
+		return errAlreadyExists("project", project.Name)
+	}
+	q.cc.Projects[project.Name] = deepCopy(project)
+	return nil
+}
+
+// DeleteProject deletes projects.
+func (q *InMemoryQConf) DeleteProject(projects []string) error {
+	if len(projects) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, name := range projects {
+		if _, exists := q.cc.Projects[name]; !exists {
+			return errNotExist("project", name)
+		}
+		delete(q.cc.Projects, name)
+	}
+	return nil
+}
+
+// ShowProject shows the specified project.
+func (q *InMemoryQConf) ShowProject(projectName string) (ProjectConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	p, exists := q.cc.Projects[projectName]
+	if !exists {
+		return ProjectConfig{}, errNotExist("project", projectName)
+	}
+	return deepCopy(p), nil
+}
+
+// ShowProjects shows all projects.
+func (q *InMemoryQConf) ShowProjects() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.Projects), nil
+}
+
+// ModifyProject modifies a project.
+func (q *InMemoryQConf) ModifyProject(projectName string, p ProjectConfig) error {
+	SetDefaultProjectValues(&p)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Projects[projectName]; !exists {
+		return errNotExist("project", projectName)
+	}
+	p.Name = projectName
+	q.cc.Projects[projectName] = deepCopy(p)
+	return nil
+}
+
+// AddClusterQueue adds a new cluster queue.
+func (q *InMemoryQConf) AddClusterQueue(queue ClusterQueueConfig) error {
+	SetDefaultQueueValues(&queue)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ClusterQueues[queue.Name]; exists {
+		return errAlreadyExists("cluster queue", queue.Name)
+	}
+	q.cc.ClusterQueues[queue.Name] = deepCopy(queue)
+	return nil
+}
+
+// ModifyClusterQueue modifies a cluster queue.
+func (q *InMemoryQConf) ModifyClusterQueue(queueName string, cfg ClusterQueueConfig) error {
+	cfg.Name = queueName
+	SetDefaultQueueValues(&cfg)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ClusterQueues[queueName]; !exists {
+		return errNotExist("cluster queue", queueName)
+	}
+	q.cc.ClusterQueues[queueName] = deepCopy(cfg)
+	return nil
+}
+
+// DeleteClusterQueue deletes a cluster queue.
+func (q *InMemoryQConf) DeleteClusterQueue(queueName string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.ClusterQueues[queueName]; !exists {
+		return errNotExist("cluster queue", queueName)
+	}
+	delete(q.cc.ClusterQueues, queueName)
+	return nil
+}
+
+// ShowClusterQueue shows the specified cluster queue.
+func (q *InMemoryQConf) ShowClusterQueue(queueName string) (ClusterQueueConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	cfg, exists := q.cc.ClusterQueues[queueName]
+	if !exists {
+		return ClusterQueueConfig{}, errNotExist("cluster queue", queueName)
+	}
+	return deepCopy(cfg), nil
+}
+
+// ShowClusterQueues shows all cluster queues.
+func (q *InMemoryQConf) ShowClusterQueues() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.ClusterQueues), nil
+}
+
+// AddSubmitHosts adds submit hosts.
+func (q *InMemoryQConf) AddSubmitHosts(hostnames []string) error {
+	if hostnames == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.SubmitHosts, err = addToList(q.cc.SubmitHosts, hostnames, "submit host")
+	return err
+}
+
+// DeleteSubmitHost deletes submit hosts.
+func (q *InMemoryQConf) DeleteSubmitHost(hostnames []string) error {
+	if hostnames == nil {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var err error
+	q.cc.SubmitHosts, err = deleteFromList(q.cc.SubmitHosts, hostnames, "submit host")
+	return err
+}
+
+// ShowSubmitHosts shows all submit hosts.
+func (q *InMemoryQConf) ShowSubmitHosts() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return slices.Clone(q.cc.SubmitHosts), nil
+}
+
+// AddUserSetList adds a new user set list.
+func (q *InMemoryQConf) AddUserSetList(listnameList string, u UserSetListConfig) error {
+	SetDefaultUserSetListConfig(&u)
+	u.Name = listnameList
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.UserSetLists[listnameList]; exists {
+		return errAlreadyExists("userset", listnameList)
+	}
+	q.cc.UserSetLists[listnameList] = deepCopy(u)
+	return nil
+}
+
+// AddUserToUserSetList adds users to user set lists. Like qconf -au, a
+// user set list which does not exist yet is created as an ACL, and users
+// which are already members are left alone.
+func (q *InMemoryQConf) AddUserToUserSetList(userList, listnameList string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, list := range splitObjectList(listnameList) {
+		u, exists := q.cc.UserSetLists[list]
+		if !exists {
+			u = UserSetListConfig{Name: list, Type: "ACL"}
+		}
+		for _, user := range splitObjectList(userList) {
+			if !slices.Contains(u.Entries, user) {
+				u.Entries = append(u.Entries, user)
+			}
+		}
+		q.cc.UserSetLists[list] = u
+	}
+	return nil
+}
+
+// DeleteUserFromUserSetList deletes users from user set lists.
+func (q *InMemoryQConf) DeleteUserFromUserSetList(userList, listnameList string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, list := range splitObjectList(listnameList) {
+		u, exists := q.cc.UserSetLists[list]
+		if !exists {
+			return errNotExist("userset", list)
+		}
+		u.Entries = slices.DeleteFunc(slices.Clone(u.Entries), func(e string) bool {
+			return slices.Contains(splitObjectList(userList), e)
+		})
+		q.cc.UserSetLists[list] = u
+	}
+	return nil
+}
+
+// DeleteUserSetList deletes a comma-separated list of user set lists.
+func (q *InMemoryQConf) DeleteUserSetList(userList string) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, name := range splitObjectList(userList) {
+		if _, exists := q.cc.UserSetLists[name]; !exists {
+			return errNotExist("userset", name)
+		}
+		delete(q.cc.UserSetLists, name)
+	}
+	return nil
+}
+
+// ShowUserSetList shows the specified user set list.
+func (q *InMemoryQConf) ShowUserSetList(listnameList string) (UserSetListConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u, exists := q.cc.UserSetLists[listnameList]
+	if !exists {
+		return UserSetListConfig{}, errNotExist("userset", listnameList)
+	}
+	return deepCopy(u), nil
+}
+
+// ShowUserSetLists shows all user set lists.
+func (q *InMemoryQConf) ShowUserSetLists() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.UserSetLists), nil
+}
+
+// ModifyUserset modifies a user set list.
+func (q *InMemoryQConf) ModifyUserset(listnameList string, u UserSetListConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.UserSetLists[listnameList]; !exists {
+		return errNotExist("userset", listnameList)
+	}
+	u.Name = listnameList
+	q.cc.UserSetLists[listnameList] = deepCopy(u)
+	return nil
+}
+
+// AddUser adds a new user.
+func (q *InMemoryQConf) AddUser(userConfig UserConfig) error {
+	SetDefaultUserValues(&userConfig)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Users[userConfig.Name]; exists {
+		return errAlreadyExists("user", userConfig.Name)
+	}
+	q.cc.Users[userConfig.Name] = deepCopy(userConfig)
+	return nil
+}
+
+// DeleteUser deletes users.
+func (q *InMemoryQConf) DeleteUser(users []string) error {
+	if len(users) == 0 {
+		return nil
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	for _, name := range users {
+		if _, exists := q.cc.Users[name]; !exists {
+			return errNotExist("user", name)
+		}
+		delete(q.cc.Users, name)
+	}
+	return nil
+}
+
+// ShowUser shows the specified user. A missing user is reported like
+// CommandLineQConf.ShowUser does.
+func (q *InMemoryQConf) ShowUser(userName string) (UserConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	u, exists := q.cc.Users[userName]
+	if !exists {
+		return UserConfig{}, fmt.Errorf("user %s is not defined: %w",
+			userName, qerror.ErrNotExist)
+	}
+	return deepCopy(u), nil
+}
+
+// ShowUsers shows all users.
+func (q *InMemoryQConf) ShowUsers() ([]string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return sortedKeys(q.cc.Users), nil
+}
+
+// ModifyUser modifies a user.
+func (q *InMemoryQConf) ModifyUser(userName string, u UserConfig) error {
+	SetDefaultUserValues(&u)
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, exists := q.cc.Users[userName]; !exists {
+		return errNotExist("user", userName)
+	}
+	u.Name = userName
+	q.cc.Users[userName] = deepCopy(u)
+	return nil
+}
+
+// CleanQueue is a no-op; there are no jobs in memory.
+func (q *InMemoryQConf) CleanQueue(destinID []string) error {
+	return nil
+}
+
+// ShutdownExecDaemons is a no-op; there are no daemons in memory.
+func (q *InMemoryQConf) ShutdownExecDaemons(hosts []string) error {
+	return nil
+}
+
+// ShutdownMasterDaemon is a no-op; there are no daemons in memory.
+func (q *InMemoryQConf) ShutdownMasterDaemon() error {
+	return nil
+}
+
+// ShutdownSchedulingDaemon is a no-op; there are no daemons in memory.
+func (q *InMemoryQConf) ShutdownSchedulingDaemon() error {
+	return nil
+}
+
+// KillEventClient is a no-op; there are no event clients in memory.
+func (q *InMemoryQConf) KillEventClient(evids []string) error {
+	return nil
+}
+
+// KillQmasterThread is a no-op; there are no threads in memory.
+func (q *InMemoryQConf) KillQmasterThread(threadName string) error {
+	return nil
+}
+
+// ModifySchedulerConfig modifies the scheduler configuration.
+func (q *InMemoryQConf) ModifySchedulerConfig(cfg SchedulerConfig) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.cc.SchedulerConfig = deepCopy(&cfg)
+	return nil
+}
+
+// ShowSchedulerConfiguration shows the scheduler configuration.
+func (q *InMemoryQConf) ShowSchedulerConfiguration() (*SchedulerConfig, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.cc.SchedulerConfig == nil {
+		return &SchedulerConfig{}, nil
+	}
+	return deepCopy(q.cc.SchedulerConfig), nil
+}
+
+// ModifyAttribute modifies an attribute of an object, like qconf -mattr.
+func (q *InMemoryQConf) ModifyAttribute(objName, attrName, val, objIDList string) error {
+	return q.changeAttribute(attrModify, objName, attrName, val, objIDList)
+}
+
+// AddAttribute adds a value to a list-valued attribute of an object, like
+// qconf -aattr. Adding a value, or the key of a key=value element, which
+// is already present returns an error wrapping ErrNoModification.
+func (q *InMemoryQConf) AddAttribute(objName, attrName, val, objIDList string) error {
+	return q.changeAttribute(attrAdd, objName, attrName, val, objIDList)
+}
+
+// ReplaceAttribute replaces the complete value of a list-valued attribute
+// of an object, like qconf -rattr.
+func (q *InMemoryQConf) ReplaceAttribute(objName, attrName, val, objIDList string) error {
+	return q.changeAttribute(attrReplace, objName, attrName, val, objIDList)
+}
+
+// DeleteAttribute deletes a value from a list-valued attribute of an
+// object, like qconf -dattr. Deleting a value which is not present
+// returns an error wrapping ErrNoModification.
+func (q *InMemoryQConf) DeleteAttribute(objName, attrName, val, objIDList string) error {
+	return q.changeAttribute(attrDelete, objName, attrName, val, objIDList)
+}
+
+// PurgeAttribute removes the host or host group specific values of the
+// comma separated attributes attrName from a queue instance, like qconf
+// -purge. Purging a value which is not set returns an error wrapping
+// ErrNoModification.
+func (q *InMemoryQConf) PurgeAttribute(objName, attrName, objInstance string) error {
+	if objName != "queue" {
+		return memoryCommandError(fmt.Sprintf("unknown object type \"%s\"", objName))
+	}
+	for _, attr := range splitObjectList(attrName) {
+		if err := q.changeAttribute(attrPurge, objName, attr, "", objInstance); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,394 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// attrOp is one of the qconf -mattr / -aattr / -dattr / -rattr /
+// -purge operations.
+type attrOp int
+
+const (
+	attrModify attrOp = iota
+	attrAdd
+	attrDelete
+	attrReplace
+	attrPurge
+)
+
+// memoryAttrObject describes an object kind which can be changed with
+// the attribute operations: the ClusterConfig field holding the objects
+// and the name qmaster uses for the kind in its messages.
+type memoryAttrObject struct {
+	field string
+	kind  string
+}
+
+// memoryAttrObjects maps the qconf object names accepted by -mattr,
+// -aattr and -dattr to the objects they address.
+var memoryAttrObjects = map[string]memoryAttrObject{
+	"queue":     {field: "ClusterQueues", kind: "cluster queue"},
+	"exechost":  {field: "ExecHosts", kind: "execution host"},
+	"hostgroup": {field: "HostGroups", kind: "host group"},
+	"pe":        {field: "ParallelEnvironments", kind: "parallel environment"},
+	"ckpt":      {field: "CkptInterfaces", kind: "checkpoint interface"},
+}
+
+// spaceSeparatedQueueAttrs are the queue attributes holding space
+// separated lists; all others are comma separated. This follows the
+// parse helpers used by ShowClusterQueue.
+var spaceSeparatedQueueAttrs = map[string]bool{
+	"hostlist":         true,
+	"qtype":            true,
+	"ckpt_list":        true,
+	"pe_list":          true,
+	"owner_list":       true,
+	"user_lists":       true,
+	"xuser_lists":      true,
+	"subordinate_list": true,
+	"projects":         true,
+	"xprojects":        true,
+	"calendar":         true,
+	"initial_state":    true,
+}
+
+// errAlreadyInList is the qmaster notice for adding an element whose key
+// is already present. qconf exits 0, so CommandLineQConf reports it via
+// ErrNoModification; so does InMemoryQConf.
+func errAlreadyInList(key, attrName, objName string) error {
+	return fmt.Errorf("%w: No modification because \"%s\" already exists in \"%s\" of \"%s\"",
+		ErrNoModification, key, attrName, objName)
+}
+
+// errNotInList is the qmaster notice for deleting an element which is
+// not present.
+func errNotInList(key, attrName, objID string) error {
+	return fmt.Errorf("%w: \"%s\" does not exist in \"%s\" of \"%s\"",
+		ErrNoModification, key, attrName, objID)
+}
+
+// changeAttribute applies op to attribute attrName of every object in
+// objIDList. Queue instances ("all.q@host", "all.q@@hostgroup") address
+// the host or host group override of the attribute.
+func (q *InMemoryQConf) changeAttribute(op attrOp, objName, attrName, val, objIDList string) error {
+	desc, ok := memoryAttrObjects[objName]
+	if !ok {
+		return memoryCommandError(fmt.Sprintf("unknown object type \"%s\"", objName))
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	objects := reflect.ValueOf(&q.cc).Elem().FieldByName(desc.field)
+	for _, id := range splitObjectList(objIDList) {
+		name, target := id, ""
+		if objName == "queue" {
+			if i := strings.Index(id, "@"); i >= 0 {
+				name, target = id[:i], id[i+1:]
+			}
+		}
+		if op == attrPurge && target == "" {
+			return memoryCommandError(fmt.Sprintf("\"%s\" is not a queue instance", id))
+		}
+		stored := objects.MapIndex(reflect.ValueOf(name))
+		if !stored.IsValid() {
+			return errNotExist(desc.kind, name)
+		}
+		obj := reflect.New(stored.Type()).Elem()
+		copyValue(obj, stored)
+		fv, ok := fieldByJSONName(obj, attrName)
+		if !ok {
+			return memoryCommandError(fmt.Sprintf("unknown attribute name \"%s\"", attrName))
+		}
+		if err := changeAttrValue(op, fv, objName, attrName, val, id, target); err != nil {
+			return err
+		}
+		if queue, ok := obj.Addr().Interface().(*ClusterQueueConfig); ok {
+			SetDefaultQueueValues(queue)
+		}
+		objects.SetMapIndex(reflect.ValueOf(name), obj)
+	}
+	return nil
+}
+
+// fieldByJSONName returns the field of struct v whose json name is name.
+// ExtraFields are not attributes and never match.
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Name == "ExtraFields" {
+			continue
+		}
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// changeAttrValue applies op to a single attribute value. target is the
+// host or host group of a queue instance, empty for the object itself.
+func changeAttrValue(op attrOp, fv reflect.Value, objName, attrName, val, objID, target string) error {
+	if target != "" && fv.Kind() != reflect.Slice {
+		return memoryCommandError(fmt.Sprintf("attribute \"%s\" has no host specific values", attrName))
+	}
+	switch fv.Kind() {
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			break
+		}
+		elems := fv.Interface().([]string)
+		var (
+			result []string
+			err    error
+		)
+		if objName == "queue" {
+			result, err = changeQueueListAttr(op, elems, val, attrName, objName, objID, target)
+		} else {
+			items := strings.FieldsFunc(val, isListSeparator)
+			result, err = changeListAttr(op, noneToEmpty(elems), items, attrName, objName, objID)
+		}
+		if err != nil {
+			return err
+		}
+		fv.Set(reflect.ValueOf(result))
+		return nil
+	case reflect.Map:
+		return changeMapAttr(op, fv, val, attrName, objName, objID)
+	}
+	if op != attrModify {
+		return memoryCommandError(fmt.Sprintf("attribute \"%s\" is not a list", attrName))
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(val)
+	case reflect.Int:
+		n, err := strconv.Atoi(val)
+		if err != nil {
+			return memoryCommandError(fmt.Sprintf("invalid value \"%s\" for attribute \"%s\"", val, attrName))
+		}
+		fv.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(val, 64)
+		if err != nil {
+			return memoryCommandError(fmt.Sprintf("invalid value \"%s\" for attribute \"%s\"", val, attrName))
+		}
+		fv.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(val)
+		if err != nil {
+			return memoryCommandError(fmt.Sprintf("invalid value \"%s\" for attribute \"%s\"", val, attrName))
+		}
+		fv.SetBool(b)
+	default:
+		return memoryCommandError(fmt.Sprintf("attribute \"%s\" cannot be changed", attrName))
+	}
+	return nil
+}
+
+func isListSeparator(r rune) bool {
+	return r == ' ' || r == ','
+}
+
+// noneToEmpty treats the qconf "NONE" placeholder as an empty list.
+func noneToEmpty(elems []string) []string {
+	if len(elems) == 1 && strings.EqualFold(elems[0], "NONE") {
+		return nil
+	}
+	return slices.Clone(elems)
+}
+
+func listKey(elem string) string {
+	key, _, _ := strings.Cut(elem, "=")
+	return key
+}
+
+// changeListAttr applies op to a plain list. Elements of the form
+// key=value are matched by key, others by their full value. A modify
+// with key=value items updates or appends those keys; any other modify
+// replaces the whole list.
+func changeListAttr(op attrOp, elems, items []string, attrName, objName, objID string) ([]string, error) {
+	indexOf := func(item string) int {
+		return slices.IndexFunc(elems, func(e string) bool {
+			return listKey(e) == listKey(item)
+		})
+	}
+	switch op {
+	case attrAdd:
+		for _, item := range items {
+			if indexOf(item) >= 0 {
+				return nil, errAlreadyInList(listKey(item), attrName, objName)
+			}
+			elems = append(elems, item)
+		}
+	case attrDelete:
+		for _, item := range items {
+			i := indexOf(item)
+			if i < 0 {
+				return nil, errNotInList(listKey(item), attrName, objID)
+			}
+			elems = slices.Delete(elems, i, i+1)
+		}
+	case attrReplace:
+		elems = noneToEmpty(items)
+	case attrModify:
+		if !slices.ContainsFunc(items, func(s string) bool { return strings.Contains(s, "=") }) {
+			return items, nil
+		}
+		for _, item := range items {
+			if i := indexOf(item); i >= 0 {
+				elems[i] = item
+			} else {
+				elems = append(elems, item)
+			}
+		}
+	}
+	if len(elems) == 0 {
+		return nil, nil
+	}
+	return elems, nil
+}
+
+// changeQueueListAttr applies op to a queue attribute in the
+// ClusterQueueConfig representation: the cluster-wide elements followed
+// by "[host=value]" overrides. With a target the override for that host
+// or host group is changed; a missing override starts from the
+// cluster-wide value, as qmaster does.
+func changeQueueListAttr(op attrOp, elems []string, val, attrName, objName, objID, target string) ([]string, error) {
+	sep := ","
+	var items []string
+	if spaceSeparatedQueueAttrs[attrName] {
+		sep = " "
+		items = strings.FieldsFunc(val, isListSeparator)
+	} else {
+		items = splitObjectList(val)
+	}
+
+	var defaults, overrides []string
+	for _, e := range elems {
+		if strings.HasPrefix(e, "[") {
+			overrides = append(overrides, e)
+			continue
+		}
+		defaults = append(defaults, e)
+	}
+	defaults = noneToEmpty(defaults)
+
+	if target == "" {
+		var err error
+		defaults, err = changeListAttr(op, defaults, items, attrName, objName, objID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		prefix := "[" + target + "="
+		i := slices.IndexFunc(overrides, func(o string) bool {
+			return strings.HasPrefix(o, prefix)
+		})
+		if op == attrPurge {
+			if i < 0 {
+				return nil, errNotInList(target, attrName, objID)
+			}
+			overrides = slices.Delete(overrides, i, i+1)
+			return joinQueueListAttr(defaults, overrides), nil
+		}
+		current := slices.Clone(defaults)
+		if i >= 0 {
+			inner := strings.TrimSuffix(strings.TrimPrefix(overrides[i], prefix), "]")
+			current = noneToEmpty(strings.Split(inner, sep))
+		}
+		changed, err := changeListAttr(op, current, items, attrName, objName, objID)
+		if err != nil {
+			return nil, err
+		}
+		override := prefix + JoinList(changed, sep) + "]"
+		if i >= 0 {
+			overrides[i] = override
+		} else {
+			overrides = append(overrides, override)
+		}
+	}
+
+	return joinQueueListAttr(defaults, overrides), nil
+}
+
+// joinQueueListAttr joins the cluster-wide elements and the overrides of
+// a queue attribute; "NONE" stands in for missing cluster-wide elements.
+func joinQueueListAttr(defaults, overrides []string) []string {
+	if len(defaults) == 0 {
+		if len(overrides) == 0 {
+			return nil
+		}
+		defaults = []string{"NONE"}
+	}
+	return append(defaults, overrides...)
+}
+
+// changeMapAttr applies op to a key=value map attribute such as the
+// complex_values or load_scaling of an execution host.
+func changeMapAttr(op attrOp, fv reflect.Value, val, attrName, objName, objID string) error {
+	if fv.Type().Key().Kind() != reflect.String {
+		return memoryCommandError(fmt.Sprintf("attribute \"%s\" cannot be changed", attrName))
+	}
+	if fv.IsNil() || op == attrReplace {
+		fv.Set(reflect.MakeMap(fv.Type()))
+	}
+	for _, item := range strings.FieldsFunc(val, isListSeparator) {
+		key, raw, _ := strings.Cut(item, "=")
+		k := reflect.ValueOf(key)
+		exists := fv.MapIndex(k).IsValid()
+		switch op {
+		case attrAdd:
+			if exists {
+				return errAlreadyInList(key, attrName, objName)
+			}
+		case attrReplace:
+			if strings.EqualFold(item, "NONE") {
+				continue
+			}
+		case attrDelete:
+			if !exists {
+				return errNotInList(key, attrName, objID)
+			}
+			fv.SetMapIndex(k, reflect.Value{})
+			continue
+		}
+		v := reflect.New(fv.Type().Elem()).Elem()
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString(raw)
+		case reflect.Float64:
+			f, err := strconv.ParseFloat(raw, 64)
+			if err != nil {
+				return memoryCommandError(fmt.Sprintf("invalid value \"%s\" for attribute \"%s\"", item, attrName))
+			}
+			v.SetFloat(f)
+		default:
+			return memoryCommandError(fmt.Sprintf("attribute \"%s\" cannot be changed", attrName))
+		}
+		fv.SetMapIndex(k, v)
+	}
+	return nil
+}
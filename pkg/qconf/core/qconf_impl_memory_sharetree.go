@@ -0,0 +1,316 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// noShareTreeError is what ShowShareTree reports when no tree exists.
+func noShareTreeError() error {
+	return fmt.Errorf("%w: %s", ErrNoShareTree, "no sharetree element")
+}
+
+// shareTreeNodePath renders a node path the way qconf -sstnode prints it:
+// without the "/Root" prefix, the root itself being "/".
+func shareTreeNodePath(segments []string) string {
+	return "/" + strings.Join(segments, "/")
+}
+
+// ClearShareTreeUsage is a no-op; no usage is recorded in memory.
+func (q *InMemoryQConf) ClearShareTreeUsage() error {
+	return nil
+}
+
+// ShowShareTree returns the share tree in qconf -sstree text format, or
+// an error wrapping ErrNoShareTree when no tree is configured.
+func (q *InMemoryQConf) ShowShareTree() (string, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return "", noShareTreeError()
+	}
+	return FormatShareTreeText(q.shareTree)
+}
+
+// ModifyShareTreeNodes modifies the shares of existing nodes. Like qconf
+// -mstnode it updates every node it can find and then reports the ones
+// it could not locate as an error.
+func (q *InMemoryQConf) ModifyShareTreeNodes(nodeShareList []ShareTreeNode) error {
+	if len(nodeShareList) == 0 {
+		return fmt.Errorf("no nodes to modify")
+	}
+	for _, node := range nodeShareList {
+		if err := ValidateSharePath(node.Node); err != nil {
+			return err
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return memoryCommandError("no sharetree element")
+	}
+	var missing []string
+	for _, node := range nodeShareList {
+		n, _, err := FindNodeByPath(q.shareTree.Root, node.Node)
+		if err != nil {
+			missing = append(missing, "Unable to locate "+stripRootPrefix(node.Node))
+			continue
+		}
+		n.Shares = node.Share
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("share tree node not found: %s", strings.Join(missing, "\n"))
+	}
+	return nil
+}
+
+// DeleteShareTreeNodes removes the given nodes and their descendants.
+func (q *InMemoryQConf) DeleteShareTreeNodes(nodeList []string) error {
+	if len(nodeList) == 0 {
+		return fmt.Errorf("no nodes to delete")
+	}
+	for _, p := range nodeList {
+		if err := ValidateSharePath(p); err != nil {
+			return err
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return memoryCommandError("no sharetree element")
+	}
+	for _, p := range nodeList {
+		n, parent, err := FindNodeByPath(q.shareTree.Root, p)
+		if err != nil || parent == nil {
+			return memoryCommandError("Unable to locate " + stripRootPrefix(p))
+		}
+		parent.Children = slices.DeleteFunc(parent.Children, func(c *StructuredShareTreeNode) bool {
+			return c == n
+		})
+	}
+	return nil
+}
+
+// AddShareTreeNode adds a node below an existing parent, or sets the
+// shares of the node when it already exists, like qconf -astnode.
+func (q *InMemoryQConf) AddShareTreeNode(node ShareTreeNode) error {
+	if err := ValidateSharePath(node.Node); err != nil {
+		return err
+	}
+	segments := SplitSharePath(node.Node)
+	if len(segments) == 0 {
+		return fmt.Errorf("share tree: invalid path %q", node.Node)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return memoryCommandError("no sharetree element")
+	}
+	if n, _, err := FindNodeByPath(q.shareTree.Root, node.Node); err == nil {
+		n.Shares = node.Share
+		return nil
+	}
+	parentPath := "/" + strings.Join(segments[:len(segments)-1], "/")
+	parent, _, err := FindNodeByPath(q.shareTree.Root, parentPath)
+	if err != nil {
+		return memoryCommandError("Unable to locate " + stripRootPrefix(parentPath))
+	}
+	parent.Children = append(parent.Children, &StructuredShareTreeNode{
+		Name:   segments[len(segments)-1],
+		Type:   ShareTreeNodeUser,
+		Shares: node.Share,
+	})
+	return nil
+}
+
+// ShowShareTreeNodes returns the shares of the given nodes, or of all
+// nodes in pre-order when nodeList is empty.
+func (q *InMemoryQConf) ShowShareTreeNodes(nodeList []string) ([]ShareTreeNode, error) {
+	for _, p := range nodeList {
+		if err := ValidateSharePath(p); err != nil {
+			return nil, err
+		}
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return nil, memoryCommandError("no sharetree element")
+	}
+	var nodes []ShareTreeNode
+	if len(nodeList) == 0 {
+		var walk func(n *StructuredShareTreeNode, segments []string)
+		walk = func(n *StructuredShareTreeNode, segments []string) {
+			nodes = append(nodes, ShareTreeNode{
+				Node:  shareTreeNodePath(segments),
+				Share: n.Shares,
+			})
+			for _, c := range n.Children {
+				walk(c, append(slices.Clone(segments), c.Name))
+			}
+		}
+		walk(q.shareTree.Root, nil)
+		return nodes, nil
+	}
+	for _, p := range nodeList {
+		n, _, err := FindNodeByPath(q.shareTree.Root, p)
+		if err != nil {
+			return nil, memoryCommandError("Unable to locate " + stripRootPrefix(p))
+		}
+		nodes = append(nodes, ShareTreeNode{
+			Node:  shareTreeNodePath(SplitSharePath(p)[1:]),
+			Share: n.Shares,
+		})
+	}
+	return nodes, nil
+}
+
+// ModifyShareTree replaces the share tree with the given qconf -sstree
+// text. An empty string deletes the tree.
+func (q *InMemoryQConf) ModifyShareTree(shareTreeConfig string) error {
+	if shareTreeConfig == "" {
+		return q.DeleteShareTree()
+	}
+	tree, err := ParseShareTreeText(shareTreeConfig)
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shareTree = tree
+	return nil
+}
+
+// DeleteShareTree deletes the share tree. Deleting a tree which does not
+// exist is a no-op.
+func (q *InMemoryQConf) DeleteShareTree() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.shareTree = nil
+	return nil
+}
+
+// ShowShareTreeStructured returns a copy of the share tree, or
+// ErrNoShareTree when no tree is configured.
+func (q *InMemoryQConf) ShowShareTreeStructured() (*StructuredShareTree, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return nil, noShareTreeError()
+	}
+	// Round-trip through the text format so node IDs are canonical, as
+	// they are after a qconf -sstree.
+	txt, err := FormatShareTreeText(q.shareTree)
+	if err != nil {
+		return nil, err
+	}
+	return ParseShareTreeText(txt)
+}
+
+// ModifyShareTreeStructured replaces the entire share tree.
+func (q *InMemoryQConf) ModifyShareTreeStructured(t *StructuredShareTree) error {
+	txt, err := FormatShareTreeText(t)
+	if err != nil {
+		return err
+	}
+	return q.ModifyShareTree(txt)
+}
+
+// ShowShareTreeMonitoring always returns ErrShareTreeMonNotAvail; there
+// is no sge_share_mon to ask.
+func (q *InMemoryQConf) ShowShareTreeMonitoring() (*ShareTreeMonitoring, error) {
+	return nil, ErrShareTreeMonNotAvail
+}
+
+// ShowShareTreeSubtree returns a deep copy of the subtree rooted at path.
+func (q *InMemoryQConf) ShowShareTreeSubtree(path string) (*StructuredShareTreeNode, error) {
+	tree, err := q.ShowShareTreeStructured()
+	if err != nil {
+		return nil, err
+	}
+	normalized, nerr := NormalizeSharePath(path)
+	if nerr != nil {
+		return nil, pathNotFound(path)
+	}
+	target, _, ferr := FindNodeByPath(tree.Root, normalized)
+	if ferr != nil {
+		return nil, pathNotFound(normalized)
+	}
+	return CloneShareTreeSubtree(target), nil
+}
+
+// updateShareTree applies op to the current tree and stores the result.
+// The lock is held across read and write so concurrent subtree edits do
+// not lose updates.
+func (q *InMemoryQConf) updateShareTree(op func(*StructuredShareTree) (*StructuredShareTree, error)) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.shareTree == nil {
+		return noShareTreeError()
+	}
+	newTree, err := op(q.shareTree)
+	if err != nil {
+		return err
+	}
+	q.shareTree = newTree
+	return nil
+}
+
+// ModifyShareTreeSubtree replaces the subtree at path with sub.
+func (q *InMemoryQConf) ModifyShareTreeSubtree(path string, sub *StructuredShareTreeNode) error {
+	return q.updateShareTree(func(t *StructuredShareTree) (*StructuredShareTree, error) {
+		return ApplySubtreeReplace(t, path, sub, nil)
+	})
+}
+
+// AddShareTreeSubtree inserts sub as a child of parentPath.
+func (q *InMemoryQConf) AddShareTreeSubtree(parentPath string, sub *StructuredShareTreeNode) error {
+	return q.updateShareTree(func(t *StructuredShareTree) (*StructuredShareTree, error) {
+		return ApplySubtreeAdd(t, parentPath, sub, nil)
+	})
+}
+
+// DeleteShareTreeSubtree deletes the node at path and all descendants.
+func (q *InMemoryQConf) DeleteShareTreeSubtree(path string) error {
+	return q.updateShareTree(func(t *StructuredShareTree) (*StructuredShareTree, error) {
+		return ApplySubtreeDelete(t, path)
+	})
+}
+
+// MoveShareTreeSubtree relocates the subtree at srcPath below
+// destParentPath.
+func (q *InMemoryQConf) MoveShareTreeSubtree(srcPath, destParentPath string) error {
+	return q.updateShareTree(func(t *StructuredShareTree) (*StructuredShareTree, error) {
+		return ApplySubtreeMove(t, srcPath, destParentPath, nil)
+	})
+}
+
+// ApplyShareTreeBatch applies a sequence of subtree operations
+// atomically; on error the tree is left unchanged.
+func (q *InMemoryQConf) ApplyShareTreeBatch(ops []SubtreeOp) error {
+	if len(ops) == 0 {
+		return fmt.Errorf("share tree batch: no operations")
+	}
+	return q.updateShareTree(func(t *StructuredShareTree) (*StructuredShareTree, error) {
+		return ApplySubtreeBatch(t, ops, nil)
+	})
+}
@@ -0,0 +1,286 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+func newInMemoryQConf(cc core.ClusterConfig) *core.InMemoryQConf {
+	qc, err := core.NewInMemoryQConf(core.InMemoryQConfConfig{
+		ClusterConfig: cc,
+	})
+	Expect(err).NotTo(HaveOccurred())
+	return qc
+}
+
+var _ = Describe("InMemoryQConf", func() {
+
+	var qc *core.InMemoryQConf
+
+	BeforeEach(func() {
+		qc = newInMemoryQConf(core.ClusterConfig{
+			HostGroups: map[string]core.HostGroupConfig{
+				"@allhosts": {Name: "@allhosts", Hosts: []string{"@rack1", "node3"}},
+				"@rack1":    {Name: "@rack1", Hosts: []string{"node1", "node2"}},
+			},
+		})
+	})
+
+	It("implements the QConf interface", func() {
+		var _ core.QConf = qc
+	})
+
+	It("reports a default version", func() {
+		v, err := qc.GetVersion()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(v.Major).To(Equal(9))
+	})
+
+	Context("objects", func() {
+
+		It("adds, shows, modifies and deletes a calendar", func() {
+			Expect(qc.AddCalendar(core.CalendarConfig{Name: "night", Year: "NONE", Week: "mon-fri=18-6"})).To(Succeed())
+			err := qc.AddCalendar(core.CalendarConfig{Name: "night"})
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("already exists"))
+
+			cal, err := qc.ShowCalendar("night")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cal.Week).To(Equal("mon-fri=18-6"))
+
+			Expect(qc.ModifyCalendar("night", core.CalendarConfig{Year: "NONE", Week: "NONE"})).To(Succeed())
+			cal, err = qc.ShowCalendar("night")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cal.Name).To(Equal("night"))
+			Expect(cal.Week).To(Equal("NONE"))
+
+			names, err := qc.ShowCalendars()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"night"}))
+
+			Expect(qc.DeleteCalendar("night")).To(Succeed())
+			_, err = qc.ShowCalendar("night")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not exist"))
+		})
+
+		It("applies queue defaults on add", func() {
+			Expect(qc.AddClusterQueue(core.ClusterQueueConfig{Name: "all.q"})).To(Succeed())
+			q, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(q.HostList).To(Equal([]string{"NONE"}))
+			Expect(q.Slots).NotTo(BeEmpty())
+		})
+
+		It("resolves nested host groups", func() {
+			hosts, err := qc.ShowHostGroupResolved("@allhosts")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hosts).To(ConsistOf("node1", "node2", "node3"))
+		})
+
+		It("manages admin hosts as a list", func() {
+			Expect(qc.AddAdminHost([]string{"h1", "h2"})).To(Succeed())
+			Expect(qc.AddAdminHost([]string{"h1"})).NotTo(Succeed())
+			Expect(qc.DeleteAdminHost([]string{"h1"})).To(Succeed())
+			hosts, err := qc.ShowAdminHosts()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hosts).To(Equal([]string{"h2"}))
+		})
+
+		It("creates a missing access list when adding a user to it", func() {
+			Expect(qc.AddUserToUserSetList("alice", "staff")).To(Succeed())
+			Expect(qc.AddUserToUserSetList("alice", "staff")).To(Succeed())
+			us, err := qc.ShowUserSetList("staff")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(us.Type).To(Equal("ACL"))
+			Expect(us.Entries).To(Equal([]string{"alice"}))
+		})
+
+		It("isolates stored objects from caller modifications", func() {
+			hg, err := qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			hg.Hosts[0] = "changed"
+
+			cc, err := qc.GetClusterConfiguration()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cc.HostGroups["@rack1"].Hosts).To(Equal([]string{"node1", "node2"}))
+			cc.HostGroups["@rack1"].Hosts[1] = "changed"
+
+			hg, err = qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hg.Hosts).To(Equal([]string{"node1", "node2"}))
+		})
+
+		It("reports an existing exec host once in the error", func() {
+			host := core.HostExecConfig{Name: "node1"}
+			Expect(qc.AddExecHost(host)).To(Succeed())
+			err := qc.AddExecHost(host)
+			Expect(err).To(HaveOccurred())
+			Expect(strings.Count(err.Error(), "already exists")).To(Equal(1))
+			Expect(errors.Is(err, qerror.ErrAlreadyExists)).To(BeTrue())
+		})
+
+		It("rejects exec hosts and checkpointing interfaces without a name", func() {
+			Expect(qc.AddExecHost(core.HostExecConfig{})).NotTo(Succeed())
+			Expect(qc.AddCkptInterface(core.CkptInterfaceConfig{})).NotTo(Succeed())
+		})
+	})
+
+	Context("attributes", func() {
+
+		BeforeEach(func() {
+			Expect(qc.AddClusterQueue(core.ClusterQueueConfig{
+				Name:     "all.q",
+				HostList: []string{"@allhosts"},
+				Slots:    []string{"1"},
+			})).To(Succeed())
+		})
+
+		It("sets a host group override with a queue instance id", func() {
+			Expect(qc.ModifyAttribute("queue", "slots", "4", "all.q@@rack1")).To(Succeed())
+			q, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(q.Slots).To(Equal([]string{"1", "[@rack1=4]"}))
+		})
+
+		It("returns ErrNoModification when adding an existing element", func() {
+			Expect(qc.AddAttribute("hostgroup", "hostlist", "node4", "@rack1")).To(Succeed())
+			err := qc.AddAttribute("hostgroup", "hostlist", "node4", "@rack1")
+			Expect(errors.Is(err, core.ErrNoModification)).To(BeTrue())
+
+			hg, err := qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hg.Hosts).To(Equal([]string{"node1", "node2", "node4"}))
+		})
+
+		It("replaces a list with -rattr", func() {
+			Expect(qc.ReplaceAttribute("hostgroup", "hostlist", "node3 node1", "@rack1")).To(Succeed())
+			hg, err := qc.ShowHostGroup("@rack1")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(hg.Hosts).To(Equal([]string{"node3", "node1"}))
+		})
+
+		It("fails for unknown objects", func() {
+			err := qc.ModifyAttribute("queue", "slots", "2", "missing.q")
+			Expect(err).To(HaveOccurred())
+			Expect(err.Error()).To(ContainSubstring("does not exist"))
+		})
+	})
+
+	Context("cluster configuration", func() {
+
+		It("round-trips Apply", func() {
+			desired := core.ClusterConfig{
+				HostGroups: map[string]core.HostGroupConfig{
+					"@rack1": {Name: "@rack1", Hosts: []string{"node1"}},
+				},
+				Projects: map[string]core.ProjectConfig{
+					"p1": {Name: "p1", ACL: []string{"NONE"}, XACL: []string{"NONE"}},
+				},
+				Managers: []string{"root"},
+			}
+			Expect(core.Apply(qc, desired, false)).To(Succeed())
+
+			cc, err := qc.GetClusterConfiguration()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cc.HostGroups).To(HaveLen(1))
+			Expect(cc.HostGroups["@rack1"].Hosts).To(Equal([]string{"node1"}))
+			Expect(cc.Projects).To(HaveKey("p1"))
+			Expect(cc.Managers).To(Equal([]string{"root"}))
+		})
+
+		It("keeps all complexes when ModifyAllEntries modifies one", func() {
+			Expect(qc.AddComplexEntry(core.ComplexEntryConfig{Name: "a", Shortcut: "a", Type: "INT"})).To(Succeed())
+			Expect(qc.AddComplexEntry(core.ComplexEntryConfig{Name: "b", Shortcut: "b", Type: "INT"})).To(Succeed())
+
+			_, err := core.ModifyAllEntries(qc, core.ClusterConfig{
+				ComplexEntries: map[string]core.ComplexEntryConfig{
+					"a": {Name: "a", Shortcut: "a", Type: "DOUBLE"},
+				},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			names, err := qc.ShowComplexEntries()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(names).To(Equal([]string{"a", "b"}))
+			a, err := qc.ShowComplexEntry("a")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(a.Type).To(Equal("DOUBLE"))
+		})
+
+		It("reports only the operators DeleteAllEnries deleted", func() {
+			Expect(qc.AddUserToOperatorList([]string{"op1"})).To(Succeed())
+
+			deleted, err := core.DeleteAllEnries(qc, core.ClusterConfig{
+				Operators: []string{"op1", "op2"},
+			}, true)
+			Expect(err).To(HaveOccurred())
+			Expect(deleted.Operators).To(Equal([]string{"op1"}))
+		})
+	})
+
+	Context("share tree", func() {
+
+		It("returns ErrNoShareTree when no tree is configured", func() {
+			_, err := qc.ShowShareTreeStructured()
+			Expect(errors.Is(err, core.ErrNoShareTree)).To(BeTrue())
+			_, err = qc.ShowShareTree()
+			Expect(errors.Is(err, core.ErrNoShareTree)).To(BeTrue())
+		})
+
+		It("edits the structured tree", func() {
+			Expect(qc.ModifyShareTreeStructured(&core.StructuredShareTree{
+				Root: &core.StructuredShareTreeNode{
+					Name:   "Root",
+					Type:   core.ShareTreeNodeUser,
+					Shares: 1,
+					Children: []*core.StructuredShareTreeNode{
+						{Name: "default", Type: core.ShareTreeNodeUser, Shares: 10},
+					},
+				},
+			})).To(Succeed())
+
+			Expect(qc.AddShareTreeNode(core.ShareTreeNode{Node: "/Root/alice", Share: 50})).To(Succeed())
+			Expect(qc.ModifyShareTreeNodes([]core.ShareTreeNode{{Node: "/Root/default", Share: 20}})).To(Succeed())
+
+			nodes, err := qc.ShowShareTreeNodes(nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(nodes).To(ContainElement(core.ShareTreeNode{Node: "/default", Share: 20}))
+			Expect(nodes).To(ContainElement(core.ShareTreeNode{Node: "/alice", Share: 50}))
+
+			Expect(qc.DeleteShareTreeSubtree("/Root/alice")).To(Succeed())
+			tree, err := qc.ShowShareTreeStructured()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tree.Root.Children).To(HaveLen(1))
+
+			Expect(qc.DeleteShareTree()).To(Succeed())
+			_, err = qc.ShowShareTreeStructured()
+			Expect(errors.Is(err, core.ErrNoShareTree)).To(BeTrue())
+		})
+	})
+})
@@ -27,6 +27,8 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 )
 
 // ClearUsage clears all user/project sharetree usage.
@@ -43,7 +45,7 @@ func (c *CommandLineQConf) ClearShareTreeUsage() error {
 func (c *CommandLineQConf) ShowShareTree() (string, error) {
 	stree, err := c.RunCommand("-sstree")
 	if err != nil {
-		if strings.Contains(err.Error(), "no sharetree") {
+		if errors.Is(err, qerror.ErrNotExist) {
 			return "", fmt.Errorf("%w: %s", ErrNoShareTree, strings.TrimSpace(stree))
 		}
 		return "", err
@@ -164,14 +166,14 @@ func (c *CommandLineQConf) ModifyShareTree(shareTreeConfig string) error {
 		_, err := c.RunCommand("-dstree")
 		if err != nil {
 			// Ignore "sharetree does not exist"
-			if !strings.Contains(err.Error(), "sharetree does not exist") {
+			if !errors.Is(err, qerror.ErrNotExist) {
 				return err
 			}
 		}
 		return nil
 	}
 
-	file, err := CreateTempDirWithFileName("sharetree")
+	file, err := c.createObjectFile("sharetree")
 	if err != nil {
 		return err
 	}
@@ -355,7 +357,7 @@ func (c *CommandLineQConf) ShowShareTreeMonitoring() (*ShareTreeMonitoring, erro
 			Nodes:       map[string]ShareTreeNodeStats{},
 		}, nil
 	}
-	r, err := c.shareMonRunner()
+	r, err := c.shareMonRunner(c.Context())
 	if err != nil {
 		return nil, err
 	}
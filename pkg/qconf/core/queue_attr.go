@@ -0,0 +1,165 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// QueueAttr is a cluster queue attribute in typed form: the cluster-wide
+// Default and the values for single hosts or host groups ("@name") which
+// override it. In ClusterQueueConfig the same attribute is kept as raw
+// strings, like []string{"1", "[@gpu=4]", "[node7=8]"} for slots.
+type QueueAttr[T any] struct {
+	Default   T            `json:"default"`
+	Overrides map[string]T `json:"overrides,omitempty"`
+}
+
+// ParseQueueAttr converts the string form of a single-valued queue
+// attribute, like the Slots or Priority of a ClusterQueueConfig, into a
+// QueueAttr using parse for the values:
+//
+//	slots, err := ParseQueueAttr(q.Slots, strconv.Atoi)
+func ParseQueueAttr[T any](values []string, parse func(string) (T, error)) (QueueAttr[T], error) {
+	var attr QueueAttr[T]
+	var defaults []string
+	for _, v := range values {
+		host, value, ok := parseOverride(v)
+		if !ok {
+			defaults = append(defaults, v)
+			continue
+		}
+		parsed, err := parse(value)
+		if err != nil {
+			return QueueAttr[T]{}, fmt.Errorf("invalid value for %s: %w", host, err)
+		}
+		if attr.Overrides == nil {
+			attr.Overrides = make(map[string]T)
+		}
+		attr.Overrides[host] = parsed
+	}
+	if len(defaults) > 0 {
+		parsed, err := parse(strings.Join(defaults, ","))
+		if err != nil {
+			return QueueAttr[T]{}, fmt.Errorf("invalid default value: %w", err)
+		}
+		attr.Default = parsed
+	}
+	return attr, nil
+}
+
+// FormatQueueAttr converts a QueueAttr back into the string form used by
+// ClusterQueueConfig, using format for the values. The overrides of host
+// groups come first, each group sorted by name.
+func FormatQueueAttr[T any](attr QueueAttr[T], format func(T) string) []string {
+	values := []string{format(attr.Default)}
+	hosts := sortedKeys(attr.Overrides)
+	slices.SortStableFunc(hosts, func(a, b string) int {
+		return cmp.Compare(hostGroupRank(a), hostGroupRank(b))
+	})
+	for _, host := range hosts {
+		values = append(values, fmt.Sprintf("[%s=%s]", host, format(attr.Overrides[host])))
+	}
+	return values
+}
+
+// ParseQueueListAttr converts the string form of a list-valued queue
+// attribute into a QueueAttr. sep separates the values of an override:
+// " " for lists like pe_list and user_lists, "," for complex_values.
+// "NONE" is an empty list.
+func ParseQueueListAttr(values []string, sep string) QueueAttr[[]string] {
+	attr, _ := ParseQueueAttr(values, func(s string) ([]string, error) {
+		fields := strings.FieldsFunc(s, isListSeparator)
+		if sep == "," {
+			fields = strings.Split(s, ",")
+		}
+		var list []string
+		for _, v := range fields {
+			if v = strings.TrimSpace(v); v != "" && v != "NONE" {
+				list = append(list, v)
+			}
+		}
+		return list, nil
+	})
+	return attr
+}
+
+// FormatQueueListAttr converts a list-valued QueueAttr back into the
+// string form used by ClusterQueueConfig. An empty default list is
+// written as "NONE" when there are overrides; an attribute without any
+// values is nil.
+func FormatQueueListAttr(attr QueueAttr[[]string], sep string) []string {
+	values := FormatQueueAttr(attr, func(list []string) string {
+		return JoinList(list, sep)
+	})
+	if len(attr.Default) > 0 {
+		values = append(append([]string(nil), attr.Default...), values[1:]...)
+	} else if len(values) == 1 {
+		return nil
+	}
+	return values
+}
+
+// hostGroupRank orders host groups ("@name") before hosts.
+func hostGroupRank(hostOrGroup string) int {
+	if strings.HasPrefix(hostOrGroup, "@") {
+		return 0
+	}
+	return 1
+}
+
+// queueInstance returns the qconf name of the queue instance of queue on
+// a host or, with a leading "@", a host group: "all.q@node1" or
+// "all.q@@gpu".
+func queueInstance(queue, hostOrGroup string) string {
+	return queue + "@" + hostOrGroup
+}
+
+// SetQueueAttrOverride sets the value of attribute attrName of queue for
+// a host or host group ("@name") with qconf -mattr, leaving all other
+// values of the queue untouched:
+//
+//	SetQueueAttrOverride(qc, "all.q", "slots", "@gpu", "4")
+func SetQueueAttrOverride(qc QConf, queue, attrName, hostOrGroup, value string) error {
+	return qc.ModifyAttribute("queue", attrName, value, queueInstance(queue, hostOrGroup))
+}
+
+// AddQueueAttrOverrideValue adds value to a list-valued attribute of
+// queue for a host or host group with qconf -aattr. Without an override
+// the new one starts from the cluster-wide value.
+func AddQueueAttrOverrideValue(qc QConf, queue, attrName, hostOrGroup, value string) error {
+	return qc.AddAttribute("queue", attrName, value, queueInstance(queue, hostOrGroup))
+}
+
+// DeleteQueueAttrOverrideValue removes value from a list-valued
+// attribute of queue for a host or host group with qconf -dattr.
+func DeleteQueueAttrOverrideValue(qc QConf, queue, attrName, hostOrGroup, value string) error {
+	return qc.DeleteAttribute("queue", attrName, value, queueInstance(queue, hostOrGroup))
+}
+
+// RemoveQueueAttrOverride removes the value of attribute attrName of
+// queue for a host or host group with qconf -purge, so the cluster-wide
+// value applies there again. qc must be an AttributePurger.
+func RemoveQueueAttrOverride(qc QConf, queue, attrName, hostOrGroup string) error {
+	return purgeAttribute(qc, "queue", attrName, queueInstance(queue, hostOrGroup))
+}
@@ -0,0 +1,140 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"errors"
+	"strconv"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core/internal/fakeqconf"
+)
+
+var _ = Describe("QueueAttr", func() {
+
+	Context("conversion", func() {
+
+		It("parses and formats a single-valued attribute", func() {
+			slots, err := core.ParseQueueAttr([]string{"1", "[@gpu=4]", "[node7=8]"}, strconv.Atoi)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(slots).To(Equal(core.QueueAttr[int]{
+				Default:   1,
+				Overrides: map[string]int{"@gpu": 4, "node7": 8},
+			}))
+
+			slots.Overrides["@gpu"] = 16
+			delete(slots.Overrides, "node7")
+			Expect(core.FormatQueueAttr(slots, strconv.Itoa)).To(Equal([]string{"1", "[@gpu=16]"}))
+		})
+
+		It("formats host group overrides before host overrides", func() {
+			slots := core.QueueAttr[int]{
+				Default:   1,
+				Overrides: map[string]int{"node7": 8, "@gpu": 4, "10node": 2, "@big": 16},
+			}
+			Expect(core.FormatQueueAttr(slots, strconv.Itoa)).To(Equal([]string{
+				"1", "[@big=16]", "[@gpu=4]", "[10node=2]", "[node7=8]"}))
+		})
+
+		It("reports values which do not parse", func() {
+			_, err := core.ParseQueueAttr([]string{"1", "[node7=many]"}, strconv.Atoi)
+			Expect(err).To(MatchError(ContainSubstring("node7")))
+		})
+
+		It("round-trips list attributes", func() {
+			peList := []string{"make", "mpi", "[@gpu=mpi smp]", "[node1=NONE]"}
+			attr := core.ParseQueueListAttr(peList, " ")
+			Expect(attr.Default).To(Equal([]string{"make", "mpi"}))
+			Expect(attr.Overrides).To(Equal(map[string][]string{
+				"@gpu": {"mpi", "smp"}, "node1": nil}))
+			Expect(core.FormatQueueListAttr(attr, " ")).To(Equal(peList))
+
+			complexValues := []string{"NONE", "[node1=gpu=2,mem_free=4G]"}
+			cv := core.ParseQueueListAttr(complexValues, ",")
+			Expect(cv.Default).To(BeEmpty())
+			Expect(cv.Overrides["node1"]).To(Equal([]string{"gpu=2", "mem_free=4G"}))
+			Expect(core.FormatQueueListAttr(cv, ",")).To(Equal(complexValues))
+
+			Expect(core.FormatQueueListAttr(core.ParseQueueListAttr(nil, " "), " ")).To(BeNil())
+		})
+	})
+
+	Context("single override changes", func() {
+
+		var qc *core.InMemoryQConf
+
+		BeforeEach(func() {
+			qc = newInMemoryQConf(core.ClusterConfig{
+				ClusterQueues: map[string]core.ClusterQueueConfig{
+					"all.q": {Name: "all.q", HostList: []string{"@allhosts"},
+						Slots: []string{"1", "[node7=8]"}, PeList: []string{"make"}},
+				},
+			})
+		})
+
+		queue := func() core.ClusterQueueConfig {
+			q, err := qc.ShowClusterQueue("all.q")
+			Expect(err).NotTo(HaveOccurred())
+			return q
+		}
+
+		It("sets, extends and removes host group overrides", func() {
+			Expect(core.SetQueueAttrOverride(qc, "all.q", "slots", "@gpu", "4")).To(Succeed())
+			Expect(queue().Slots).To(Equal([]string{"1", "[node7=8]", "[@gpu=4]"}))
+
+			Expect(core.AddQueueAttrOverrideValue(qc, "all.q", "pe_list", "@gpu", "mpi")).To(Succeed())
+			Expect(queue().PeList).To(Equal([]string{"make", "[@gpu=make mpi]"}))
+			Expect(core.DeleteQueueAttrOverrideValue(qc, "all.q", "pe_list", "@gpu", "make")).To(Succeed())
+			Expect(queue().PeList).To(Equal([]string{"make", "[@gpu=mpi]"}))
+
+			Expect(core.RemoveQueueAttrOverride(qc, "all.q", "slots", "node7")).To(Succeed())
+			Expect(queue().Slots).To(Equal([]string{"1", "[@gpu=4]"}))
+		})
+
+		It("reports purging an override which is not set", func() {
+			err := core.RemoveQueueAttrOverride(qc, "all.q", "slots", "@gpu")
+			Expect(errors.Is(err, core.ErrNoModification)).To(BeTrue())
+			Expect(qc.PurgeAttribute("queue", "slots", "all.q")).To(HaveOccurred())
+			Expect(qc.PurgeAttribute("exechost", "slots", "all.q@node7")).To(HaveOccurred())
+		})
+
+		It("reports QConf implementations which cannot purge", func() {
+			err := core.RemoveQueueAttrOverride(struct{ core.QConf }{qc}, "all.q", "slots", "node7")
+			Expect(errors.Is(err, errors.ErrUnsupported)).To(BeTrue())
+			Expect(queue().Slots).To(Equal([]string{"1", "[node7=8]"}))
+		})
+
+		It("runs qconf -purge", func() {
+			if !fakeqconf.Available() {
+				Skip("fakeqconf uses a bash script; skip on this platform")
+			}
+			f := fakeqconf.New(GinkgoT(), `root@master modified "all.q" in cluster queue list`, 0)
+			defer f.Cleanup()
+			cqc, err := core.NewCommandLineQConf(core.CommandLineQConfConfig{Executable: f.Path()})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(core.RemoveQueueAttrOverride(cqc, "all.q", "slots,pe_list", "@gpu")).To(Succeed())
+			Expect(f.Argv()).To(Equal([]string{"-purge", "queue", "slots,pe_list", "all.q@@gpu"}))
+		})
+	})
+})
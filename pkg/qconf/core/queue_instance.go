@@ -0,0 +1,208 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// queueSpaceListFields are the cluster queue attributes whose values are
+// space-separated lists of names.
+var queueSpaceListFields = map[string]bool{
+	"qtype": true, "ckpt_list": true, "pe_list": true, "owner_list": true,
+	"user_lists": true, "xuser_lists": true, "subordinate_list": true,
+	"projects": true, "xprojects": true, "calendar": true, "initial_state": true,
+}
+
+// queueCommaListFields are the cluster queue attributes whose values are
+// comma-separated lists of name=value pairs.
+var queueCommaListFields = map[string]bool{
+	"load_thresholds": true, "suspend_thresholds": true, "complex_values": true,
+}
+
+// QueueInstanceConfig is the effective configuration of a queue instance
+// queue@host as returned by ResolveQueueInstance. Every attribute of the
+// embedded ClusterQueueConfig holds the value in effect on Host, without
+// overrides, and HostList is just Host.
+type QueueInstanceConfig struct {
+	ClusterQueueConfig
+	Host string `json:"host"`
+	// Sources maps the JSON name of every attribute whose value comes from
+	// an override to the host or host group of that override. Attributes
+	// not listed have the cluster-wide value.
+	Sources map[string]string `json:"sources,omitempty"`
+	// Ambiguous lists the attributes for which Host is in several host
+	// groups with differing overrides. qmaster puts such an instance into
+	// the configuration ambiguous (c) state and uses the cluster-wide
+	// value, and so does ResolveQueueInstance.
+	Ambiguous []string `json:"ambiguous,omitempty"`
+}
+
+// ResolveQueueInstance returns the effective configuration of the queue
+// instance queue@host in cc. An override for the host itself takes
+// precedence over an override for a host group containing the host,
+// directly or through nested host groups, which takes precedence over the
+// cluster-wide value.
+//
+// It fails when the queue is not defined in cc or host is not in its
+// hostlist.
+func ResolveQueueInstance(cc ClusterConfig, queue, host string) (QueueInstanceConfig, error) {
+	q, ok := cc.ClusterQueues[queue]
+	if !ok {
+		return QueueInstanceConfig{}, fmt.Errorf("cluster queue %q does not exist", queue)
+	}
+	if !containsHost(queueHosts(cc, q), host) {
+		return QueueInstanceConfig{}, fmt.Errorf("host %q is not in the hostlist of cluster queue %q",
+			host, queue)
+	}
+
+	// groups holds for every host group whether it contains host.
+	groups := make(map[string]bool)
+	inGroup := func(group string) bool {
+		in, ok := groups[group]
+		if !ok {
+			in = containsHost(resolveHostGroup(cc.HostGroups, group), host)
+			groups[group] = in
+		}
+		return in
+	}
+
+	qi := QueueInstanceConfig{ClusterQueueConfig: deepCopy(q), Host: host}
+	qi.HostList = []string{host}
+	forEachQueueList(&qi.ClusterQueueConfig, func(field string, entries *[]string) {
+		if field == "hostlist" {
+			return
+		}
+		var defaults []string
+		var hostValue, groupValue, group string
+		hostSet, ambiguous := false, false
+		for _, entry := range *entries {
+			target, value, ok := parseOverride(entry)
+			switch {
+			case !ok:
+				defaults = append(defaults, entry)
+			case strings.HasPrefix(target, "@"):
+				if !inGroup(target) {
+					continue
+				}
+				if group != "" && value != groupValue {
+					ambiguous = true
+				}
+				if group == "" {
+					group, groupValue = target, value
+				}
+			case strings.EqualFold(target, host):
+				hostSet, hostValue = true, value
+			}
+		}
+		switch {
+		case hostSet:
+			*entries = splitQueueValue(field, hostValue)
+			qi.setSource(field, host)
+		case ambiguous:
+			*entries = defaults
+			qi.Ambiguous = append(qi.Ambiguous, field)
+		case group != "":
+			*entries = splitQueueValue(field, groupValue)
+			qi.setSource(field, group)
+		default:
+			*entries = defaults
+		}
+	})
+	return qi, nil
+}
+
+func (qi *QueueInstanceConfig) setSource(field, hostOrGroup string) {
+	if qi.Sources == nil {
+		qi.Sources = make(map[string]string)
+	}
+	qi.Sources[field] = hostOrGroup
+}
+
+// splitQueueValue splits the value of an override of a cluster queue
+// attribute into entries like the ones of its cluster-wide value.
+func splitQueueValue(field, value string) []string {
+	switch {
+	case queueSpaceListFields[field]:
+		return strings.FieldsFunc(value, isListSeparator)
+	case queueCommaListFields[field]:
+		return strings.Split(value, ",")
+	}
+	return []string{value}
+}
+
+// queueHosts returns the hosts of the hostlist of q with all host groups
+// resolved.
+func queueHosts(cc ClusterConfig, q ClusterQueueConfig) []string {
+	var hosts []string
+	for _, member := range q.HostList {
+		switch {
+		case member == "" || strings.EqualFold(member, "NONE"):
+		case strings.HasPrefix(member, "@"):
+			hosts = append(hosts, resolveHostGroup(cc.HostGroups, member)...)
+		default:
+			hosts = append(hosts, member)
+		}
+	}
+	return hosts
+}
+
+// resolveHostGroup returns all hosts of the host group name, with nested
+// host groups expanded. Each host is reported once; groups which are not
+// defined are empty.
+func resolveHostGroup(groups map[string]HostGroupConfig, name string) []string {
+	var hosts []string
+	seen := make(map[string]bool)
+	visited := make(map[string]bool)
+	var resolve func(name string)
+	resolve = func(name string) {
+		if visited[name] {
+			return
+		}
+		visited[name] = true
+		for _, member := range groups[name].Hosts {
+			if member == "" || strings.EqualFold(member, "NONE") {
+				continue
+			}
+			if strings.HasPrefix(member, "@") {
+				resolve(member)
+				continue
+			}
+			if !seen[member] {
+				seen[member] = true
+				hosts = append(hosts, member)
+			}
+		}
+	}
+	resolve(name)
+	return hosts
+}
+
+// containsHost reports whether host is in hosts. Host names are compared
+// case-insensitively, like qmaster does.
+func containsHost(hosts []string, host string) bool {
+	for _, h := range hosts {
+		if strings.EqualFold(h, host) {
+			return true
+		}
+	}
+	return false
+}
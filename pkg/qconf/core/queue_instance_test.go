@@ -0,0 +1,99 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("ResolveQueueInstance", func() {
+
+	var cc core.ClusterConfig
+
+	BeforeEach(func() {
+		q := core.ClusterQueueConfig{
+			Name:          "all.q",
+			HostList:      []string{"@allhosts", "node9"},
+			Slots:         []string{"1", "[@gpu=4]", "[node42=8]"},
+			HVmem:         []string{"INFINITY", "[@rack1=64G]"},
+			Prolog:        []string{"NONE", "[node42=root@/opt/prolog.sh -v]"},
+			PeList:        []string{"make", "[@gpu=make mpi]"},
+			ComplexValues: []string{"NONE", "[@gpu=gpu=4,mem_free=128G]"},
+			Priority:      []string{"0", "[@gpu=5]", "[@rack1=10]"},
+		}
+		core.SetDefaultQueueValues(&q)
+		cc = core.ClusterConfig{
+			ClusterQueues: map[string]core.ClusterQueueConfig{"all.q": q},
+			HostGroups: map[string]core.HostGroupConfig{
+				"@allhosts": {Name: "@allhosts", Hosts: []string{"@rack1", "@gpu"}},
+				"@rack1":    {Name: "@rack1", Hosts: []string{"@gpu", "node1"}},
+				"@gpu":      {Name: "@gpu", Hosts: []string{"node42"}},
+			},
+		}
+	})
+
+	It("prefers host overrides over host group overrides over the default", func() {
+		qi, err := core.ResolveQueueInstance(cc, "all.q", "node42")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qi.Name).To(Equal("all.q"))
+		Expect(qi.Host).To(Equal("node42"))
+		Expect(qi.HostList).To(Equal([]string{"node42"}))
+		Expect(qi.Slots).To(Equal([]string{"8"}))
+		Expect(qi.Prolog).To(Equal([]string{"root@/opt/prolog.sh -v"}))
+		Expect(qi.HVmem).To(Equal([]string{"64G"}))
+		Expect(qi.PeList).To(Equal([]string{"make", "mpi"}))
+		Expect(qi.ComplexValues).To(Equal([]string{"gpu=4", "mem_free=128G"}))
+		Expect(qi.Shell).To(Equal([]string{"/bin/sh"}))
+		Expect(qi.Sources).To(Equal(map[string]string{
+			"slots": "node42", "prolog": "node42", "h_vmem": "@rack1",
+			"pe_list": "@gpu", "complex_values": "@gpu",
+		}))
+	})
+
+	It("falls back to the default for ambiguous host group overrides", func() {
+		qi, err := core.ResolveQueueInstance(cc, "all.q", "NODE42")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qi.Priority).To(Equal([]string{"0"}))
+		Expect(qi.Ambiguous).To(Equal([]string{"priority"}))
+
+		qi, err = core.ResolveQueueInstance(cc, "all.q", "node1")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qi.Priority).To(Equal([]string{"10"}))
+		Expect(qi.Slots).To(Equal([]string{"1"}))
+		Expect(qi.Ambiguous).To(BeEmpty())
+	})
+
+	It("resolves plain hosts of the hostlist", func() {
+		qi, err := core.ResolveQueueInstance(cc, "all.q", "node9")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(qi.HVmem).To(Equal([]string{"INFINITY"}))
+		Expect(qi.Sources).To(BeEmpty())
+	})
+
+	It("fails for unknown queues and hosts outside the hostlist", func() {
+		_, err := core.ResolveQueueInstance(cc, "gpu.q", "node42")
+		Expect(err).To(MatchError(ContainSubstring(`cluster queue "gpu.q" does not exist`)))
+		_, err = core.ResolveQueueInstance(cc, "all.q", "node2")
+		Expect(err).To(MatchError(ContainSubstring(`host "node2" is not in the hostlist`)))
+	})
+})
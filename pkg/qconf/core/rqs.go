@@ -0,0 +1,252 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RQSRule is a parsed rule of a resource quota set, one entry of
+// ResourceQuotaSetConfig.Limits:
+//
+//	name gpu_users users {*} projects !p1 hosts {@gpu} to slots=$num_proc*2
+//
+// A nil filter does not restrict the jobs the rule applies to.
+type RQSRule struct {
+	Name     string     `json:"name,omitempty"`
+	Users    *RQSFilter `json:"users,omitempty"`
+	Projects *RQSFilter `json:"projects,omitempty"`
+	PEs      *RQSFilter `json:"pes,omitempty"`
+	Queues   *RQSFilter `json:"queues,omitempty"`
+	Hosts    *RQSFilter `json:"hosts,omitempty"`
+	Limits   []RQSLimit `json:"limits"`
+}
+
+// RQSFilter is the list of a filter of an RQSRule. With Expand, written
+// in braces, the limit applies to every matching user, project, parallel
+// environment, queue or host on its own; otherwise it applies to all of
+// them together.
+type RQSFilter struct {
+	Expand  bool             `json:"expand,omitempty"`
+	Entries []RQSFilterEntry `json:"entries"`
+}
+
+// RQSFilterEntry is an entry of an RQSFilter: a name, a wildcard pattern
+// or, for users and hosts, a user set or host group prefixed with "@".
+// A Negated entry ("!name") excludes what it matches.
+type RQSFilterEntry struct {
+	Name    string `json:"name"`
+	Negated bool   `json:"negated,omitempty"`
+}
+
+// RQSLimit is a limit of an RQSRule, like "slots=10". Value is either a
+// static value or, for dynamic limits, a Formula of host complex values
+// like "$num_proc*2".
+type RQSLimit struct {
+	Resource string `json:"resource"`
+	Value    string `json:"value"`
+}
+
+// Dynamic reports whether the limit depends on the complex values of the
+// host it is checked for.
+func (l RQSLimit) Dynamic() bool {
+	return strings.Contains(l.Value, "$")
+}
+
+// rqsFilterKeywords are the filters of a rule in the order qconf writes
+// them.
+var rqsFilterKeywords = []string{"users", "projects", "pes", "queues", "hosts"}
+
+// filter returns a pointer to the filter of r for keyword.
+func (r *RQSRule) filter(keyword string) **RQSFilter {
+	switch keyword {
+	case "users":
+		return &r.Users
+	case "projects":
+		return &r.Projects
+	case "pes":
+		return &r.PEs
+	case "queues":
+		return &r.Queues
+	case "hosts":
+		return &r.Hosts
+	}
+	return nil
+}
+
+// ParseRQSRule parses a rule of a resource quota set as stored in
+// ResourceQuotaSetConfig.Limits. The "limit" keyword of the qconf -srqs
+// format is accepted as well.
+func ParseRQSRule(s string) (RQSRule, error) {
+	tokens := rqsTokens(s)
+	if len(tokens) > 0 && tokens[0] == "limit" {
+		tokens = tokens[1:]
+	}
+	var r RQSRule
+	for i := 0; i < len(tokens); i++ {
+		keyword := tokens[i]
+		if keyword == "to" {
+			limits, err := parseRQSLimits(strings.Join(tokens[i+1:], ""))
+			if err != nil {
+				return RQSRule{}, fmt.Errorf("invalid resource quota rule %q: %w", s, err)
+			}
+			r.Limits = limits
+			return r, nil
+		}
+		if i+1 == len(tokens) {
+			return RQSRule{}, fmt.Errorf("invalid resource quota rule %q: %s without value", s, keyword)
+		}
+		i++
+		if keyword == "name" {
+			r.Name = tokens[i]
+			continue
+		}
+		f := r.filter(keyword)
+		if f == nil {
+			return RQSRule{}, fmt.Errorf("invalid resource quota rule %q: unknown filter %q", s, keyword)
+		}
+		if *f != nil {
+			return RQSRule{}, fmt.Errorf("invalid resource quota rule %q: duplicate filter %q", s, keyword)
+		}
+		filter, err := parseRQSFilter(tokens[i])
+		if err != nil {
+			return RQSRule{}, fmt.Errorf("invalid resource quota rule %q: %s: %w", s, keyword, err)
+		}
+		*f = &filter
+	}
+	return RQSRule{}, fmt.Errorf("invalid resource quota rule %q: missing \"to\"", s)
+}
+
+// ParseRQSRules parses all rules of a resource quota set.
+func ParseRQSRules(limits []string) ([]RQSRule, error) {
+	rules := make([]RQSRule, 0, len(limits))
+	for _, limit := range limits {
+		r, err := ParseRQSRule(limit)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// FormatRQSRules returns the text form of rules for
+// ResourceQuotaSetConfig.Limits.
+func FormatRQSRules(rules []RQSRule) []string {
+	limits := make([]string, 0, len(rules))
+	for _, r := range rules {
+		limits = append(limits, r.String())
+	}
+	return limits
+}
+
+// rqsTokens splits a rule into words, keeping lists in braces like
+// "{a, b}" together.
+func rqsTokens(s string) []string {
+	var tokens []string
+	for _, word := range strings.Fields(s) {
+		last := len(tokens) - 1
+		if last >= 0 && strings.Count(tokens[last], "{") > strings.Count(tokens[last], "}") {
+			tokens[last] += word
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+func parseRQSFilter(s string) (RQSFilter, error) {
+	var f RQSFilter
+	if strings.HasPrefix(s, "{") || strings.HasSuffix(s, "}") {
+		if !strings.HasPrefix(s, "{") || !strings.HasSuffix(s, "}") {
+			return RQSFilter{}, fmt.Errorf("unbalanced braces in %q", s)
+		}
+		f.Expand, s = true, s[1:len(s)-1]
+	}
+	for _, name := range strings.Split(s, ",") {
+		entry := RQSFilterEntry{Name: name}
+		if strings.HasPrefix(name, "!") {
+			entry = RQSFilterEntry{Name: name[1:], Negated: true}
+		}
+		if entry.Name == "" || strings.ContainsAny(entry.Name, "{}!") {
+			return RQSFilter{}, fmt.Errorf("invalid entry %q", name)
+		}
+		f.Entries = append(f.Entries, entry)
+	}
+	return f, nil
+}
+
+func parseRQSLimits(s string) ([]RQSLimit, error) {
+	if s == "" {
+		return nil, fmt.Errorf("no limits after \"to\"")
+	}
+	var limits []RQSLimit
+	for _, pair := range strings.Split(s, ",") {
+		resource, value, ok := strings.Cut(pair, "=")
+		if !ok || !isComplexName(resource) || value == "" {
+			return nil, fmt.Errorf("invalid limit %q", pair)
+		}
+		l := RQSLimit{Resource: resource, Value: value}
+		if l.Dynamic() {
+			if _, err := ParseFormula(value); err != nil {
+				return nil, err
+			}
+		}
+		limits = append(limits, l)
+	}
+	return limits, nil
+}
+
+// String returns the text form of r as stored in
+// ResourceQuotaSetConfig.Limits, without the "limit" keyword.
+func (r RQSRule) String() string {
+	var words []string
+	if r.Name != "" {
+		words = append(words, "name", r.Name)
+	}
+	for _, keyword := range rqsFilterKeywords {
+		if f := *r.filter(keyword); f != nil {
+			words = append(words, keyword, f.String())
+		}
+	}
+	limits := make([]string, 0, len(r.Limits))
+	for _, l := range r.Limits {
+		limits = append(limits, l.Resource+"="+l.Value)
+	}
+	return strings.Join(append(words, "to", strings.Join(limits, ",")), " ")
+}
+
+// String returns the text form of f, like "{*}" or "!root,@staff".
+func (f RQSFilter) String() string {
+	entries := make([]string, 0, len(f.Entries))
+	for _, e := range f.Entries {
+		if e.Negated {
+			entries = append(entries, "!"+e.Name)
+		} else {
+			entries = append(entries, e.Name)
+		}
+	}
+	s := strings.Join(entries, ",")
+	if f.Expand {
+		return "{" + s + "}"
+	}
+	return s
+}
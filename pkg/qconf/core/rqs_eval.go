@@ -0,0 +1,304 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper"
+)
+
+// RQSRequest is a job placed into the queue instance Queue@Host, as seen
+// by EvaluateResourceQuotas.
+type RQSRequest struct {
+	User string `json:"user"`
+	// Groups are the UNIX groups of User, matched by "@group" entries
+	// of access lists.
+	Groups  []string `json:"groups,omitempty"`
+	Project string   `json:"project,omitempty"`
+	PE      string   `json:"pe,omitempty"`
+	Queue   string   `json:"queue"`
+	Host    string   `json:"host"`
+	// Resources are the requested amounts, memory in bytes and time in
+	// seconds. Without "slots" the job requests a single slot.
+	Resources map[string]float64 `json:"resources,omitempty"`
+	// HostValues are the complex values of Host, like num_proc, used
+	// by dynamic limits.
+	HostValues map[string]float64 `json:"host_values,omitempty"`
+}
+
+// RQSRuleInstance identifies a usage counter of a resource quota rule:
+// the rule Rule, its name or its 1-based index, of the set Set and, for
+// every filter in braces, the member the counter is for. Filters without
+// braces count all their members together and leave the member empty.
+type RQSRuleInstance struct {
+	Set     string `json:"set"`
+	Rule    string `json:"rule"`
+	User    string `json:"user,omitempty"`
+	Project string `json:"project,omitempty"`
+	PE      string `json:"pe,omitempty"`
+	Queue   string `json:"queue,omitempty"`
+	Host    string `json:"host,omitempty"`
+}
+
+// String returns the instance like qquota shows it, for example
+// "max_slots/1 users bob hosts node1".
+func (i RQSRuleInstance) String() string {
+	words := []string{i.Set + "/" + i.Rule}
+	for _, f := range []struct{ keyword, member string }{
+		{"users", i.User}, {"projects", i.Project}, {"pes", i.PE},
+		{"queues", i.Queue}, {"hosts", i.Host},
+	} {
+		if f.member != "" {
+			words = append(words, f.keyword, f.member)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// RQSUsage is the current usage of every rule instance by resource, in
+// the units of RQSRequest.Resources.
+type RQSUsage map[RQSRuleInstance]map[string]float64
+
+// RQSLimitStatus is a limit on a resource requested by a job. Headroom
+// is what is left of Limit after Used; Fits reports whether the request
+// is within the headroom.
+type RQSLimitStatus struct {
+	Instance  RQSRuleInstance `json:"instance"`
+	Resource  string          `json:"resource"`
+	Limit     float64         `json:"limit"`
+	Used      float64         `json:"used"`
+	Requested float64         `json:"requested"`
+	Headroom  float64         `json:"headroom"`
+	Fits      bool            `json:"fits"`
+}
+
+// EvaluateResourceQuotas returns the limits the enabled resource quota
+// sets of cc put on req. Like qmaster it uses the first rule of every set
+// whose filters match the job and checks the limits of that rule on the
+// resources the job requests against usage.
+//
+// The result is ordered by the share of each limit left after the
+// request, so the first status is the rule which caps the job. It is
+// empty when no rule limits the job.
+func EvaluateResourceQuotas(cc ClusterConfig, req RQSRequest, usage RQSUsage) ([]RQSLimitStatus, error) {
+	requested := func(resource string) (float64, bool) {
+		if v, ok := req.Resources[resource]; ok {
+			return v, true
+		}
+		return 1, resource == "slots"
+	}
+	var statuses []RQSLimitStatus
+	for _, name := range sortedKeys(cc.ResourceQuotaSets) {
+		rqs := cc.ResourceQuotaSets[name]
+		if !rqs.Enabled {
+			continue
+		}
+		rules, err := ParseRQSRules(rqs.Limits)
+		if err != nil {
+			return nil, fmt.Errorf("resource quota set %s: %w", name, err)
+		}
+		for i, r := range rules {
+			instance, ok := r.match(cc, req)
+			if !ok {
+				continue
+			}
+			instance.Set, instance.Rule = name, r.Name
+			if r.Name == "" {
+				instance.Rule = strconv.Itoa(i + 1)
+			}
+			for _, l := range r.Limits {
+				amount, ok := requested(l.Resource)
+				if !ok {
+					continue
+				}
+				limit, err := l.amount(req.HostValues)
+				if err != nil {
+					return nil, fmt.Errorf("resource quota set %s rule %s: %w", name, instance.Rule, err)
+				}
+				used := usage[instance][l.Resource]
+				statuses = append(statuses, RQSLimitStatus{
+					Instance:  instance,
+					Resource:  l.Resource,
+					Limit:     limit,
+					Used:      used,
+					Requested: amount,
+					Headroom:  limit - used,
+					Fits:      amount <= limit-used,
+				})
+			}
+			break
+		}
+	}
+	sort.SliceStable(statuses, func(i, j int) bool {
+		return statuses[i].left() < statuses[j].left()
+	})
+	return statuses, nil
+}
+
+// left returns the share of the limit left after the request, which
+// makes limits of resources in different units comparable.
+func (s RQSLimitStatus) left() float64 {
+	switch {
+	case math.IsInf(s.Limit, 1):
+		return math.Inf(1)
+	case s.Limit <= 0:
+		return s.Headroom - s.Requested
+	}
+	return (s.Headroom - s.Requested) / s.Limit
+}
+
+// match reports whether all filters of r match req and returns the rule
+// instance req is counted in.
+func (r RQSRule) match(cc ClusterConfig, req RQSRequest) (RQSRuleInstance, bool) {
+	var instance RQSRuleInstance
+	filters := []struct {
+		filter *RQSFilter
+		value  string
+		member *string
+		match  func(entry string) bool
+	}{
+		{r.Users, req.User, &instance.User, func(entry string) bool {
+			if set, ok := strings.CutPrefix(entry, "@"); ok {
+				return userSetContains(cc.UserSetLists[set], req.User, req.Groups)
+			}
+			return wildcardMatch(entry, req.User)
+		}},
+		{r.Projects, req.Project, &instance.Project, func(entry string) bool {
+			return wildcardMatch(entry, req.Project)
+		}},
+		{r.PEs, req.PE, &instance.PE, func(entry string) bool {
+			return wildcardMatch(entry, req.PE)
+		}},
+		{r.Queues, req.Queue, &instance.Queue, func(entry string) bool {
+			return wildcardMatch(entry, req.Queue)
+		}},
+		{r.Hosts, req.Host, &instance.Host, func(entry string) bool {
+			if strings.HasPrefix(entry, "@") {
+				return containsHost(resolveHostGroup(cc.HostGroups, entry), req.Host)
+			}
+			return wildcardMatch(strings.ToLower(entry), strings.ToLower(req.Host))
+		}},
+	}
+	for _, f := range filters {
+		if !f.filter.matches(f.match) {
+			return RQSRuleInstance{}, false
+		}
+		if f.filter != nil && f.filter.Expand {
+			*f.member = f.value
+		}
+	}
+	return instance, true
+}
+
+// matches reports whether a job is within f, where match reports whether
+// an entry matches the job. Entries only excluding something match every
+// job not excluded, even one without a value for the filter.
+func (f *RQSFilter) matches(match func(entry string) bool) bool {
+	if f == nil {
+		return true
+	}
+	included, positive := false, false
+	for _, e := range f.Entries {
+		m := match(e.Name)
+		if e.Negated {
+			if m {
+				return false
+			}
+			continue
+		}
+		positive = true
+		included = included || m
+	}
+	return included || !positive
+}
+
+// userSetContains reports whether user, or one of its UNIX groups as
+// "@group", is an entry of set.
+func userSetContains(set UserSetListConfig, user string, groups []string) bool {
+	for _, entry := range set.Entries {
+		if group, ok := strings.CutPrefix(entry, "@"); ok {
+			if slices.Contains(groups, group) {
+				return true
+			}
+		} else if entry == user {
+			return true
+		}
+	}
+	return false
+}
+
+// wildcardMatch reports whether s matches the shell pattern pattern. An
+// empty s matches nothing.
+func wildcardMatch(pattern, s string) bool {
+	if s == "" {
+		return false
+	}
+	matched, err := path.Match(pattern, s)
+	return matched || (err != nil && pattern == s)
+}
+
+// amount returns the value of l, computed from hostValues for dynamic
+// limits.
+func (l RQSLimit) amount(hostValues map[string]float64) (float64, error) {
+	if l.Dynamic() {
+		f, err := ParseFormula(l.Value)
+		if err != nil {
+			return 0, err
+		}
+		v, err := f.Eval(func(complex string) (float64, bool) {
+			v, ok := hostValues[complex]
+			return v, ok
+		})
+		if err != nil {
+			return 0, fmt.Errorf("dynamic limit %s=%s: %w", l.Resource, l.Value, err)
+		}
+		return v, nil
+	}
+	v, err := parseAmount(l.Value)
+	if err != nil {
+		return 0, fmt.Errorf("limit %s=%s: %w", l.Resource, l.Value, err)
+	}
+	return v, nil
+}
+
+// parseAmount parses a resource amount like a static limit or a load
+// value: a number, a memory value like "4G", a time like "01:00:00" or
+// INFINITY.
+func parseAmount(s string) (float64, error) {
+	if strings.EqualFold(s, "INFINITY") {
+		return math.Inf(1), nil
+	}
+	if v, err := strconv.ParseFloat(s, 64); err == nil {
+		return v, nil
+	}
+	if strings.Contains(s, ":") {
+		v, err := helper.ParseTimeResourceValueToSeconds(s)
+		return float64(v), err
+	}
+	v, err := helper.ParseMemoryFromString(s)
+	return float64(v), err
+}
@@ -0,0 +1,207 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"math"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("Resource quota rules", func() {
+
+	Context("parsing", func() {
+
+		It("parses filters, expansion, negation and dynamic limits", func() {
+			r, err := core.ParseRQSRule("limit name gpu users {*} projects !p1,p2 " +
+				"hosts {@gpu} to slots=$num_proc*2, h_vmem=4G")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(r).To(Equal(core.RQSRule{
+				Name:  "gpu",
+				Users: &core.RQSFilter{Expand: true, Entries: []core.RQSFilterEntry{{Name: "*"}}},
+				Projects: &core.RQSFilter{Entries: []core.RQSFilterEntry{
+					{Name: "p1", Negated: true}, {Name: "p2"}}},
+				Hosts: &core.RQSFilter{Expand: true, Entries: []core.RQSFilterEntry{{Name: "@gpu"}}},
+				Limits: []core.RQSLimit{
+					{Resource: "slots", Value: "$num_proc*2"},
+					{Resource: "h_vmem", Value: "4G"},
+				},
+			}))
+			Expect(r.Limits[0].Dynamic()).To(BeTrue())
+			Expect(r.Limits[1].Dynamic()).To(BeFalse())
+			Expect(r.String()).To(Equal("name gpu users {*} projects !p1,p2 hosts {@gpu} " +
+				"to slots=$num_proc*2,h_vmem=4G"))
+		})
+
+		It("round-trips rules through the formatter", func() {
+			limits := []string{
+				"users {@staff, bob} queues all.q to slots=10",
+				"pes mpi hosts {*} to slots=$num_proc",
+				"to slots=100",
+			}
+			rules, err := core.ParseRQSRules(limits)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(rules[0].Users.Entries).To(HaveLen(2))
+			Expect(core.FormatRQSRules(rules)).To(Equal([]string{
+				"users {@staff,bob} queues all.q to slots=10",
+				"pes mpi hosts {*} to slots=$num_proc",
+				"to slots=100",
+			}))
+		})
+
+		DescribeTable("rejects invalid rules",
+			func(rule, message string) {
+				_, err := core.ParseRQSRule(rule)
+				Expect(err).To(MatchError(ContainSubstring(message)))
+			},
+			Entry("missing to", "users {*}", `missing "to"`),
+			Entry("unknown filter", "groups g1 to slots=1", `unknown filter "groups"`),
+			Entry("duplicate filter", "users a users b to slots=1", `duplicate filter "users"`),
+			Entry("unbalanced braces", "users {a to slots=1", "unbalanced braces"),
+			Entry("empty entry", "users a,,b to slots=1", `invalid entry ""`),
+			Entry("missing limits", "users a to", "no limits"),
+			Entry("invalid limit", "users a to slots", `invalid limit "slots"`),
+			Entry("invalid formula", "hosts {*} to slots=$num_proc*$m_core", "multiplies two complex values"),
+		)
+	})
+
+	Context("formulas", func() {
+
+		It("parses, formats and evaluates weighted sums", func() {
+			f, err := core.ParseFormula("np_load_avg - $slots*0.5 + 2*cpu + 1e-3")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(f).To(Equal(core.Formula{
+				{Complex: "np_load_avg", Weight: 1},
+				{Complex: "slots", Weight: -0.5},
+				{Complex: "cpu", Weight: 2},
+				{Weight: 0.001},
+			}))
+			Expect(f.String()).To(Equal("np_load_avg-slots*0.5+cpu*2+0.001"))
+			Expect(f.Complexes()).To(Equal([]string{"np_load_avg", "slots", "cpu"}))
+
+			values := map[string]float64{"np_load_avg": 0.5, "slots": 4, "cpu": 10}
+			v, err := f.Eval(func(c string) (float64, bool) { x, ok := values[c]; return x, ok })
+			Expect(err).NotTo(HaveOccurred())
+			Expect(v).To(BeNumerically("~", 0.5-2+20+0.001))
+
+			_, err = f.Eval(func(string) (float64, bool) { return 0, false })
+			Expect(err).To(MatchError("no value for np_load_avg"))
+		})
+	})
+
+	Context("evaluation", func() {
+
+		var cc core.ClusterConfig
+
+		BeforeEach(func() {
+			cc = core.ClusterConfig{
+				UserSetLists: map[string]core.UserSetListConfig{
+					"staff": {Name: "staff", Entries: []string{"alice", "@admins"}},
+				},
+				HostGroups: map[string]core.HostGroupConfig{
+					"@gpu": {Name: "@gpu", Hosts: []string{"node42"}},
+				},
+				ResourceQuotaSets: map[string]core.ResourceQuotaSetConfig{
+					"max_user_slots": {Name: "max_user_slots", Enabled: true, Limits: []string{
+						"users root to slots=1000",
+						"name per_user users {*} to slots=20",
+					}},
+					"gpu_hosts": {Name: "gpu_hosts", Enabled: true, Limits: []string{
+						"users @staff hosts {@gpu} to slots=$num_proc*2,h_vmem=64G",
+					}},
+					"projects": {Name: "projects", Enabled: true, Limits: []string{
+						"projects !p1 to slots=5",
+					}},
+					"disabled": {Name: "disabled", Limits: []string{"to slots=0"}},
+				},
+			}
+		})
+
+		It("returns the capping rule first and the headroom of every limit", func() {
+			usage := core.RQSUsage{
+				{Set: "max_user_slots", Rule: "per_user", User: "bob"}: {"slots": 12},
+				{Set: "gpu_hosts", Rule: "1", Host: "node42"}:          {"slots": 10, "h_vmem": 60 << 30},
+			}
+			statuses, err := core.EvaluateResourceQuotas(cc, core.RQSRequest{
+				User: "bob", Groups: []string{"admins"}, Project: "p1", Queue: "all.q", Host: "node42",
+				Resources:  map[string]float64{"slots": 4, "h_vmem": 2 << 30},
+				HostValues: map[string]float64{"num_proc": 8},
+			}, usage)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(3))
+
+			Expect(statuses[0].Instance.String()).To(Equal("gpu_hosts/1 hosts node42"))
+			Expect(statuses[0].Resource).To(Equal("h_vmem"))
+			Expect(statuses[0].Headroom).To(Equal(float64(4 << 30)))
+
+			Expect(statuses[1].Resource).To(Equal("slots"))
+			Expect(statuses[1].Limit).To(Equal(16.0))
+			Expect(statuses[1].Headroom).To(Equal(6.0))
+			Expect(statuses[1].Fits).To(BeTrue())
+
+			Expect(statuses[2].Instance).To(Equal(core.RQSRuleInstance{
+				Set: "max_user_slots", Rule: "per_user", User: "bob"}))
+			Expect(statuses[2].Headroom).To(Equal(8.0))
+		})
+
+		It("uses only the first matching rule of a set", func() {
+			statuses, err := core.EvaluateResourceQuotas(cc, core.RQSRequest{
+				User: "root", Queue: "all.q", Host: "node1",
+			}, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses).To(HaveLen(2))
+			Expect(statuses[0].Instance.String()).To(Equal("projects/1"))
+			Expect(statuses[0].Requested).To(Equal(1.0))
+			Expect(statuses[1].Instance.String()).To(Equal("max_user_slots/1"))
+			Expect(statuses[1].Limit).To(Equal(1000.0))
+		})
+
+		It("reports a request which exceeds the headroom", func() {
+			statuses, err := core.EvaluateResourceQuotas(cc, core.RQSRequest{
+				User: "carol", Project: "p2", Queue: "all.q", Host: "node1",
+				Resources: map[string]float64{"slots": 6},
+			}, core.RQSUsage{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses[0].Instance.Set).To(Equal("projects"))
+			Expect(statuses[0].Fits).To(BeFalse())
+		})
+
+		It("handles unlimited values and fails on missing host values", func() {
+			cc.ResourceQuotaSets = map[string]core.ResourceQuotaSetConfig{
+				"dyn": {Name: "dyn", Enabled: true, Limits: []string{
+					"hosts {*} to slots=$num_proc,h_rt=INFINITY"}},
+			}
+			req := core.RQSRequest{User: "bob", Queue: "all.q", Host: "Node1",
+				Resources: map[string]float64{"h_rt": 3600}}
+			_, err := core.EvaluateResourceQuotas(cc, req, nil)
+			Expect(err).To(MatchError(ContainSubstring("no value for num_proc")))
+
+			req.HostValues = map[string]float64{"num_proc": 4}
+			statuses, err := core.EvaluateResourceQuotas(cc, req, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(statuses[0].Instance.Host).To(Equal("Node1"))
+			Expect(statuses[1].Resource).To(Equal("h_rt"))
+			Expect(math.IsInf(statuses[1].Headroom, 1)).To(BeTrue())
+		})
+	})
+})
@@ -276,8 +276,8 @@ func scanUtilbin(root string) string {
 // shareMonRunner field on their own instance without touching a
 // package-level variable (previously this was a var, and concurrent
 // test-level swaps would race).
-func (c *CommandLineQConf) defaultShareMonRunner() (io.Reader, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), c.config.Timeout)
+func (c *CommandLineQConf) defaultShareMonRunner(parent context.Context) (io.Reader, error) {
+	ctx, cancel := context.WithTimeout(parent, c.config.Timeout)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, locateShareMonBinary(), "-c", "1", "-n")
 	var out bytes.Buffer
@@ -0,0 +1,314 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ClassicSpoolDirs are the directories of a cell using classic spooling:
+// the qmaster spool directory holding one file per object, like
+// cqueues/all.q, and the common directory of the cell holding the
+// global configuration, local_conf/ and the scheduler configuration.
+// Every file is looked up in SpoolDir first, then in CommonDir, so a
+// backup copying both into one tree can be read with SpoolDir only.
+type ClassicSpoolDirs struct {
+	SpoolDir  string
+	CommonDir string
+}
+
+// ClassicSpoolDirsFromBootstrap returns the spool directories from the
+// spooling_params of a bootstrap file ("<common dir>;<spool dir>").
+func ClassicSpoolDirsFromBootstrap(b BootstrapFile) (ClassicSpoolDirs, error) {
+	if b.SpoolingMethod != "classic" {
+		return ClassicSpoolDirs{}, fmt.Errorf("spooling method is %q, not classic", b.SpoolingMethod)
+	}
+	common, spool, ok := strings.Cut(b.SpoolingParams, ";")
+	if !ok {
+		return ClassicSpoolDirs{SpoolDir: b.QmasterSpoolDir, CommonDir: common}, nil
+	}
+	return ClassicSpoolDirs{SpoolDir: spool, CommonDir: common}, nil
+}
+
+// ReadClassicSpool reads the configuration objects of a classic spooling
+// directory tree without a running qmaster, for instance to inspect or
+// migrate a cluster from a backup. The share tree is nil when none is
+// spooled. Like GetClusterConfiguration, all maps of the returned
+// configuration are allocated. Feed both into NewInMemoryQConf to query
+// the configuration through the QConf interface.
+func ReadClassicSpool(dirs ClassicSpoolDirs) (ClusterConfig, *StructuredShareTree, error) {
+	var cc ClusterConfig
+	if info, err := os.Stat(dirs.SpoolDir); err != nil {
+		return cc, nil, fmt.Errorf("failed to read spool directory: %w", err)
+	} else if !info.IsDir() {
+		return cc, nil, fmt.Errorf("spool directory %s is not a directory", dirs.SpoolDir)
+	}
+	initClusterConfigMaps(&cc)
+	r := spoolReader{dirs: dirs}
+
+	r.readObjectDirs(&cc, "exec_hosts", true)
+	r.objects("local_conf", func(name string, lines []string) error {
+		hc := ParseHostConfigurationFromLines(lines)
+		hc.Name = name
+		cc.HostConfigurations[name] = hc
+		return nil
+	})
+	r.file([]string{"configuration"}, func(lines []string) error {
+		global := ParseGlobalConfigFromLines(lines)
+		cc.GlobalConfig = &global
+		return nil
+	})
+	r.file([]string{"schedconf", "sched_configuration"}, func(lines []string) error {
+		sched := ParseSchedulerConfigFromLines(lines)
+		cc.SchedulerConfig = &sched
+		return nil
+	})
+	var tree *StructuredShareTree
+	r.file([]string{"sharetree"}, func(lines []string) error {
+		var err error
+		tree, err = ParseShareTreeText(strings.Join(lines, "\n"))
+		if errors.Is(err, ErrNoShareTree) {
+			return nil
+		}
+		return err
+	})
+	if r.err != nil {
+		return ClusterConfig{}, nil, r.err
+	}
+	return cc, tree, nil
+}
+
+// readObjectDirs reads the objects of a directory layout with one
+// directory per object kind holding one file per object, named like the
+// object. Exec hosts are read from execHostDir; the global exec host is
+// skipped when skipGlobal is set.
+func (r *spoolReader) readObjectDirs(cc *ClusterConfig, execHostDir string, skipGlobal bool) {
+	r.objects("cqueues", func(name string, lines []string) error {
+		q := ParseClusterQueueConfigFromLines(lines)
+		q.Name = defaultName(q.Name, name)
+		cc.ClusterQueues[q.Name] = q
+		return nil
+	})
+	r.objects(execHostDir, func(name string, lines []string) error {
+		if name == "template" || (name == "global" && skipGlobal) {
+			return nil
+		}
+		h, err := ParseExecHostConfigFromLines(lines)
+		if err != nil {
+			return err
+		}
+		h.Name = defaultName(h.Name, name)
+		cc.ExecHosts[h.Name] = h
+		return nil
+	})
+	r.objects("hostgroups", func(name string, lines []string) error {
+		hg := ParseHostGroupConfigFromLines(lines)
+		hg.Name = defaultName(hg.Name, name)
+		cc.HostGroups[hg.Name] = hg
+		return nil
+	})
+	r.objects("projects", func(name string, lines []string) error {
+		p := ParseProjectConfigFromLines(lines)
+		p.Name = defaultName(p.Name, name)
+		cc.Projects[p.Name] = p
+		return nil
+	})
+	r.objects("users", func(name string, lines []string) error {
+		u := ParseUserConfigFromLines(lines)
+		u.Name = defaultName(u.Name, name)
+		cc.Users[u.Name] = u
+		return nil
+	})
+	r.objects("usersets", func(name string, lines []string) error {
+		u := ParseUserSetListConfigFromLines(lines)
+		u.Name = defaultName(u.Name, name)
+		cc.UserSetLists[u.Name] = u
+		return nil
+	})
+	r.objects("centry", func(name string, lines []string) error {
+		e, err := ParseComplexEntryConfigFromLines(lines)
+		if err != nil {
+			return err
+		}
+		e.Name = defaultName(e.Name, name)
+		cc.ComplexEntries[e.Name] = e
+		return nil
+	})
+	r.objects("pe", func(name string, lines []string) error {
+		pe, recognized := ParseParallelEnvironmentConfigFromLines(lines)
+		if recognized == 0 {
+			return errors.New("not a parallel environment definition")
+		}
+		pe.Name = defaultName(pe.Name, name)
+		cc.ParallelEnvironments[pe.Name] = pe
+		return nil
+	})
+	r.objects("ckpt", func(name string, lines []string) error {
+		ckpt := ParseCkptInterfaceConfigFromLines(lines)
+		ckpt.Name = defaultName(ckpt.Name, name)
+		cc.CkptInterfaces[ckpt.Name] = ckpt
+		return nil
+	})
+	r.objects("calendars", func(name string, lines []string) error {
+		cal, recognized := ParseCalendarConfigFromLines(lines)
+		if recognized == 0 {
+			return errors.New("not a calendar definition")
+		}
+		cal.Name = defaultName(cal.Name, name)
+		cc.Calendars[cal.Name] = cal
+		return nil
+	})
+	r.objects("resource_quotas", func(name string, lines []string) error {
+		rqs := ParseResourceQuotaSetConfigFromLines(lines)
+		rqs.Name = defaultName(rqs.Name, name)
+		cc.ResourceQuotaSets[rqs.Name] = rqs
+		return nil
+	})
+	r.objects("admin_hosts", func(name string, _ []string) error {
+		cc.AdminHosts = append(cc.AdminHosts, name)
+		return nil
+	})
+	r.objects("submit_hosts", func(name string, _ []string) error {
+		cc.SubmitHosts = append(cc.SubmitHosts, name)
+		return nil
+	})
+	r.file([]string{"managers"}, func(lines []string) error {
+		cc.Managers = spoolNames(lines)
+		return nil
+	})
+	r.file([]string{"operators"}, func(lines []string) error {
+		cc.Operators = spoolNames(lines)
+		return nil
+	})
+}
+
+// spoolReader looks up spool files and remembers the first error.
+type spoolReader struct {
+	dirs ClassicSpoolDirs
+	err  error
+}
+
+// path returns the first existing of name in the spool and the common
+// directory, or "".
+func (r *spoolReader) path(name string) string {
+	for _, dir := range []string{r.dirs.SpoolDir, r.dirs.CommonDir} {
+		if dir == "" {
+			continue
+		}
+		p := filepath.Join(dir, name)
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// objects calls fn for every object file in the directory dir, sorted
+// by name. Hidden files, which qmaster uses while writing, are skipped.
+func (r *spoolReader) objects(dir string, fn func(name string, lines []string) error) {
+	if r.err != nil {
+		return
+	}
+	p := r.path(dir)
+	if p == "" {
+		return
+	}
+	entries, err := os.ReadDir(p)
+	if err != nil {
+		r.err = fmt.Errorf("failed to read %s: %w", p, err)
+		return
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		file := filepath.Join(p, e.Name())
+		lines, err := readSpoolFile(file)
+		if err == nil {
+			err = fn(e.Name(), lines)
+		}
+		if err != nil {
+			r.err = fmt.Errorf("failed to read %s: %w", file, err)
+			return
+		}
+	}
+}
+
+// file calls fn for the first of names which exists.
+func (r *spoolReader) file(names []string, fn func(lines []string) error) {
+	if r.err != nil {
+		return
+	}
+	for _, name := range names {
+		p := r.path(name)
+		if p == "" {
+			continue
+		}
+		lines, err := readSpoolFile(p)
+		if err == nil {
+			err = fn(lines)
+		}
+		if err != nil {
+			r.err = fmt.Errorf("failed to read %s: %w", p, err)
+		}
+		return
+	}
+}
+
+// readSpoolFile returns the lines of a spool file without the comment
+// header qmaster writes, with continuations folded.
+func readSpoolFile(path string) ([]string, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(raw), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "#") {
+			lines = append(lines, line)
+		}
+	}
+	return normalizeConfigLines(lines), nil
+}
+
+// spoolNames returns the names of a list file like managers, one name
+// per line.
+func spoolNames(lines []string) []string {
+	var names []string
+	for _, line := range lines {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			names = append(names, fields[0])
+		}
+	}
+	return names
+}
+
+func defaultName(parsed, fileName string) string {
+	if parsed == "" {
+		return fileName
+	}
+	return parsed
+}
@@ -0,0 +1,178 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+var _ = Describe("ReadClassicSpool", func() {
+
+	var dirs core.ClassicSpoolDirs
+
+	write := func(dir, name, content string) {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(content), 0644)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		root := GinkgoT().TempDir()
+		dirs = core.ClassicSpoolDirs{
+			SpoolDir:  filepath.Join(root, "spool", "qmaster"),
+			CommonDir: filepath.Join(root, "default", "common"),
+		}
+		write(dirs.SpoolDir, "cqueues/all.q", `# Version: 9.0.0
+#
+# DO NOT MODIFY THIS FILE MANUALLY!
+#
+qname                 all.q
+hostlist              @allhosts
+seq_no                0
+slots                 1,[node1=4]
+`)
+		write(dirs.SpoolDir, "cqueues/.all.q.tmp", "qname broken\n")
+		write(dirs.SpoolDir, "exec_hosts/global", "hostname global\n")
+		write(dirs.SpoolDir, "exec_hosts/template", "hostname template\n")
+		write(dirs.SpoolDir, "exec_hosts/node1", `hostname              node1
+complex_values        slots=4,mem_free=1G
+`)
+		write(dirs.SpoolDir, "hostgroups/@allhosts", `group_name @allhosts
+hostlist node1 \
+         node2
+`)
+		write(dirs.SpoolDir, "usersets/arusers", `name    arusers
+type    ACL
+fshare  0
+oticket 0
+entries NONE
+`)
+		write(dirs.SpoolDir, "admin_hosts/master", "")
+		write(dirs.SpoolDir, "submit_hosts/node1", "")
+		write(dirs.SpoolDir, "managers", "# Version: 9.0.0\nroot\nsgeadmin\n")
+		tree, err := os.ReadFile("testdata/share_tree_basic.txt")
+		Expect(err).NotTo(HaveOccurred())
+		write(dirs.SpoolDir, "sharetree", string(tree))
+		write(dirs.CommonDir, "configuration", `execd_spool_dir /opt/cs/default/spool
+mailer          /bin/mail
+`)
+		write(dirs.CommonDir, "local_conf/node1", `mailer /usr/bin/mail
+`)
+		write(dirs.CommonDir, "sched_configuration", `algorithm       default
+max_reservation 10
+`)
+	})
+
+	It("reads all objects of the spool and the common directory", func() {
+		cc, tree, err := core.ReadClassicSpool(dirs)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(cc.ClusterQueues).To(HaveLen(1))
+		Expect(cc.ClusterQueues["all.q"].HostList).To(Equal([]string{"@allhosts"}))
+		Expect(cc.ClusterQueues["all.q"].Slots).To(Equal([]string{"1", "[node1=4]"}))
+		Expect(cc.ExecHosts).To(HaveKey("node1"))
+		Expect(cc.ExecHosts).NotTo(HaveKey("global"))
+		Expect(cc.ExecHosts["node1"].ComplexValues).To(HaveKeyWithValue("mem_free", "1G"))
+		Expect(cc.HostGroups["@allhosts"].Hosts).To(Equal([]string{"node1", "node2"}))
+		Expect(cc.UserSetLists["arusers"].Type).To(Equal("ACL"))
+		Expect(cc.AdminHosts).To(Equal([]string{"master"}))
+		Expect(cc.SubmitHosts).To(Equal([]string{"node1"}))
+		Expect(cc.Managers).To(Equal([]string{"root", "sgeadmin"}))
+
+		Expect(cc.GlobalConfig).NotTo(BeNil())
+		Expect(cc.GlobalConfig.Mailer).To(Equal("/bin/mail"))
+		Expect(cc.HostConfigurations).To(HaveKey("node1"))
+		Expect(cc.HostConfigurations["node1"].Name).To(Equal("node1"))
+		Expect(cc.SchedulerConfig).NotTo(BeNil())
+		Expect(cc.SchedulerConfig.MaxReservation).To(Equal(10))
+
+		// kinds without a spool directory are empty but allocated
+		Expect(cc.Projects).NotTo(BeNil())
+		Expect(cc.Projects).To(BeEmpty())
+
+		Expect(tree).NotTo(BeNil())
+		Expect(tree.Root.Children).To(HaveLen(3))
+	})
+
+	It("can be queried through the in-memory QConf", func() {
+		cc, _, err := core.ReadClassicSpool(dirs)
+		Expect(err).NotTo(HaveOccurred())
+		qc, err := core.NewInMemoryQConf(core.InMemoryQConfConfig{ClusterConfig: cc})
+		Expect(err).NotTo(HaveOccurred())
+		hg, err := qc.ShowHostGroup("@allhosts")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(hg.Hosts).To(ConsistOf("node1", "node2"))
+	})
+
+	It("returns no share tree when none is spooled", func() {
+		Expect(os.Remove(filepath.Join(dirs.SpoolDir, "sharetree"))).To(Succeed())
+		_, tree, err := core.ReadClassicSpool(dirs)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(tree).To(BeNil())
+	})
+
+	It("names the file which cannot be parsed", func() {
+		write(dirs.SpoolDir, "centry/bad", "name bad\nshortcut bad\nurgency high\n")
+		_, _, err := core.ReadClassicSpool(dirs)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring(filepath.Join("centry", "bad")))
+	})
+
+	It("names parallel environment and calendar files which are no definitions", func() {
+		for _, file := range []string{"pe/broken", "calendars/broken"} {
+			write(dirs.SpoolDir, file, "\x00\x00garbage\n")
+			_, _, err := core.ReadClassicSpool(dirs)
+			Expect(err).To(MatchError(ContainSubstring(filepath.FromSlash(file))))
+			Expect(os.Remove(filepath.Join(dirs.SpoolDir, file))).To(Succeed())
+		}
+	})
+
+	It("fails for a missing spool directory", func() {
+		_, _, err := core.ReadClassicSpool(core.ClassicSpoolDirs{SpoolDir: "/nonexistent"})
+		Expect(err).To(HaveOccurred())
+	})
+})
+
+var _ = Describe("ClassicSpoolDirsFromBootstrap", func() {
+
+	It("splits the spooling parameters", func() {
+		dirs, err := core.ClassicSpoolDirsFromBootstrap(core.BootstrapFile{
+			SpoolingMethod: "classic",
+			SpoolingParams: "/opt/cs/default/common;/opt/cs/default/spool/qmaster",
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(dirs.CommonDir).To(Equal("/opt/cs/default/common"))
+		Expect(dirs.SpoolDir).To(Equal("/opt/cs/default/spool/qmaster"))
+	})
+
+	It("rejects berkeleydb spooling", func() {
+		_, err := core.ClassicSpoolDirsFromBootstrap(core.BootstrapFile{
+			SpoolingMethod: "berkeleydb",
+			SpoolingParams: "/opt/cs/default/spool/spooldb",
+		})
+		Expect(err).To(HaveOccurred())
+	})
+})
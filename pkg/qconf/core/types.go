@@ -299,6 +299,9 @@ const ResourceTypeDouble string = "DOUBLE"
 const ResourceTypeMemory string = "MEMORY"
 const ResourceTypeTime string = "TIME"
 const ResourceTypeString string = "STRING"
+const ResourceTypeCString string = "CSTRING"
+const ResourceTypeRestring string = "RESTRING"
+const ResourceTypeHost string = "HOST"
 const ResourceTypeBool string = "BOOL"
 const ResourceTypeRSMAP string = "RSMAP"
 
@@ -27,7 +27,29 @@ import (
 var Apply = core.Apply
 var AddAllEntries = core.AddAllEntries
 var ModifyAllEntries = core.ModifyAllEntries
+var ModifyAllEntriesWithOptions = core.ModifyAllEntriesWithOptions
+var ModifyFields = core.ModifyFields
+var AttributeChanges = core.AttributeChanges
 var DeleteAllEnries = core.DeleteAllEnries
+var ApplyWithReport = core.ApplyWithReport
+var ApplyComparison = core.ApplyComparison
+var Plan = core.Plan
+var ErrStalePlan = core.ErrStalePlan
+var ObjectKinds = core.ObjectKinds
+var ReplaceKinds = core.ReplaceKinds
+
+// Apply report and plan types re-exported from core.
+type ObjectKind = core.ObjectKind
+type ChangeAction = core.ChangeAction
+type AppliedChange = core.AppliedChange
+type RevertFailure = core.RevertFailure
+type ApplyReport = core.ApplyReport
+type ApplyError = core.ApplyError
+type ApplyPlan = core.ApplyPlan
+type PlanOperation = core.PlanOperation
+type FieldChange = core.FieldChange
+type ModifyOptions = core.ModifyOptions
+type AttributeChange = core.AttributeChange
 
 // Re-export additional functions used by tests and consumers.
 var ParseVersionInfo = core.ParseVersionInfo
@@ -27,6 +27,8 @@ import (
 type ClusterConfigComparison = core.ClusterConfigComparison
 type DiffResult[T any] = core.DiffResult[T]
 type DiffResultMap[T any] = core.DiffResultMap[T]
+type FieldDiff = core.FieldDiff
+type JSONPatchOperation = core.JSONPatchOperation
 
 var NewClusterConfigComparison = core.NewClusterConfigComparison
 
@@ -0,0 +1,39 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package qconf
+
+import (
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+// Load formula and threshold evaluation re-exported from core.
+type LoadEvaluator = core.LoadEvaluator
+type HostLoad = core.HostLoad
+type HostLoadRank = core.HostLoadRank
+type LoadThreshold = core.LoadThreshold
+type LoadAlarm = core.LoadAlarm
+type QueueInstanceAlarms = core.QueueInstanceAlarms
+
+var HostLoadValues = core.HostLoadValues
+var ParseLoadAdjustments = core.ParseLoadAdjustments
+var NewLoadEvaluator = core.NewLoadEvaluator
+var ParseLoadThresholds = core.ParseLoadThresholds
+var CheckLoadThresholds = core.CheckLoadThresholds
+var CheckQueueInstanceLoad = core.CheckQueueInstanceLoad
@@ -26,3 +26,13 @@ import (
 // QConf defines the methods for interacting with the Open Cluster Scheduler
 // configuration. This is a type alias to the core QConf interface.
 type QConf = core.QConf
+
+// AttributeReplacer is implemented by the QConf implementations which
+// can run qconf -rattr. This is a type alias to the core
+// AttributeReplacer interface.
+type AttributeReplacer = core.AttributeReplacer
+
+// AttributePurger is implemented by the QConf implementations which can
+// run qconf -purge. This is a type alias to the core AttributePurger
+// interface.
+type AttributePurger = core.AttributePurger
@@ -20,6 +20,8 @@
 package qconf
 
 import (
+	"context"
+
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
 )
 
@@ -34,3 +36,33 @@ type CommandLineQConfConfig = core.CommandLineQConfConfig
 func NewCommandLineQConf(config CommandLineQConfConfig) (*CommandLineQConf, error) {
 	return core.NewCommandLineQConf(config)
 }
+
+// InMemoryQConf is a type alias to the core in-memory implementation
+// which serves the QConf interface without a cluster.
+type InMemoryQConf = core.InMemoryQConf
+
+// InMemoryQConfConfig is a type alias to the core in-memory configuration.
+type InMemoryQConfConfig = core.InMemoryQConfConfig
+
+// NewInMemoryQConf creates a new instance of InMemoryQConf.
+func NewInMemoryQConf(config InMemoryQConfConfig) (*InMemoryQConf, error) {
+	return core.NewInMemoryQConf(config)
+}
+
+// CachingQConf is a type alias to the core QConf decorator which caches
+// the results of the Show* methods.
+type CachingQConf = core.CachingQConf
+
+// CachingQConfConfig is a type alias to the core caching configuration.
+type CachingQConfConfig = core.CachingQConfConfig
+
+// NewCachingQConf creates a new instance of CachingQConf wrapping qc.
+func NewCachingQConf(qc QConf, config CachingQConfConfig) *CachingQConf {
+	return core.NewCachingQConf(qc, config)
+}
+
+// WithContext returns v bound to ctx when it has a WithContext method;
+// see core.WithContext.
+func WithContext[T any](v T, ctx context.Context) T {
+	return core.WithContext(v, ctx)
+}
@@ -0,0 +1,50 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package qconf
+
+import (
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+// Typed queue attribute re-exported from core.
+type QueueAttr[T any] = core.QueueAttr[T]
+
+var ParseQueueListAttr = core.ParseQueueListAttr
+var FormatQueueListAttr = core.FormatQueueListAttr
+var SetQueueAttrOverride = core.SetQueueAttrOverride
+var AddQueueAttrOverrideValue = core.AddQueueAttrOverrideValue
+var DeleteQueueAttrOverrideValue = core.DeleteQueueAttrOverrideValue
+var RemoveQueueAttrOverride = core.RemoveQueueAttrOverride
+
+// Effective queue instance configuration re-exported from core.
+type QueueInstanceConfig = core.QueueInstanceConfig
+
+var ResolveQueueInstance = core.ResolveQueueInstance
+
+// ParseQueueAttr converts the string form of a single-valued queue
+// attribute into a QueueAttr.
+func ParseQueueAttr[T any](values []string, parse func(string) (T, error)) (QueueAttr[T], error) {
+	return core.ParseQueueAttr(values, parse)
+}
+
+// FormatQueueAttr converts a QueueAttr back into its string form.
+func FormatQueueAttr[T any](attr QueueAttr[T], format func(T) string) []string {
+	return core.FormatQueueAttr(attr, format)
+}
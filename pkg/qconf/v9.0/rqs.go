@@ -0,0 +1,45 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2026 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package qconf
+
+import (
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+// Resource quota rule model and evaluator re-exported from core.
+type RQSRule = core.RQSRule
+type RQSFilter = core.RQSFilter
+type RQSFilterEntry = core.RQSFilterEntry
+type RQSLimit = core.RQSLimit
+type RQSRequest = core.RQSRequest
+type RQSRuleInstance = core.RQSRuleInstance
+type RQSUsage = core.RQSUsage
+type RQSLimitStatus = core.RQSLimitStatus
+
+var ParseRQSRule = core.ParseRQSRule
+var ParseRQSRules = core.ParseRQSRules
+var FormatRQSRules = core.FormatRQSRules
+var EvaluateResourceQuotas = core.EvaluateResourceQuotas
+
+// Weighted sum formulas re-exported from core.
+type Formula = core.Formula
+type FormulaTerm = core.FormulaTerm
+
+var ParseFormula = core.ParseFormula
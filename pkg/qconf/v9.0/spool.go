@@ -0,0 +1,39 @@
+/*___INFO__MARK_BEGIN__*/
+/*************************************************************************
+*  Copyright 2024 HPC-Gridware GmbH
+*
+*  Licensed under the Apache License, Version 2.0 (the "License");
+*  you may not use this file except in compliance with the License.
+*  You may obtain a copy of the License at
+*
+*      http://www.apache.org/licenses/LICENSE-2.0
+*
+*  Unless required by applicable law or agreed to in writing, software
+*  distributed under the License is distributed on an "AS IS" BASIS,
+*  WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+*  See the License for the specific language governing permissions and
+*  limitations under the License.
+*
+************************************************************************/
+/*___INFO__MARK_END__*/
+
+package qconf
+
+import (
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/qconf/core"
+)
+
+// Offline reading of classic spool directories re-exported from core.
+type ClassicSpoolDirs = core.ClassicSpoolDirs
+
+var ClassicSpoolDirsFromBootstrap = core.ClassicSpoolDirsFromBootstrap
+var ReadClassicSpool = core.ReadClassicSpool
+
+// Object parsers re-exported from core.
+var ParseClusterQueueConfigFromLines = core.ParseClusterQueueConfigFromLines
+var ParseExecHostConfigFromLines = core.ParseExecHostConfigFromLines
+var ParseHostGroupConfigFromLines = core.ParseHostGroupConfigFromLines
+var ParseHostConfigurationFromLines = core.ParseHostConfigurationFromLines
+var ParseComplexEntryConfigFromLines = core.ParseComplexEntryConfigFromLines
+var ParseCkptInterfaceConfigFromLines = core.ParseCkptInterfaceConfigFromLines
+var ParseSchedulerConfigFromLines = core.ParseSchedulerConfigFromLines
@@ -64,6 +64,9 @@ const ResourceTypeDouble = core.ResourceTypeDouble
 const ResourceTypeMemory = core.ResourceTypeMemory
 const ResourceTypeTime = core.ResourceTypeTime
 const ResourceTypeString = core.ResourceTypeString
+const ResourceTypeCString = core.ResourceTypeCString
+const ResourceTypeRestring = core.ResourceTypeRestring
+const ResourceTypeHost = core.ResourceTypeHost
 const ResourceTypeBool = core.ResourceTypeBool
 const ResourceTypeRSMAP = core.ResourceTypeRSMAP
 
@@ -115,6 +118,26 @@ const (
 	ShareCodeNilNode                = core.ShareCodeNilNode
 )
 
+// Cluster configuration validation re-exports. Keep the ConfigCode*
+// constants in lock-step with core/cluster_config_validate.go.
+type ConfigIssue = core.ConfigIssue
+
+var ValidateClusterConfig = core.ValidateClusterConfig
+
+const (
+	ConfigCodeUnknownPE        = core.ConfigCodeUnknownPE
+	ConfigCodeUnknownCkpt      = core.ConfigCodeUnknownCkpt
+	ConfigCodeUnknownCalendar  = core.ConfigCodeUnknownCalendar
+	ConfigCodeUnknownUserSet   = core.ConfigCodeUnknownUserSet
+	ConfigCodeUnknownProject   = core.ConfigCodeUnknownProject
+	ConfigCodeUnknownQueue     = core.ConfigCodeUnknownQueue
+	ConfigCodeUnknownHostGroup = core.ConfigCodeUnknownHostGroup
+	ConfigCodeUnknownComplex   = core.ConfigCodeUnknownComplex
+	ConfigCodeHostGroupCycle   = core.ConfigCodeHostGroupCycle
+	ConfigCodeSelfSubordinate  = core.ConfigCodeSelfSubordinate
+	ConfigCodeNameMismatch     = core.ConfigCodeNameMismatch
+)
+
 const ConsumableYES = core.ConsumableYES
 const ConsumableNO = core.ConsumableNO
 const ConsumableJOB = core.ConsumableJOB
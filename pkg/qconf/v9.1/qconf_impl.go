@@ -20,6 +20,7 @@
 package qconf
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -46,6 +47,13 @@ func NewCommandLineQConf(config CommandLineQConfConfig) (*CommandLineQConf, erro
 	return &CommandLineQConf{CommandLineQConf: c}, nil
 }
 
+// WithContext returns a copy of c whose commands run under ctx; see
+// core.CommandLineQConf.WithContext. It is overridden so the copy keeps
+// the v9.1 methods.
+func (c *CommandLineQConf) WithContext(ctx context.Context) *CommandLineQConf {
+	return &CommandLineQConf{CommandLineQConf: c.CommandLineQConf.WithContext(ctx)}
+}
+
 // ShowGlobalConfiguration returns the global configuration with v9.1-specific
 // fields parsed. It reuses the core parser for base fields and then promotes
 // v9.1-only keys out of ExtraFields into their typed slots so the residual
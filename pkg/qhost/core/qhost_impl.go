@@ -23,6 +23,7 @@ import (
 	"fmt"
 	"os/exec"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
 )
 
@@ -66,15 +67,8 @@ func (q *QHostImpl) NativeSpecification(args []string) (string, error) {
 	command := exec.Command(q.config.Executable, args...)
 	out, err := command.Output()
 	if err != nil {
-		ee, ok := err.(*exec.ExitError)
-		if ok {
-			if !ee.Success() {
-				return "", fmt.Errorf("qhost command failed with exit code %d",
-					ee.ExitCode())
-			}
-			return "", nil
-		}
-		return "", fmt.Errorf("failed to get output of qhost: %w", err)
+		return "", fmt.Errorf("failed to get output of qhost: %w",
+			qerror.FromOutput(out, err))
 	}
 	return string(out), nil
 }
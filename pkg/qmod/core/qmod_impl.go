@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
 )
 
@@ -73,8 +74,7 @@ func (c *CommandLineQMod) RunCommand(args ...string) (string, error) {
 		<-time.After(c.config.DelayAfter)
 	}
 	if err != nil {
-		return out.String(), fmt.Errorf("failed to run command (%s): %v",
-			out.String(), err)
+		return out.String(), qerror.New(out.String(), err)
 	}
 	return out.String(), nil
 }
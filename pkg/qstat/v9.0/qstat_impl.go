@@ -26,6 +26,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
 )
 
@@ -136,15 +137,8 @@ func (q *QStatImpl) NativeSpecification(args []string) (string, error) {
 	command := exec.Command(q.config.Executable, args...)
 	out, err := command.Output()
 	if err != nil {
-		// convert error in exit error
-		ee, ok := err.(*exec.ExitError)
-		if ok {
-			if !ee.Success() {
-				return "", fmt.Errorf("qstat command failed with exit code %d", ee.ExitCode())
-			}
-			return "", nil
-		}
-		return "", fmt.Errorf("failed to get output of qstat: %w", err)
+		return "", fmt.Errorf("failed to get output of qstat: %w",
+			qerror.FromOutput(out, err))
 	}
 	return string(out), nil
 }
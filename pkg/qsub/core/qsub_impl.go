@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/qerror"
 	"github.com/hpc-gridware/go-clusterscheduler/pkg/helper/validate"
 )
 
@@ -76,7 +77,7 @@ func (c *QSubClient) SubmitWithNativeSpecification(ctx context.Context, args []s
 	cmd := exec.CommandContext(ctx, c.config.QsubPath, args...)
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("qsub error: %v, output: %s", err, string(output))
+		return "", fmt.Errorf("qsub error: %w", qerror.New(string(output), err))
 	}
 	return string(output), nil
 }